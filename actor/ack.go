@@ -0,0 +1,49 @@
+package actor
+
+import "context"
+
+// AckMode requires the actor to acknowledge each message via Ack. A message
+// is only removed from the actor once it has been acknowledged; those which
+// aren't - including the message being handled should the actor fail - are
+// redelivered when the actor next restarts, ahead of any messages waiting in
+// its mailbox. This suits actors fronting durable queues, which mustn't lose
+// in-flight work.
+//
+// A message sent by Ask is replied to once it's acknowledged, should Handle
+// fail to reply.
+func AckMode() SpawnOption {
+	return func(o *spawnOptions) {
+		o.acking = true
+	}
+}
+
+// Ack acknowledges the message being handled, given the context passed to
+// Handle; it returns false should the context not belong to an actor, or
+// the actor not have been spawned with AckMode.
+func Ack(ctx context.Context) bool {
+	cur, ok := ctx.Value(currentKey{}).(*current)
+	if !ok || !cur.runtime.acking {
+		return false
+	}
+
+	cur.runtime.acked = true
+	return true
+}
+
+// settle retains a message which has been handled, should it not have been
+// acknowledged; otherwise it's acknowledged to the actor's MailboxStore,
+// should it have one.
+func (r *runtime) settle(msg interface{}) {
+	if r.acking && !r.acked {
+		r.unacked = append(r.unacked, msg)
+		return
+	}
+
+	acknowledge(msg)
+}
+
+// redeliverUnacked queues the unacknowledged messages for redelivery.
+func (r *runtime) redeliverUnacked() {
+	r.replay = append(r.unacked, r.replay...)
+	r.unacked = nil
+}
@@ -0,0 +1,64 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_AckModeMustRedeliverUnacknowledgedMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan interface{}, 8)
+	ref, _ := sys.Spawn("consumer", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		select {
+		case handled <- msg:
+		default:
+		}
+
+		switch msg {
+		case "crash":
+			panic("crash")
+		case "unacked":
+			return nil
+		}
+
+		Ack(ctx)
+		return nil
+	}), AckMode())
+
+	ref.Tell("acked")
+	ref.Tell("unacked")
+	ref.Tell("crash")
+
+	expect := func(expected ...string) {
+		for _, e := range expected {
+			select {
+			case msg := <-handled:
+				if msg != e {
+					t.Error("expected messages to be delivered in order", msg, e)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected a delivery of", e)
+			}
+		}
+	}
+
+	// The unacknowledged messages are redelivered upon each restart.
+	expect("acked", "unacked", "crash", "unacked", "crash", "unacked", "crash")
+
+	sys.Stop("consumer")
+	if Ack(context.Background()) {
+		t.Error("expected Ack to fail outside of an actor")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,369 @@
+// Package actor provides supervised actors: long-lived workers which handle
+// the messages delivered to their mailbox one at a time.
+//
+// A System owns a root Supervisor, under which each spawned actor runs as a
+// worker; should an actor panic, or return an error from Handle, then it's
+// restarted according to the System's RestartPolicy, whilst its mailbox -
+// and any messages waiting within it - are retained.
+//
+//	sys, err := actor.NewSystem(ctx)
+//	ref, err := sys.Spawn("billing", &Billing{})
+//	defer sys.Shutdown(ctx)
+//
+//	ref.Tell(Invoice{Amount: 100})
+//
+// Actors which handle a single type of message can instead be spawned with
+// SpawnTyped, giving a TypedRef which only accepts messages of that type.
+//
+// As in OTP, an actor may Monitor another to be sent a Down message whenever
+// it exits, or Link to another so that the failure of either restarts both.
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrRestartRequested is reported when an actor is restarted due to the
+// Restart ControlMessage.
+var ErrRestartRequested = errors.New("actor: restart requested")
+
+// DefaultMailboxSize is the number of messages a mailbox can hold before
+// senders are blocked.
+const DefaultMailboxSize = 64
+
+// Actor handles the messages delivered to its mailbox. Messages are handled
+// one at a time, so an Actor needn't synchronise access to its own state;
+// returning an error causes the Actor to be restarted.
+type Actor interface {
+	Handle(ctx context.Context, msg interface{}) error
+}
+
+// Initialiser may be implemented by an Actor to prepare its state as each
+// run begins, including upon being restarted; returning an error fails the
+// run, and so restarts the Actor.
+type Initialiser interface {
+	Init(ctx context.Context) error
+}
+
+// ActorFunc adapts a function to the Actor interface.
+type ActorFunc func(ctx context.Context, msg interface{}) error
+
+// Handle calls f.
+func (f ActorFunc) Handle(ctx context.Context, msg interface{}) error {
+	return f(ctx, msg)
+}
+
+// ControlMessage is an instruction to the runtime of an actor, rather than
+// to the Actor itself; control messages are delivered ahead of any ordinary
+// messages waiting in the mailbox.
+type ControlMessage int
+
+const (
+	// Restart causes the actor to be restarted, as though it had failed.
+	Restart ControlMessage = iota + 1
+	// Stop causes the actor to stop handling messages; actors spawned by a
+	// System are also removed from it.
+	Stop
+)
+
+// ActorWorker adapts an Actor to a Supervisable which handles the messages
+// delivered to the Mailbox. Upon receiving Stop the Supervisable ceases to
+// handle messages, but doesn't exit until its context is cancelled - as the
+// Supervisor would otherwise restart it.
+func ActorWorker(a Actor, mailbox *Mailbox) supervisor.Supervisable {
+	return (&runtime{actor: a, mailbox: mailbox}).run
+}
+
+// runtime runs an Actor upon its Mailbox.
+type runtime struct {
+	actor   Actor
+	mailbox *Mailbox
+	stopped func()
+	// ref is the handle of an actor spawned by a System, which is notified
+	// of the actor's exits.
+	ref *ActorRef
+
+	// stash holds the messages set aside via Stash, whilst replay holds
+	// those which have since been unstashed; both are only accessed by the
+	// actor's own goroutine, and persist across restarts.
+	stash  []interface{}
+	replay []interface{}
+
+	// behaviours is the stack of Actors set via Become, which is reset upon
+	// each run.
+	behaviours []Actor
+
+	// inflight is the message being handled, should holding be set; it's
+	// retained should the actor panic, in which case failures counts the
+	// consecutive panics it has caused. See PoisonThreshold.
+	inflight interface{}
+	holding  bool
+	failures int
+	poison   int
+	retries  *supervisor.RetryPolicy
+
+	// unacked holds the messages handled without being acknowledged; see
+	// AckMode.
+	acking  bool
+	acked   bool
+	unacked []interface{}
+
+	batchSize   int
+	batchWindow time.Duration
+	// window is the timer bounding the collection of a batch, which is
+	// reused by each batch.
+	window supervisor.Timer
+
+	deadline      time.Duration
+	deadlineFails bool
+
+	// idle is the IdleTimeout, upon which passivated is called.
+	idle       time.Duration
+	passivated func()
+
+	interceptors []Interceptor
+	// latency is how long the message being handled waited in the mailbox,
+	// should it have been received from there.
+	latency time.Duration
+	// clock is the Clock of the Supervisor running the actor, which times
+	// its retries, batches and idleness.
+	clock supervisor.Clock
+}
+
+func (r *runtime) run(ctx context.Context, done chan struct{}) {
+	defer supervisor.Recover(ctx, done)
+	defer func() {
+		if reason := recover(); reason != nil {
+			err := fmt.Errorf("%w: %v", ErrActorFailed, reason)
+			if r.holding {
+				if msg, attempt := unretried(r.inflight); r.retry(msg, attempt, err) {
+					r.inflight, r.holding = nil, false
+				} else {
+					r.failures++
+				}
+			}
+
+			r.failed(ctx, err, 0)
+			panic(reason)
+		}
+	}()
+
+	r.clock = supervisor.ClockFrom(ctx)
+
+	var exits chan linkExit
+	if r.ref != nil {
+		exits = r.ref.exits
+		ctx = context.WithValue(ctx, selfKey{}, r.ref)
+	}
+
+	r.unstash()
+	r.redeliver()
+	r.redeliverUnacked()
+	r.behaviours = nil
+
+	if init, ok := r.actor.(Initialiser); ok {
+		if err := init.Init(ctx); err != nil {
+			r.failed(ctx, err, 0)
+			return
+		}
+	}
+
+	idleTimer, idle := r.idleTimer()
+	if idleTimer != nil {
+		defer idleTimer.Stop()
+	}
+
+	supervisor.ObserveQueue(ctx, r.mailbox.depth)
+	supervisor.Ready(ctx)
+	for {
+		// An actor which is being stopped doesn't handle any further
+		// messages, leaving them to be routed to the dead letters.
+		if ctx.Err() != nil {
+			r.terminate(ctx)
+			return
+		}
+
+		// Control messages, and exits from linked actors, are checked first
+		// so they take precedence over a backlog of ordinary messages.
+		select {
+		case exit := <-exits:
+			r.failed(ctx, exit.reason, exit.wave)
+			return
+		case msg := <-r.mailbox.Control:
+			if !r.control(ctx, msg) {
+				return
+			}
+			continue
+		default:
+		}
+
+		// Followed by unstashed messages, and then High priority messages
+		// should the mailbox have them.
+		if msg, ok := r.next(); ok {
+			if !r.process(ctx, msg) {
+				return
+			}
+			continue
+		}
+
+		select {
+		case msg := <-r.mailbox.Urgent:
+			if !r.process(ctx, r.dequeued(msg)) {
+				return
+			}
+			continue
+		default:
+		}
+
+		r.resetIdle(idleTimer)
+		select {
+		case <-ctx.Done():
+			r.terminate(ctx)
+			return
+		case <-idle:
+			r.passivate(ctx)
+			return
+		case exit := <-exits:
+			r.failed(ctx, exit.reason, exit.wave)
+			return
+		case msg := <-r.mailbox.Control:
+			if !r.control(ctx, msg) {
+				return
+			}
+		case msg := <-r.mailbox.Urgent:
+			if !r.process(ctx, r.dequeued(msg)) {
+				return
+			}
+		case msg := <-r.mailbox.Messages:
+			if handled, ok := r.batch(ctx, msg); handled {
+				if !ok {
+					return
+				}
+				continue
+			}
+
+			if !r.process(ctx, r.dequeued(msg)) {
+				return
+			}
+		}
+	}
+}
+
+// process handles a message, returning whether the actor should continue
+// handling messages.
+func (r *runtime) process(ctx context.Context, msg interface{}) bool {
+	r.inflight, r.holding, r.acked = msg, true, false
+	msg, attempt := unretried(msg)
+	err := r.handle(ctx, msg)
+	held, holding := r.inflight, r.holding
+	r.inflight, r.holding, r.failures = nil, false, 0
+
+	if err != nil {
+		// A message whose handling was interrupted by the actor being
+		// stopped is left unacknowledged, so a DurableMailbox redelivers it.
+		if !r.retry(msg, attempt, err) && holding && ctx.Err() == nil {
+			r.settle(held)
+		}
+
+		r.failed(ctx, err, 0)
+		return false
+	}
+
+	if holding {
+		r.settle(held)
+	}
+
+	return true
+}
+
+// dequeued unwraps a message received from the mailbox, recording how long
+// it waited there.
+func (r *runtime) dequeued(msg interface{}) interface{} {
+	msg, latency, measured := r.mailbox.recordDequeue(msg)
+	r.latency = latency
+	if measured && r.ref != nil && r.ref.system.metrics != nil {
+		r.ref.system.metrics.MessageDequeued(r.ref.name, latency)
+	}
+
+	return msg
+}
+
+// failed reports the reason for the actor's failure to the Supervisor, and
+// notifies any monitors and links.
+func (r *runtime) failed(ctx context.Context, reason error, wave uint64) {
+	supervisor.ReportError(ctx, reason)
+	if r.ref != nil {
+		r.ref.exited(reason, wave)
+	}
+}
+
+// handle passes a message to the Actor, unwrapping it should it have been
+// sent within an Envelope; the Envelope is then always responded to, even
+// should the Actor panic, unless the message was stashed or forwarded.
+func (r *runtime) handle(ctx context.Context, msg interface{}) (err error) {
+	cur := &current{runtime: r, msg: msg, latency: r.latency}
+	r.latency = 0
+	msg = undurable(msg)
+	parent := ctx
+	ctx, cancel := r.withDeadline(context.WithValue(ctx, currentKey{}, cur))
+	defer cancel()
+
+	env, ok := msg.(*Envelope)
+	if !ok {
+		err = r.intercepted(r.behaviour()).Handle(ctx, msg)
+		r.checkDeadline(parent, ctx)
+		return err
+	}
+
+	defer func() {
+		if reason := recover(); reason != nil {
+			// A message which will be redelivered is responded to once it's
+			// either handled or dead-lettered.
+			if !cur.stashed && !cur.forwarded && !r.redelivers(true) {
+				env.respond(Response{Err: fmt.Errorf("%w: %v", ErrActorFailed, reason)})
+			}
+			panic(reason)
+		}
+	}()
+
+	err = r.intercepted(r.behaviour()).Handle(context.WithValue(ctx, envelopeKey{}, env), env.Message)
+	r.checkDeadline(parent, ctx)
+
+	switch {
+	case cur.stashed, cur.forwarded:
+	case err != nil:
+		if !r.redelivers(false) {
+			env.respond(Response{Err: err})
+		}
+	case r.acking && !r.acked:
+	default:
+		env.respond(Response{Err: ErrNoReply})
+	}
+
+	return err
+}
+
+// control acts upon a ControlMessage, returning whether the actor should
+// continue handling messages.
+func (r *runtime) control(ctx context.Context, msg ControlMessage) bool {
+	switch msg {
+	case Restart:
+		r.failed(ctx, ErrRestartRequested, 0)
+		return false
+	case Stop:
+		r.terminate(ctx)
+		if r.stopped != nil {
+			r.stopped()
+		}
+
+		<-ctx.Done()
+		return false
+	}
+
+	return true
+}
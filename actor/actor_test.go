@@ -0,0 +1,55 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func Test_ActorWorkerMustPrioritiseControlMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	handled := make(chan interface{}, 10)
+	mailbox := NewMailbox(10)
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{
+			Name: "echo",
+			Worker: ActorWorker(ActorFunc(func(ctx context.Context, msg interface{}) error {
+				handled <- msg
+				return nil
+			}), mailbox),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mailbox.Messages <- "first"
+	mailbox.Messages <- "second"
+	mailbox.Control <- Restart
+
+	s.Run()
+	<-time.After(time.Millisecond * 50)
+
+	if workers := s.WorkerInfo("echo"); len(workers) != 1 || workers[0].Restarts != 1 {
+		t.Error("expected the actor to restart ahead of handling its messages", workers)
+	}
+
+	if len(handled) != 2 || <-handled != "first" {
+		t.Error("expected messages to be retained across the restart")
+	}
+
+	mailbox.Control <- Stop
+	mailbox.Messages <- "ignored"
+	<-time.After(time.Millisecond * 20)
+
+	if len(handled) != 1 {
+		t.Error("expected a stopped actor to not handle messages")
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,215 @@
+// Package actortest provides a TestKit, in the style of Akka's, for testing
+// actor interactions at the level of the messages they exchange. A Probe is
+// an actor which records every message it receives, allowing a test to
+// assert upon them, and whose replies may be scripted:
+//
+//	kit := actortest.NewTestKit(t)
+//	billing := kit.Probe("billing")
+//	billing.ReplyWith(func(msg interface{}) (interface{}, error) {
+//		return Receipt{}, nil
+//	})
+//
+//	checkout, _ := kit.System.Spawn("checkout", NewCheckout(billing.Ref()))
+//	checkout.Tell(Purchase{Amount: 100})
+//
+//	billing.ExpectMsg(Invoice{Amount: 100})
+//	billing.ExpectNoMsg(100 * time.Millisecond)
+package actortest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.fergus.london/go-supervise/actor"
+)
+
+// DefaultTimeout is how long a Probe waits for an expected message.
+const DefaultTimeout = 3 * time.Second
+
+// TestKit owns a System for the duration of a test, which is shut down once
+// the test completes.
+type TestKit struct {
+	T      testing.TB
+	System *actor.System
+}
+
+// NewTestKit returns a TestKit with a running System, spawned with the
+// given options; the test fails should the System not start.
+func NewTestKit(t testing.TB, opts ...actor.SystemOption) *TestKit {
+	t.Helper()
+
+	sys, err := actor.NewSystem(context.Background(), opts...)
+	if err != nil {
+		t.Fatalf("actortest: failed to start system: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+		defer cancel()
+
+		sys.Shutdown(ctx)
+		sys.Supervisor().Wait()
+	})
+
+	return &TestKit{T: t, System: sys}
+}
+
+// Received is a message received by a Probe.
+type Received struct {
+	// Message is the message itself.
+	Message interface{}
+	// Envelope is the Envelope the message was sent within, if any - such
+	// as by Ask, Send or Request.
+	Envelope *actor.Envelope
+}
+
+// Probe is an actor which records the messages it receives.
+type Probe struct {
+	t        testing.TB
+	ref      *actor.ActorRef
+	received chan Received
+
+	mu      sync.Mutex
+	reply   func(msg interface{}) (interface{}, error)
+	scripts []interface{}
+}
+
+// Probe spawns a Probe within the TestKit's System, with the given name and
+// options; the test fails should it not be spawned.
+func (k *TestKit) Probe(name string, opts ...actor.SpawnOption) *Probe {
+	k.T.Helper()
+
+	p := &Probe{t: k.T, received: make(chan Received, actor.DefaultMailboxSize)}
+	ref, err := k.System.Spawn(name, actor.ActorFunc(p.handle), opts...)
+	if err != nil {
+		k.T.Fatalf("actortest: failed to spawn probe %q: %v", name, err)
+	}
+
+	p.ref = ref
+	return p
+}
+
+// Ref returns the ActorRef of the Probe, to be given to the actors under
+// test.
+func (p *Probe) Ref() *actor.ActorRef {
+	return p.ref
+}
+
+// ReplyWith sets the function which replies to the messages received by the
+// Probe which expect a reply, such as those sent by Ask; returning an error
+// replies with it via ReplyError. Scripted replies take precedence; see
+// Script.
+func (p *Probe) ReplyWith(fn func(msg interface{}) (interface{}, error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.reply = fn
+}
+
+// Script queues replies to the next messages received by the Probe which
+// expect a reply, in order; a reply which is an error is replied with via
+// ReplyError.
+func (p *Probe) Script(replies ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.scripts = append(p.scripts, replies...)
+}
+
+func (p *Probe) handle(ctx context.Context, msg interface{}) error {
+	env, _ := actor.CurrentEnvelope(ctx)
+	if env != nil && (env.ReplyTo != nil || env.ReplyToRef != nil) {
+		p.respond(ctx, msg)
+	}
+
+	p.received <- Received{Message: msg, Envelope: env}
+	return nil
+}
+
+// respond replies to a message with the next scripted reply, or via the
+// function set by ReplyWith; otherwise the message is replied to with
+// actor.ErrNoReply on the Probe's behalf.
+func (p *Probe) respond(ctx context.Context, msg interface{}) {
+	p.mu.Lock()
+	var (
+		value interface{}
+		err   error
+		ok    = true
+	)
+	switch {
+	case len(p.scripts) > 0:
+		value, p.scripts = p.scripts[0], p.scripts[1:]
+		err, _ = value.(error)
+	case p.reply != nil:
+		value, err = p.reply(msg)
+	default:
+		ok = false
+	}
+	p.mu.Unlock()
+
+	switch {
+	case !ok:
+	case err != nil:
+		actor.ReplyError(ctx, err)
+	default:
+		actor.Reply(ctx, value)
+	}
+}
+
+// Receive waits for the next message received by the Probe, failing the
+// test should none be received within the timeout.
+func (p *Probe) Receive(timeout time.Duration) Received {
+	p.t.Helper()
+
+	select {
+	case r := <-p.received:
+		return r
+	case <-time.After(timeout):
+		p.t.Fatalf("actortest: %q received no message within %s", p.ref.Name(), timeout)
+		return Received{}
+	}
+}
+
+// ExpectMsg waits for the next message received by the Probe, within the
+// DefaultTimeout, failing the test should it not equal the expected message
+// as per reflect.DeepEqual. It returns the Envelope the message was sent
+// within, if any.
+func (p *Probe) ExpectMsg(expected interface{}) *actor.Envelope {
+	p.t.Helper()
+
+	r := p.Receive(DefaultTimeout)
+	if !reflect.DeepEqual(r.Message, expected) {
+		p.t.Fatalf("actortest: %q expected %#v, but received %#v", p.ref.Name(), expected, r.Message)
+	}
+
+	return r.Envelope
+}
+
+// ExpectMsgType waits for the next message received by the Probe, within
+// the DefaultTimeout, failing the test should it not be of type T.
+func ExpectMsgType[T any](p *Probe) T {
+	p.t.Helper()
+
+	r := p.Receive(DefaultTimeout)
+	msg, ok := r.Message.(T)
+	if !ok {
+		p.t.Fatalf("actortest: %q expected a %T, but received %#v", p.ref.Name(), msg, r.Message)
+	}
+
+	return msg
+}
+
+// ExpectNoMsg fails the test should the Probe receive a message within the
+// duration.
+func (p *Probe) ExpectNoMsg(within time.Duration) {
+	p.t.Helper()
+
+	select {
+	case r := <-p.received:
+		p.t.Fatalf("actortest: %q expected no message, but received %#v", p.ref.Name(), r.Message)
+	case <-time.After(within):
+	}
+}
@@ -0,0 +1,52 @@
+package actortest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.fergus.london/go-supervise/actor"
+	"go.uber.org/goleak"
+)
+
+type invoice struct {
+	Amount int
+}
+
+func Test_ProbeMustRecordMessagesAndScriptReplies(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("kit", func(t *testing.T) {
+		kit := NewTestKit(t)
+		billing := kit.Probe("billing")
+		billing.Script(42, errors.New("declined"))
+		billing.ReplyWith(func(msg interface{}) (interface{}, error) {
+			return msg.(invoice).Amount * 2, nil
+		})
+
+		billing.Ref().Tell(invoice{Amount: 10})
+		if env := billing.ExpectMsg(invoice{Amount: 10}); env != nil {
+			t.Error("expected no envelope for a told message", env)
+		}
+
+		if v, err := actor.Ask[int](context.Background(), billing.Ref(), invoice{Amount: 1}); err != nil || v != 42 {
+			t.Error("expected the scripted reply", v, err)
+		}
+		if env := billing.ExpectMsg(invoice{Amount: 1}); env == nil || env.ID == "" {
+			t.Error("expected the envelope to be recorded", env)
+		}
+
+		if _, err := actor.Ask[int](context.Background(), billing.Ref(), invoice{Amount: 2}); err == nil || err.Error() != "declined" {
+			t.Error("expected the scripted error", err)
+		}
+		ExpectMsgType[invoice](billing)
+
+		if v, err := actor.Ask[int](context.Background(), billing.Ref(), invoice{Amount: 3}); err != nil || v != 6 {
+			t.Error("expected the reply function once the script is exhausted", v, err)
+		}
+		billing.ExpectMsg(invoice{Amount: 3})
+
+		billing.ExpectNoMsg(time.Millisecond * 20)
+	})
+}
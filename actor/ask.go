@@ -0,0 +1,159 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTimeout is returned by Ask when its context's deadline passes
+	// before the actor replies.
+	ErrTimeout = errors.New("actor: ask timed out")
+	// ErrNoReply is returned by Ask when the actor handles the message
+	// without replying to it.
+	ErrNoReply = errors.New("actor: no reply")
+	// ErrUnexpectedReply is returned by Ask when the actor replies with a
+	// value of a different type to that expected.
+	ErrUnexpectedReply = errors.New("actor: unexpected reply")
+	// ErrActorFailed is returned by Ask when the actor panics whilst
+	// handling the message.
+	ErrActorFailed = errors.New("actor: failed handling message")
+)
+
+// Envelope carries a message along with the destination for its reply, and
+// metadata describing it. Upon receiving an Envelope, an actor's runtime
+// passes the Message to Handle, which can respond via Reply or ReplyError;
+// should Handle return without responding then a reply is sent on its
+// behalf, carrying either the error it returned or ErrNoReply. The Envelope
+// itself is available to Handle via CurrentEnvelope.
+//
+// Envelopes built by NewEnvelope - as used by Ask and Send - have their
+// metadata populated from the message being handled, if any, allowing a
+// chain of messages to be traced.
+type Envelope struct {
+	// Message is the message to be handled.
+	Message interface{}
+	// ReplyTo receives the reply to the message; it should be buffered, as
+	// the actor won't wait for it to be received. It may be nil should no
+	// reply be expected.
+	ReplyTo chan<- Response
+	// ReplyToRef, if given, is an actor to which the reply is also sent as
+	// a Response message; see Request.
+	ReplyToRef *ActorRef
+
+	// ID uniquely identifies the message.
+	ID string
+	// CorrelationID identifies the chain of messages the message belongs
+	// to; it's the ID of the first message in the chain.
+	CorrelationID string
+	// CausationID is the ID of the message being handled when this message
+	// was sent, if any.
+	CausationID string
+	// Sender is the actor which sent the message, if any.
+	Sender *ActorRef
+	// Created is when the Envelope was created.
+	Created time.Time
+	// Headers carries arbitrary metadata, such as tracing context.
+	Headers map[string]string
+
+	once sync.Once
+}
+
+// Response is the reply to a message delivered within an Envelope.
+type Response struct {
+	// Value is the value replied with.
+	Value interface{}
+	// Err is the error replied with, if any.
+	Err error
+	// InReplyTo is the ID of the Envelope being replied to.
+	InReplyTo string
+}
+
+type envelopeKey struct{}
+
+// respond delivers the Response, should the Envelope not have been
+// responded to already; it returns whether the Response was delivered.
+func (env *Envelope) respond(resp Response) bool {
+	sent := false
+	env.once.Do(func() {
+		resp.InReplyTo = env.ID
+		select {
+		case env.ReplyTo <- resp:
+		default:
+		}
+
+		if env.ReplyToRef != nil {
+			env.ReplyToRef.Tell(resp)
+		}
+		sent = true
+	})
+
+	return sent
+}
+
+// Reply responds to the message being handled with a value; it returns
+// false should the message not have been sent within an Envelope, or have
+// already been responded to.
+func Reply(ctx context.Context, value interface{}) bool {
+	env, ok := ctx.Value(envelopeKey{}).(*Envelope)
+	return ok && env.respond(Response{Value: value})
+}
+
+// ReplyError responds to the message being handled with an error, without
+// the actor failing; it returns false under the same conditions as Reply.
+func ReplyError(ctx context.Context, err error) bool {
+	env, ok := ctx.Value(envelopeKey{}).(*Envelope)
+	return ok && env.respond(Response{Err: err})
+}
+
+// Ask sends a message to the actor and waits for its reply, which must be
+// of type T. Should the context's deadline pass first then ErrTimeout is
+// returned, whilst should it be cancelled then its error is returned.
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Second)
+//	defer cancel()
+//	balance, err := actor.Ask[int](ctx, ref, GetBalance{Account: id})
+func Ask[T any](ctx context.Context, ref *ActorRef, msg interface{}) (T, error) {
+	var zero T
+	resp, err := ref.ask(ctx, newEnvelopeTo(ctx, ref, msg))
+	if err != nil {
+		return zero, err
+	}
+
+	value, ok := resp.Value.(T)
+	if !ok && resp.Value != nil {
+		return zero, fmt.Errorf("%w: %q replied with %T, expected %T", ErrUnexpectedReply, ref.name, resp.Value, zero)
+	}
+
+	return value, nil
+}
+
+// ask sends the Envelope to the actor and waits for its Response, returning
+// the error it carries, if any.
+func (ref *ActorRef) ask(ctx context.Context, env *Envelope) (Response, error) {
+	replies := make(chan Response, 1)
+	env.ReplyTo = replies
+	if err := ref.tell(ctx, env); err != nil {
+		return Response{}, ref.askError(ctx, err)
+	}
+
+	select {
+	case resp := <-replies:
+		return resp, resp.Err
+	case <-ctx.Done():
+		return Response{}, ref.askError(ctx, ctx.Err())
+	case <-ref.stopped:
+		return Response{}, ref.errStopped()
+	}
+}
+
+func (ref *ActorRef) askError(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %q", ErrTimeout, ref.name)
+	}
+
+	return err
+}
@@ -0,0 +1,72 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_AskMustReturnTypedReplies(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := sys.Spawn("calculator", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		switch msg {
+		case "double":
+			Reply(ctx, 42)
+		case "string":
+			Reply(ctx, "42")
+		case "invalid":
+			ReplyError(ctx, errors.New("invalid"))
+		case "panic":
+			panic("testing")
+		case "slow":
+			<-time.After(time.Millisecond * 50)
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if n, err := Ask[int](ctx, ref, "double"); n != 42 || err != nil {
+		t.Error("expected a typed reply", n, err)
+	}
+
+	if _, err := Ask[int](ctx, ref, "string"); !errors.Is(err, ErrUnexpectedReply) {
+		t.Error("expected a reply of the wrong type to be rejected", err)
+	}
+
+	if _, err := Ask[int](ctx, ref, "invalid"); err == nil || err.Error() != "invalid" {
+		t.Error("expected the actor's error", err)
+	}
+
+	if _, err := Ask[int](ctx, ref, "panic"); !errors.Is(err, ErrActorFailed) {
+		t.Error("expected the panic to be reported", err)
+	}
+
+	if _, err := Ask[int](ctx, ref, "ignored"); !errors.Is(err, ErrNoReply) {
+		t.Error("expected an unanswered message to be reported", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, time.Millisecond*10)
+	defer cancel()
+	if _, err := Ask[int](timeout, ref, "slow"); !errors.Is(err, ErrTimeout) {
+		t.Error("expected the ask to time out", err)
+	}
+
+	if info, _ := ref.Info(); info.Restarts != 1 {
+		t.Error("expected only the panic to restart the actor", info)
+	}
+
+	sys.Shutdown(ctx)
+	<-time.After(time.Millisecond * 50)
+}
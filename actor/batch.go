@@ -0,0 +1,134 @@
+package actor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BatchHandler may be implemented by an Actor to handle the messages waiting
+// in its mailbox together, rather than one at a time; it's only used should
+// the actor be spawned with BatchSize. This greatly improves throughput for
+// actors which write to databases or brokers.
+//
+// Returning an error, or panicking, fails the actor - and loses the batch;
+// PoisonThreshold, RetryMessages and AckMode only apply to the messages an
+// actor handles individually.
+type BatchHandler interface {
+	HandleBatch(ctx context.Context, msgs []interface{}) error
+}
+
+// TypedBatchHandler is the typed equivalent of BatchHandler; a TypedActor
+// which implements it handles batches once adapted by Untyped.
+type TypedBatchHandler[T any] interface {
+	HandleBatch(ctx context.Context, msgs []T) error
+}
+
+// BatchSize has an actor which implements BatchHandler handle up to n of the
+// messages waiting in its mailbox as a single batch. Should fewer be waiting
+// then the actor waits up to window for more to arrive, before handling the
+// batch regardless; a window of zero handles whatever is waiting.
+//
+// Messages sent by Ask, High priority messages, those which have been
+// unstashed or redelivered, and those of a DurableMailbox are always passed
+// to Handle individually.
+func BatchSize(n int, window time.Duration) SpawnOption {
+	return func(o *spawnOptions) {
+		o.batchSize = n
+		o.batchWindow = window
+	}
+}
+
+// batch collects a batch of messages, beginning with one received from the
+// mailbox, and passes it to the BatchHandler; it returns false should the
+// actor not be handling batches, or whether the actor should continue
+// handling messages otherwise.
+func (r *runtime) batch(ctx context.Context, first interface{}) (handled, ok bool) {
+	handler, batching := r.behaviour().(BatchHandler)
+	if !batching || r.batchSize < 2 {
+		return false, false
+	}
+
+	msgs := []interface{}{}
+	add := func(msg interface{}) bool {
+		msg = r.dequeued(msg)
+		switch msg.(type) {
+		case *Envelope, durable:
+			// Envelopes, and messages which must be acknowledged to a
+			// MailboxStore, are handled individually once the batch has
+			// been.
+			r.replay = append([]interface{}{msg}, r.replay...)
+			return false
+		}
+
+		msgs = append(msgs, msg)
+		return len(msgs) < r.batchSize
+	}
+
+	collecting := add(first)
+drain:
+	for collecting {
+		select {
+		case msg := <-r.mailbox.Messages:
+			collecting = add(msg)
+		default:
+			break drain
+		}
+	}
+
+	if collecting && r.batchWindow > 0 {
+		if r.window == nil {
+			r.window = r.clock.NewTimer(r.batchWindow)
+		} else {
+			r.window.Reset(r.batchWindow)
+		}
+
+		for collecting {
+			select {
+			case msg := <-r.mailbox.Messages:
+				collecting = add(msg)
+			case <-r.window.C():
+				collecting = false
+			case <-ctx.Done():
+				collecting = false
+			}
+		}
+		r.window.Stop()
+	}
+
+	if len(msgs) == 0 {
+		return true, true
+	}
+
+	hctx, cancel := r.withDeadline(ctx)
+	defer cancel()
+
+	err := handler.HandleBatch(hctx, msgs)
+	r.checkDeadline(ctx, hctx)
+	if err != nil {
+		r.failed(ctx, err, 0)
+		return true, false
+	}
+
+	return true, true
+}
+
+// untypedBatch adapts a TypedActor which implements TypedBatchHandler.
+type untypedBatch[T any] struct {
+	Actor
+	batch TypedBatchHandler[T]
+}
+
+func (u untypedBatch[T]) HandleBatch(ctx context.Context, msgs []interface{}) error {
+	typed := make([]T, len(msgs))
+	for i, msg := range msgs {
+		t, ok := msg.(T)
+		if !ok {
+			return fmt.Errorf("%w: %T", ErrUnexpectedMessage, msg)
+		}
+
+		typed[i] = t
+	}
+
+	return u.batch.HandleBatch(ctx, typed)
+}
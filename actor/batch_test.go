@@ -0,0 +1,63 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type batchWriter struct {
+	batches chan []int
+}
+
+func (w *batchWriter) Handle(ctx context.Context, msg int) error {
+	w.batches <- []int{msg}
+	return nil
+}
+
+func (w *batchWriter) HandleBatch(ctx context.Context, msgs []int) error {
+	w.batches <- msgs
+	return nil
+}
+
+func Test_BatchHandlerMustReceiveQueuedMessagesTogether(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &batchWriter{batches: make(chan []int, 8)}
+	ref, err := SpawnTyped[int](sys, "writer", w, BatchSize(4, time.Millisecond*50))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 6; i++ {
+		ref.Tell(i)
+	}
+
+	if batch := <-w.batches; len(batch) != 4 || batch[0] != 0 || batch[3] != 3 {
+		t.Error("expected a full batch of the queued messages", batch)
+	}
+
+	start := time.Now()
+	if batch := <-w.batches; len(batch) != 2 || batch[0] != 4 {
+		t.Error("expected a partial batch of the remaining messages", batch)
+	}
+
+	if waited := time.Since(start); waited < time.Millisecond*40 {
+		t.Error("expected a partial batch to wait for the window", waited)
+	}
+
+	go Ask[int](context.Background(), ref.Ref(), 6)
+	if batch := <-w.batches; len(batch) != 1 || batch[0] != 6 {
+		t.Error("expected an Ask to be handled individually", batch)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
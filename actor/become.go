@@ -0,0 +1,51 @@
+package actor
+
+import "context"
+
+// Become swaps the Actor handling messages for another, given the context
+// passed to Handle, taking effect from the next message; the previous Actor
+// is retained, and restored by Unbecome. This allows an actor to be written
+// as a state machine, with an Actor for each state.
+//
+//	func (c *Conn) Handle(ctx context.Context, msg interface{}) error {
+//		if _, ok := msg.(Connected); ok {
+//			actor.Become(ctx, actor.ActorFunc(c.authenticated))
+//		}
+//		return nil
+//	}
+//
+// Behaviours don't survive the actor being restarted, upon which it reverts
+// to the Actor it was spawned with. It returns false should the context not
+// belong to an actor.
+func Become(ctx context.Context, a Actor) bool {
+	cur, ok := ctx.Value(currentKey{}).(*current)
+	if !ok {
+		return false
+	}
+
+	cur.runtime.behaviours = append(cur.runtime.behaviours, a)
+	return true
+}
+
+// Unbecome restores the Actor which was handling messages prior to the last
+// call to Become, given the context passed to Handle. It returns false
+// should there be no such Actor.
+func Unbecome(ctx context.Context) bool {
+	cur, ok := ctx.Value(currentKey{}).(*current)
+	if !ok || len(cur.runtime.behaviours) == 0 {
+		return false
+	}
+
+	r := cur.runtime
+	r.behaviours = r.behaviours[:len(r.behaviours)-1]
+	return true
+}
+
+// behaviour returns the Actor currently handling messages.
+func (r *runtime) behaviour() Actor {
+	if n := len(r.behaviours); n > 0 {
+		return r.behaviours[n-1]
+	}
+
+	return r.actor
+}
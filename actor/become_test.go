@@ -0,0 +1,66 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_BecomeMustSwapTheHandler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var connecting, authenticated ActorFunc
+	connecting = func(ctx context.Context, msg interface{}) error {
+		if msg == "authenticate" {
+			Become(ctx, authenticated)
+		}
+		Reply(ctx, "connecting")
+		return nil
+	}
+
+	authenticated = func(ctx context.Context, msg interface{}) error {
+		if msg == "logout" && !Unbecome(ctx) {
+			t.Error("expected to revert to the previous behaviour")
+		}
+		Reply(ctx, "authenticated")
+		return nil
+	}
+
+	ref, _ := sys.Spawn("conn", connecting)
+	state := func(msg string) string {
+		reply, _ := Ask[string](context.Background(), ref, msg)
+		return reply
+	}
+
+	if got := state("ping"); got != "connecting" {
+		t.Error("expected the initial behaviour", got)
+	}
+
+	state("authenticate")
+	if got := state("ping"); got != "authenticated" {
+		t.Error("expected the behaviour to be swapped", got)
+	}
+
+	state("logout")
+	if got := state("ping"); got != "connecting" {
+		t.Error("expected the behaviour to be reverted", got)
+	}
+
+	state("authenticate")
+	ref.TellControl(Restart)
+	<-time.After(time.Millisecond * 20)
+
+	if got := state("ping"); got != "connecting" {
+		t.Error("expected a restart to reset the behaviour", got)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
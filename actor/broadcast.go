@@ -0,0 +1,107 @@
+package actor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// BroadcastGroup fans each message sent to it out to every member actor,
+// suiting patterns such as cache invalidation and configuration push.
+// Unlike a Pool, the group doesn't own its members: any actor may join or
+// leave the group at runtime, and stopped members are forgotten.
+//
+// Messages sent within an Envelope are forwarded intact to every member, so
+// an Ask receives the first member's reply.
+type BroadcastGroup struct {
+	ref *ActorRef
+
+	mu      sync.Mutex
+	members map[*ActorRef]bool
+}
+
+// NewBroadcastGroup spawns a BroadcastGroup under the System, with the given
+// name.
+func NewBroadcastGroup(sys *System, name string, opts ...SpawnOption) (*BroadcastGroup, error) {
+	g := &BroadcastGroup{members: map[*ActorRef]bool{}}
+
+	ref, err := sys.Spawn(name, ActorFunc(g.broadcast), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	g.ref = ref
+	return g, nil
+}
+
+// Ref returns the ActorRef of the group, to which messages are sent.
+func (g *BroadcastGroup) Ref() *ActorRef {
+	return g.ref
+}
+
+// Join adds an actor to the group, which receives every message sent to the
+// group after it joins; it returns false should the actor already be a
+// member, or have stopped.
+func (g *BroadcastGroup) Join(ref *ActorRef) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.members[ref] || !ref.Alive() {
+		return false
+	}
+
+	g.members[ref] = true
+	return true
+}
+
+// Leave removes an actor from the group; it returns false should the actor
+// not be a member.
+func (g *BroadcastGroup) Leave(ref *ActorRef) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ok := g.members[ref]
+	delete(g.members, ref)
+	return ok
+}
+
+// Members returns the live members of the group, sorted by name.
+func (g *BroadcastGroup) Members() []*ActorRef {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	members := make([]*ActorRef, 0, len(g.members))
+	for ref := range g.members {
+		if !ref.Alive() {
+			delete(g.members, ref)
+			continue
+		}
+
+		members = append(members, ref)
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].name < members[j].name })
+	return members
+}
+
+// broadcast forwards a message to every member.
+func (g *BroadcastGroup) broadcast(ctx context.Context, msg interface{}) error {
+	members := g.Members()
+	if len(members) == 0 {
+		if env, ok := CurrentEnvelope(ctx); ok {
+			msg = env
+		}
+
+		g.ref.deadLetter(msg, fmt.Errorf("%w: %q", ErrNoRoutees, g.ref.name))
+		return nil
+	}
+
+	for _, ref := range members {
+		// A member which stops in the meantime routes the message to the
+		// dead letters itself.
+		Forward(ctx, ref)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_BroadcastGroupMustFanOutToEveryMember(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan string, 10)
+	member := func(name string) *ActorRef {
+		ref, err := sys.Spawn(name, ActorFunc(func(ctx context.Context, msg interface{}) error {
+			received <- name
+			return nil
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ref
+	}
+
+	group, err := NewBroadcastGroup(sys, "caches")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := member("a"), member("b")
+	if !group.Join(a) || !group.Join(b) || group.Join(a) {
+		t.Error("expected each actor to join the group once")
+	}
+
+	group.Ref().Tell("invalidate")
+	<-time.After(time.Millisecond * 50)
+	if len(received) != 2 {
+		t.Error("expected the message to reach every member", len(received))
+	}
+
+	group.Leave(a)
+	sys.Stop("b")
+	if len(group.Members()) != 0 {
+		t.Error("expected members to leave the group, or be forgotten once stopped")
+	}
+
+	if _, err := Ask[string](context.Background(), group.Ref(), "lost"); !errors.Is(err, ErrNoRoutees) {
+		t.Error("expected ErrNoRoutees from an empty group", err)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,101 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrNoParent is returned by SpawnChild when the context doesn't belong
+	// to an actor spawned by a System.
+	ErrNoParent = errors.New("actor: no parent actor")
+	// ErrEscalated is the reason a parent is restarted upon the failure of
+	// a child spawned with Escalate.
+	ErrEscalated = errors.New("actor: child failure escalated")
+)
+
+type selfKey struct{}
+
+// Escalate escalates the failures of a child actor to its parent, which is
+// restarted in turn with a reason wrapping ErrEscalated. It has no effect
+// on actors spawned directly by a System.
+func Escalate() SpawnOption {
+	return func(o *spawnOptions) {
+		o.escalate = true
+	}
+}
+
+// Self returns the ActorRef of the actor handling the current message,
+// given the context passed to Handle.
+func Self(ctx context.Context) (*ActorRef, bool) {
+	ref, ok := ctx.Value(selfKey{}).(*ActorRef)
+	return ref, ok
+}
+
+// SpawnChild spawns an actor as a child of the actor handling the current
+// message, given the context passed to Handle; it returns ErrNoParent should
+// there be no such actor. Children are supervised by the System like any
+// other actor, and share its namespace, but are stopped along with their
+// parent. A parent being restarted doesn't affect its children.
+func SpawnChild(ctx context.Context, name string, a Actor, opts ...SpawnOption) (*ActorRef, error) {
+	parent, ok := Self(ctx)
+	if !ok {
+		return nil, ErrNoParent
+	}
+
+	return parent.system.spawn(parent, name, a, opts...)
+}
+
+// Parent returns the actor which spawned this actor via SpawnChild, or nil
+// should it have been spawned directly by the System.
+func (ref *ActorRef) Parent() *ActorRef {
+	return ref.parent
+}
+
+// Children returns the live children of the actor, sorted by name.
+func (ref *ActorRef) Children() []*ActorRef {
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	children := make([]*ActorRef, 0, len(ref.children))
+	for child := range ref.children {
+		children = append(children, child)
+	}
+
+	sort.Slice(children, func(i, j int) bool { return children[i].name < children[j].name })
+	return children
+}
+
+// adopt records a child of the actor, failing should the actor have
+// stopped.
+func (ref *ActorRef) adopt(child *ActorRef) error {
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	if ref.terminated {
+		return ref.errStopped()
+	}
+
+	ref.children[child] = true
+	return nil
+}
+
+// orphan removes a child of the actor.
+func (ref *ActorRef) orphan(child *ActorRef) {
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	delete(ref.children, child)
+}
+
+// escalate restarts the parent of an actor spawned with Escalate, upon the
+// actor failing as part of the given wave.
+func (ref *ActorRef) escalate(reason error, wave uint64) {
+	if !ref.escalates || ref.parent == nil {
+		return
+	}
+
+	ref.parent.kill(linkExit{reason: fmt.Errorf("%w: %q: %v", ErrEscalated, ref.name, reason), wave: wave})
+}
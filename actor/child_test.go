@@ -0,0 +1,97 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_ChildrenMustBeStoppedWithTheirParent(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idle := ActorFunc(func(ctx context.Context, msg interface{}) error { return nil })
+	parent, _ := sys.Spawn("parent", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		child, err := SpawnChild(ctx, msg.(string), idle)
+		if err != nil {
+			return err
+		}
+
+		Reply(ctx, child)
+		return nil
+	}))
+
+	child, err := Ask[*ActorRef](context.Background(), parent, "child")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if child.Parent() != parent || len(parent.Children()) != 1 {
+		t.Error("expected the child to be recorded against its parent")
+	}
+
+	if _, err := SpawnChild(context.Background(), "orphan", idle); !errors.Is(err, ErrNoParent) {
+		t.Error("expected ErrNoParent outside of an actor", err)
+	}
+
+	sys.Stop("parent")
+	if child.Alive() || len(sys.Actors()) != 0 {
+		t.Error("expected the child to be stopped with its parent", sys.Actors())
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_EscalateMustRestartTheParent(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("failed")
+	parent, _ := sys.Spawn("parent", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		child, err := SpawnChild(ctx, "child", ActorFunc(func(ctx context.Context, msg interface{}) error {
+			return failure
+		}), Escalate())
+		if err != nil {
+			return err
+		}
+
+		Reply(ctx, child)
+		return nil
+	}))
+
+	child, err := Ask[*ActorRef](context.Background(), parent, "spawn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child.Tell("fail")
+	<-time.After(time.Millisecond * 100)
+
+	history := sys.Supervisor().History("parent", 0)
+	if len(history) != 1 {
+		t.Fatal("expected the parent to be restarted", history)
+	}
+
+	if reason, _ := history[0].Reason.(error); !errors.Is(reason, ErrEscalated) {
+		t.Error("expected the parent to exit with ErrEscalated", history[0].Reason)
+	}
+
+	if !child.Alive() {
+		t.Error("expected the child to survive its parent restarting")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,469 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrNodeDown is the reason given to the monitors and links of the proxies
+// for a node's actors, upon the node being deemed to have failed.
+var ErrNodeDown = errors.New("actor: node down")
+
+const (
+	// DefaultHeartbeatInterval is how often a Cluster exchanges heartbeats
+	// with its members.
+	DefaultHeartbeatInterval = time.Second
+	// DefaultFailureTimeout is how long a member may go without responding
+	// to heartbeats before it's deemed to have failed.
+	DefaultFailureTimeout = 5 * time.Second
+)
+
+// clusterActor is the name of the actor which answers the heartbeats of a
+// Cluster's members.
+const clusterActor = "$cluster"
+
+// NodeEventKind is the kind of a NodeEvent.
+type NodeEventKind int
+
+const (
+	// NodeUp denotes a member responding to heartbeats, either for the first
+	// time or after having failed.
+	NodeUp NodeEventKind = iota + 1
+	// NodeDown denotes a member having failed to respond to heartbeats
+	// within the failure timeout.
+	NodeDown
+)
+
+// NodeEvent notifies of a change in the state of a Cluster's member.
+type NodeEvent struct {
+	Addr string
+	Kind NodeEventKind
+}
+
+// ClusterHeartbeat is exchanged between the members of a Cluster, each
+// member replying to another's heartbeat with its own. Transports which
+// encode messages with gob must register it.
+type ClusterHeartbeat struct {
+	// From is the address of the member sending the heartbeat.
+	From string
+	// Members are the addresses of the members known to the sender, should
+	// it gossip.
+	Members []string
+	// Actors are the names of the actors within the sender's System.
+	Actors []string
+}
+
+// ClusterOption configures a Cluster.
+type ClusterOption func(*clusterOptions)
+
+type clusterOptions struct {
+	seeds    []string
+	gossip   bool
+	interval time.Duration
+	timeout  time.Duration
+	events   func(NodeEvent)
+	spawn    []SpawnOption
+}
+
+// Seeds sets the addresses of the Cluster's members; without Gossip these
+// are its only members.
+func Seeds(addrs ...string) ClusterOption {
+	return func(o *clusterOptions) {
+		o.seeds = append(o.seeds, addrs...)
+	}
+}
+
+// Gossip causes the Cluster to learn of the members known to each of its
+// members through their heartbeats, and of any node which sends it a
+// heartbeat; the seeds then only need to include a single member.
+func Gossip() ClusterOption {
+	return func(o *clusterOptions) {
+		o.gossip = true
+	}
+}
+
+// Heartbeat sets how often heartbeats are exchanged, and how long a member
+// may go without responding before it's deemed to have failed; they default
+// to DefaultHeartbeatInterval and DefaultFailureTimeout.
+func Heartbeat(interval, timeout time.Duration) ClusterOption {
+	return func(o *clusterOptions) {
+		o.interval, o.timeout = interval, timeout
+	}
+}
+
+// OnNodeEvent sets a function called upon each NodeEvent; it's called
+// synchronously, so mustn't block.
+func OnNodeEvent(fn func(NodeEvent)) ClusterOption {
+	return func(o *clusterOptions) {
+		o.events = fn
+	}
+}
+
+// ProxyOptions sets the options with which the proxies for the actors of
+// other members are spawned; see RemoteRef.
+func ProxyOptions(opts ...SpawnOption) ClusterOption {
+	return func(o *clusterOptions) {
+		o.spawn = append(o.spawn, opts...)
+	}
+}
+
+// Cluster joins a System to the Systems of other processes reachable via a
+// Transport, forming a membership layer above it. Members exchange
+// heartbeats, through which each learns of the actors within the others'
+// Systems - allowing names to be resolved across the cluster - and detects
+// those which have failed.
+//
+// Upon a member failing, the proxies for its actors report an exit with a
+// reason wrapping ErrNodeDown: their monitors are sent Down, and any actors
+// linked to them are restarted. The proxies are retained, so once the member
+// recovers they may be used again.
+type Cluster struct {
+	sys       *System
+	transport Transport
+	self      string
+	o         clusterOptions
+
+	mu      sync.Mutex
+	members map[string]*member
+	proxies map[string][]*ActorRef
+}
+
+// member is the state of a Cluster's member, as learnt from heartbeats.
+type member struct {
+	up       bool
+	lastSeen time.Time
+	actors   map[string]bool
+}
+
+// NewCluster joins the System to a Cluster, as the member at the address
+// self; its heartbeats are sent, and answered, under the System's root
+// Supervisor.
+func NewCluster(sys *System, t Transport, self string, opts ...ClusterOption) (*Cluster, error) {
+	o := clusterOptions{interval: DefaultHeartbeatInterval, timeout: DefaultFailureTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Cluster{
+		sys:       sys,
+		transport: t,
+		self:      self,
+		o:         o,
+		members:   map[string]*member{},
+		proxies:   map[string][]*ActorRef{},
+	}
+
+	for _, addr := range o.seeds {
+		if addr != self {
+			c.members[addr] = &member{}
+		}
+	}
+
+	if _, err := sys.Spawn(clusterActor, ActorFunc(c.answer)); err != nil {
+		return nil, err
+	}
+
+	if err := sys.root.AddWorker(supervisor.WorkerSpec{Name: clusterActor + "/heartbeat", Worker: c.heartbeat}); err != nil {
+		sys.Stop(clusterActor)
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close stops the Cluster's heartbeats, leaving the proxies for the actors
+// of other members in place.
+func (c *Cluster) Close() {
+	c.sys.root.RemoveWorker(clusterActor + "/heartbeat")
+	c.sys.Stop(clusterActor)
+}
+
+// Members returns the addresses of every member which is up, including this
+// one, sorted alphabetically.
+func (c *Cluster) Members() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.upLocked()
+}
+
+// Whereis returns the actor known by name, as System.Whereis does; should no
+// local actor be known by the name then the actors of the other members are
+// searched, returning a proxy for the first found.
+func (c *Cluster) Whereis(name string) (*ActorRef, bool) {
+	if ref, ok := c.sys.Whereis(name); ok {
+		return ref, true
+	}
+
+	c.mu.Lock()
+	var addr string
+	for _, a := range c.upLocked() {
+		if m, ok := c.members[a]; ok && m.actors[name] {
+			addr = a
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	if addr == "" {
+		return nil, false
+	}
+
+	ref, err := c.proxy(addr, name)
+	return ref, err == nil
+}
+
+// Owner returns the address of the member which owns the key, chosen by
+// rendezvous hashing amongst the members which are up; as members fail or
+// join, only the keys of those members move.
+func (c *Cluster) Owner(key string) string {
+	var (
+		owner string
+		best  uint64
+	)
+
+	for _, addr := range c.Members() {
+		if sum := rendezvous(addr, key); owner == "" || sum > best {
+			owner, best = addr, sum
+		}
+	}
+
+	return owner
+}
+
+// Shard returns the named actor of the member which owns the key, allowing
+// a set of actors - one per member - to distribute shards across the
+// Cluster; see Owner.
+func (c *Cluster) Shard(name, key string) (*ActorRef, error) {
+	owner := c.Owner(key)
+	if owner != c.self {
+		return c.proxy(owner, name)
+	}
+
+	ref, ok := c.sys.Whereis(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownActor, name)
+	}
+
+	return ref, nil
+}
+
+// proxy returns the proxy for the named actor of the member at the address,
+// spawning it should it not exist.
+func (c *Cluster) proxy(addr, name string) (*ActorRef, error) {
+	for {
+		if ref, ok := c.sys.Whereis(fmt.Sprintf("%s@%s", name, addr)); ok {
+			return ref, nil
+		}
+
+		ref, err := c.sys.RemoteRef(c.transport, addr, name, c.o.spawn...)
+		if errors.Is(err, ErrNameTaken) {
+			// The proxy was spawned concurrently.
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.proxies[addr] = append(c.proxies[addr], ref)
+		c.mu.Unlock()
+
+		return ref, nil
+	}
+}
+
+// answer replies to the heartbeats of other members with this member's.
+func (c *Cluster) answer(ctx context.Context, msg interface{}) error {
+	hb, ok := msg.(ClusterHeartbeat)
+	if !ok {
+		return nil
+	}
+
+	if hb.From != "" {
+		c.seen(hb.From, hb)
+	}
+
+	Reply(ctx, c.heartbeatMessage())
+	return nil
+}
+
+// heartbeat exchanges heartbeats with every member at each interval,
+// detecting the failure of any member which doesn't respond in time.
+func (c *Cluster) heartbeat(ctx context.Context, done chan struct{}) {
+	defer supervisor.Recover(ctx, done)
+
+	timer := c.sys.clock.NewTimer(c.o.interval)
+	defer timer.Stop()
+
+	supervisor.Ready(ctx)
+	for {
+		c.round(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C():
+			timer.Reset(c.o.interval)
+		}
+	}
+}
+
+// round sends a heartbeat to every member, awaiting their replies.
+func (c *Cluster) round(ctx context.Context) {
+	hb := c.heartbeatMessage()
+
+	c.mu.Lock()
+	addrs := make([]string, 0, len(c.members))
+	for addr := range c.members {
+		addrs = append(addrs, addr)
+	}
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, addr := range addrs {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+
+			ctx, cancel := supervisor.WithTimeout(ctx, c.o.interval)
+			defer cancel()
+
+			reply, err := c.transport.Send(ctx, addr, RemoteMessage{
+				Actor:   clusterActor,
+				Message: hb,
+				ID:      newID(),
+				Reply:   true,
+			})
+			if err == nil {
+				err = reply.Err()
+			}
+
+			if answer, ok := reply.Value.(ClusterHeartbeat); err == nil && ok {
+				c.seen(addr, answer)
+			}
+		}(addr)
+	}
+	wg.Wait()
+
+	c.detect(c.sys.clock.Now())
+}
+
+// heartbeatMessage returns this member's heartbeat. Proxies - whose names
+// contain "@" - aren't advertised, so names only resolve to the member
+// which holds the actor itself.
+func (c *Cluster) heartbeatMessage() ClusterHeartbeat {
+	hb := ClusterHeartbeat{From: c.self}
+	for _, name := range append(c.sys.Actors(), c.sys.Registered()...) {
+		if name != clusterActor && !strings.Contains(name, "@") {
+			hb.Actors = append(hb.Actors, name)
+		}
+	}
+
+	if c.o.gossip {
+		hb.Members = c.Members()
+	}
+
+	return hb
+}
+
+// seen records the heartbeat of the member at the address.
+func (c *Cluster) seen(addr string, hb ClusterHeartbeat) {
+	var events []NodeEvent
+
+	c.mu.Lock()
+	m, ok := c.members[addr]
+	if !ok && c.o.gossip && addr != c.self {
+		m = &member{}
+		c.members[addr] = m
+	}
+
+	if m != nil {
+		m.lastSeen = c.sys.clock.Now()
+		m.actors = make(map[string]bool, len(hb.Actors))
+		for _, name := range hb.Actors {
+			m.actors[name] = true
+		}
+
+		if !m.up {
+			m.up = true
+			events = append(events, NodeEvent{Addr: addr, Kind: NodeUp})
+		}
+	}
+
+	if c.o.gossip {
+		for _, other := range hb.Members {
+			if _, ok := c.members[other]; !ok && other != c.self {
+				c.members[other] = &member{}
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	c.notify(events)
+}
+
+// detect deems any member not seen within the failure timeout to be down,
+// notifying the monitors and links of the proxies for its actors.
+func (c *Cluster) detect(now time.Time) {
+	var (
+		events  []NodeEvent
+		proxies = map[*ActorRef]string{}
+	)
+
+	c.mu.Lock()
+	for addr, m := range c.members {
+		if !m.up || now.Sub(m.lastSeen) < c.o.timeout {
+			continue
+		}
+
+		m.up, m.actors = false, nil
+		events = append(events, NodeEvent{Addr: addr, Kind: NodeDown})
+
+		alive := c.proxies[addr][:0]
+		for _, ref := range c.proxies[addr] {
+			if ref.Alive() {
+				alive = append(alive, ref)
+			}
+		}
+		c.proxies[addr] = alive
+		for _, ref := range alive {
+			proxies[ref] = addr
+		}
+	}
+	c.mu.Unlock()
+
+	for ref, addr := range proxies {
+		ref.exited(fmt.Errorf("%w: %q", ErrNodeDown, addr), 0)
+	}
+
+	c.notify(events)
+}
+
+func (c *Cluster) notify(events []NodeEvent) {
+	if c.o.events == nil {
+		return
+	}
+
+	for _, event := range events {
+		c.o.events(event)
+	}
+}
+
+func (c *Cluster) upLocked() []string {
+	up := []string{c.self}
+	for addr, m := range c.members {
+		if m.up {
+			up = append(up, addr)
+		}
+	}
+
+	sort.Strings(up)
+	return up
+}
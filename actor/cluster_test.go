@@ -0,0 +1,135 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// partitioned is a loopback Transport whose members may be cut off.
+type partitioned struct {
+	loopback
+
+	mu  sync.Mutex
+	cut map[string]bool
+}
+
+func (p *partitioned) Send(ctx context.Context, addr string, msg RemoteMessage) (RemoteReply, error) {
+	p.mu.Lock()
+	cut := p.cut[addr]
+	p.mu.Unlock()
+
+	if cut {
+		return RemoteReply{}, errors.New("unreachable")
+	}
+
+	return p.loopback.Send(ctx, addr, msg)
+}
+
+func Test_ClusterMustResolveNamesAcrossMembersAndDetectFailures(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	a, _ := NewSystem(context.Background())
+	b, _ := NewSystem(context.Background())
+
+	transport := &partitioned{loopback: loopback{"a:1": a, "b:1": b}, cut: map[string]bool{}}
+
+	events := make(chan NodeEvent, 10)
+	ca, err := NewCluster(a, transport, "a:1", Seeds("b:1"), Heartbeat(time.Millisecond*10, time.Millisecond*50), OnNodeEvent(func(e NodeEvent) {
+		events <- e
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb, err := NewCluster(b, transport, "b:1", Gossip(), Heartbeat(time.Millisecond*10, time.Millisecond*50))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Spawn("billing", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		Reply(ctx, 42)
+		return nil
+	}))
+
+	<-time.After(time.Millisecond * 50)
+	select {
+	case e := <-events:
+		if e != (NodeEvent{Addr: "b:1", Kind: NodeUp}) {
+			t.Error("expected b to be up", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected b to be up")
+	}
+
+	if members := ca.Members(); len(members) != 2 || members[0] != "a:1" || members[1] != "b:1" {
+		t.Error("expected both members to be up", members)
+	}
+
+	ref, ok := ca.Whereis("billing")
+	if !ok {
+		t.Fatal("expected billing to be resolved via b")
+	}
+
+	if v, err := Ask[int](context.Background(), ref, "invoice"); err != nil || v != 42 {
+		t.Error("expected the remote actor's reply", v, err)
+	}
+
+	if _, ok := ca.Whereis("missing"); ok {
+		t.Error("expected an unknown name not to resolve")
+	}
+
+	if owner := ca.Owner("customer-1"); owner != "a:1" && owner != "b:1" {
+		t.Error("expected a member to own the key", owner)
+	}
+
+	downs := make(chan Down, 1)
+	watcher, _ := a.Spawn("watcher", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		if down, ok := msg.(Down); ok {
+			downs <- down
+		}
+		return nil
+	}))
+	watcher.Monitor(ref)
+
+	// b ceases to send heartbeats, and to answer those of a.
+	cb.Close()
+	transport.mu.Lock()
+	transport.cut["b:1"] = true
+	transport.mu.Unlock()
+	<-time.After(time.Millisecond * 150)
+
+	select {
+	case e := <-events:
+		if e != (NodeEvent{Addr: "b:1", Kind: NodeDown}) {
+			t.Error("expected b to be down", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected b to be down")
+	}
+
+	select {
+	case down := <-downs:
+		if !errors.Is(down.Reason, ErrNodeDown) {
+			t.Error("expected the proxy to exit as its node is down", down.Reason)
+		}
+	default:
+		t.Error("expected the monitor to be notified of the node failing")
+	}
+
+	if _, ok := ca.Whereis("billing"); ok {
+		t.Error("expected the names of a failed member not to resolve")
+	}
+
+	if owner := ca.Owner("customer-1"); owner != "a:1" {
+		t.Error("expected the remaining member to own every key", owner)
+	}
+
+	a.Shutdown(context.Background())
+	b.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,86 @@
+package actor
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// DeadLetter is a message which couldn't be delivered to the actor it was
+// sent to; either as the actor - or its System - had stopped, or as it was
+// dropped by the actor's OverflowPolicy.
+type DeadLetter struct {
+	// Actor is the actor the message was sent to.
+	Actor *ActorRef
+	// Message is the message which wasn't delivered; for a message sent by
+	// Ask, it's the message the Envelope carried.
+	Message interface{}
+	// Reason is why the message wasn't delivered, wrapping either
+	// ErrStopped or ErrMailboxFull.
+	Reason error
+}
+
+// OnDeadLetter sets a function to be called with every DeadLetter, in place
+// of the default handler which logs them via the Supervisor's Logger; see
+// supervisor.WithLogger. Passing nil restores the default. The function is
+// called by the sender, so mustn't block.
+func (sys *System) OnDeadLetter(fn func(DeadLetter)) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	sys.deadLetters = fn
+}
+
+// DeadLetters returns the number of messages which couldn't be delivered to
+// the System's actors.
+func (sys *System) DeadLetters() int {
+	return int(atomic.LoadUint64(&sys.undelivered))
+}
+
+// deadLetter routes a message which couldn't be delivered to the System's
+// dead letters, replying to it should it have been sent by Ask.
+func (ref *ActorRef) deadLetter(msg interface{}, reason error) {
+	msg, _ = unretried(unwrap(msg))
+	if m, ok := msg.(durable); ok {
+		acknowledge(m)
+		msg = m.msg
+	}
+
+	if env, ok := msg.(*Envelope); ok {
+		env.respond(Response{Err: reason})
+		msg = env.Message
+	}
+
+	sys := ref.system
+	atomic.AddUint64(&sys.undelivered, 1)
+
+	sys.mu.RLock()
+	fn := sys.deadLetters
+	sys.mu.RUnlock()
+
+	if fn == nil {
+		fn = logDeadLetter
+	}
+
+	fn(DeadLetter{Actor: ref, Message: msg, Reason: reason})
+}
+
+func logDeadLetter(letter DeadLetter) {
+	supervisor.Log(fmt.Sprintf("actor: dead letter %T for %q: %v", letter.Message, letter.Actor.name, letter.Reason))
+}
+
+// drain routes the messages left in the mailbox of a stopped actor to the
+// System's dead letters.
+func (ref *ActorRef) drain() {
+	for {
+		select {
+		case msg := <-ref.mailbox.Urgent:
+			ref.deadLetter(msg, ref.errStopped())
+		case msg := <-ref.mailbox.Messages:
+			ref.deadLetter(msg, ref.errStopped())
+		default:
+			return
+		}
+	}
+}
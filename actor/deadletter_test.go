@@ -0,0 +1,73 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_DeadLettersMustReceiveUndeliverableMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu      sync.Mutex
+		letters []DeadLetter
+	)
+	sys.OnDeadLetter(func(letter DeadLetter) {
+		mu.Lock()
+		defer mu.Unlock()
+		letters = append(letters, letter)
+	})
+
+	release := make(chan struct{})
+	ref, _ := sys.Spawn("worker", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		<-release
+		return nil
+	}))
+
+	ref.Tell("in progress")
+	ref.Tell("waiting")
+	<-time.After(time.Millisecond * 10)
+
+	go func() {
+		<-time.After(time.Millisecond * 10)
+		close(release)
+	}()
+	sys.Stop("worker")
+
+	if err := ref.Tell("late"); !errors.Is(err, ErrStopped) {
+		t.Error("expected ErrStopped for a stopped actor", err)
+	}
+
+	if _, err := Ask[string](context.Background(), ref, "ask"); !errors.Is(err, ErrStopped) {
+		t.Error("expected ErrStopped when asking a stopped actor", err)
+	}
+
+	mu.Lock()
+	if len(letters) != 3 || letters[0].Message != "waiting" || letters[2].Message != "ask" {
+		t.Error("expected undelivered messages to be routed to the dead letters", letters)
+	}
+
+	for _, letter := range letters {
+		if letter.Actor != ref || !errors.Is(letter.Reason, ErrStopped) {
+			t.Error("expected the dead letter to carry its actor and reason", letter)
+		}
+	}
+	mu.Unlock()
+
+	if sys.DeadLetters() != 3 {
+		t.Error("expected dead letters to be counted", sys.DeadLetters())
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
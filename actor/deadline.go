@@ -0,0 +1,59 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrDeadlineExceeded is the reason an actor fails upon a message exceeding
+// its deadline; see MessageDeadline.
+var ErrDeadlineExceeded = errors.New("actor: message deadline exceeded")
+
+// MessageDeadline gives each call to Handle - or HandleBatch - a context
+// which is cancelled once d has elapsed, and counts the messages which
+// exceed it; see ActorRef.Timeouts. Should fail be set then a message which
+// exceeds its deadline is treated as though Handle had panicked with an
+// error wrapping ErrDeadlineExceeded, so counts towards PoisonThreshold.
+func MessageDeadline(d time.Duration, fail bool) SpawnOption {
+	return func(o *spawnOptions) {
+		o.deadline = d
+		o.deadlineFails = fail
+	}
+}
+
+// Timeouts returns the number of messages which have exceeded the deadline
+// set by MessageDeadline.
+func (ref *ActorRef) Timeouts() int {
+	return int(atomic.LoadUint64(&ref.timeouts))
+}
+
+// withDeadline derives the context for handling a single message.
+func (r *runtime) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.deadline <= 0 {
+		return ctx, func() {}
+	}
+
+	return supervisor.WithTimeout(ctx, r.deadline)
+}
+
+// checkDeadline records a message having exceeded its deadline, panicking
+// should the actor fail upon doing so.
+func (r *runtime) checkDeadline(parent, ctx context.Context) {
+	if r.deadline <= 0 || parent.Err() != nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return
+	}
+
+	if r.ref == nil {
+		return
+	}
+
+	atomic.AddUint64(&r.ref.timeouts, 1)
+	if r.deadlineFails {
+		panic(fmt.Errorf("%w: %q after %s", ErrDeadlineExceeded, r.ref.name, r.deadline))
+	}
+}
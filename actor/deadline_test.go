@@ -0,0 +1,59 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_MessageDeadlineMustCancelSlowMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	letters := make(chan DeadLetter, 1)
+	sys.OnDeadLetter(func(letter DeadLetter) {
+		letters <- letter
+	})
+
+	slow := ActorFunc(func(ctx context.Context, msg interface{}) error {
+		if msg == "slow" {
+			<-ctx.Done()
+		}
+		return nil
+	})
+
+	counted, _ := sys.Spawn("counted", slow, MessageDeadline(time.Millisecond*10, false))
+	failing, _ := sys.Spawn("failing", slow, MessageDeadline(time.Millisecond*10, true), PoisonThreshold(2))
+
+	counted.Tell("slow")
+	counted.Tell("fast")
+	failing.Tell("slow")
+	<-time.After(time.Millisecond * 100)
+
+	if counted.Timeouts() != 1 || len(sys.Supervisor().History("counted", 0)) != 0 {
+		t.Error("expected the timeout to be counted without the actor failing", counted.Timeouts())
+	}
+
+	if failing.Timeouts() != 2 || len(sys.Supervisor().History("failing", 0)) != 2 {
+		t.Error("expected the timeout to fail the actor", failing.Timeouts())
+	}
+
+	select {
+	case letter := <-letters:
+		if letter.Message != "slow" || !errors.Is(letter.Reason, ErrPoisoned) {
+			t.Error("expected the slow message to be quarantined", letter)
+		}
+	default:
+		t.Error("expected the slow message to count towards the PoisonThreshold")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,158 @@
+package actor
+
+import (
+	"fmt"
+	"sync"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// MailboxStore is durable storage backing an actor's mailbox; see
+// DurableMailbox. Implementations must be safe for concurrent use.
+type MailboxStore interface {
+	// Append persists a message sent to the actor, returning its ID.
+	Append(msg interface{}) (uint64, error)
+	// Peek returns up to n of the oldest messages which haven't been
+	// acknowledged, in the order they were appended; should n be below 1
+	// then every such message is returned.
+	Peek(n int) ([]StoredMessage, error)
+	// Ack removes a message once the actor is done with it.
+	Ack(id uint64) error
+}
+
+// StoredMessage is a message held by a MailboxStore.
+type StoredMessage struct {
+	ID      uint64
+	Message interface{}
+}
+
+// DurableMailbox backs the actor's mailbox with the store: each message is
+// appended to the store before being enqueued, and only acknowledged once
+// the actor is done with it - upon it being handled, or routed to the dead
+// letters - so messages which were waiting in the mailbox, or whose handling
+// was interrupted, when the process exited are redelivered once the actor is next
+// spawned with the same store, ahead of any new messages.
+//
+// Messages are passed to the store as they're sent, so must be of a form it
+// can persist; the reply channel of an Envelope doesn't survive the process
+// exiting.
+func DurableMailbox(store MailboxStore) SpawnOption {
+	return func(o *spawnOptions) {
+		o.store = store
+	}
+}
+
+// durable wraps a message which has been appended to a MailboxStore.
+type durable struct {
+	msg   interface{}
+	id    uint64
+	store MailboxStore
+}
+
+func (m durable) Priority() Priority { return priorityOf(m.msg) }
+
+// undurable unwraps a message which has been appended to a MailboxStore.
+func undurable(msg interface{}) interface{} {
+	if m, ok := msg.(durable); ok {
+		return m.msg
+	}
+
+	return msg
+}
+
+// persist appends a message sent to the actor to its MailboxStore, should
+// it have one; messages which are being retried, or forwarded, have already
+// been appended.
+func (ref *ActorRef) persist(msg interface{}) (interface{}, error) {
+	if ref.store == nil {
+		return msg, nil
+	}
+
+	switch msg.(type) {
+	case durable, retried:
+		return msg, nil
+	}
+
+	id, err := ref.store.Append(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return durable{msg: msg, id: id, store: ref.store}, nil
+}
+
+// acknowledge acknowledges a message which has been appended to a
+// MailboxStore, should it have been.
+func acknowledge(msg interface{}) {
+	msg, _ = unretried(unwrap(msg))
+	if m, ok := msg.(durable); ok {
+		if err := m.store.Ack(m.id); err != nil {
+			supervisor.Log(fmt.Sprintf("actor: failed to acknowledge message %d: %v", m.id, err))
+		}
+	}
+}
+
+// recoverStored returns the messages left unacknowledged in the store, for
+// redelivery.
+func recoverStored(store MailboxStore) ([]interface{}, error) {
+	stored, err := store.Peek(0)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]interface{}, len(stored))
+	for i, m := range stored {
+		msgs[i] = durable{msg: m.Message, id: m.ID, store: store}
+	}
+
+	return msgs, nil
+}
+
+// MemoryMailboxStore is an in-memory MailboxStore, suited to tests.
+type MemoryMailboxStore struct {
+	mu   sync.Mutex
+	next uint64
+	msgs []StoredMessage
+}
+
+// NewMemoryMailboxStore returns an empty MemoryMailboxStore.
+func NewMemoryMailboxStore() *MemoryMailboxStore {
+	return &MemoryMailboxStore{}
+}
+
+// Append stores the message.
+func (s *MemoryMailboxStore) Append(msg interface{}) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	s.msgs = append(s.msgs, StoredMessage{ID: s.next, Message: msg})
+	return s.next, nil
+}
+
+// Peek returns up to n of the oldest stored messages.
+func (s *MemoryMailboxStore) Peek(n int) ([]StoredMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n < 1 || n > len(s.msgs) {
+		n = len(s.msgs)
+	}
+
+	return append([]StoredMessage(nil), s.msgs[:n]...), nil
+}
+
+// Ack removes the message.
+func (s *MemoryMailboxStore) Ack(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.msgs {
+		if m.ID == id {
+			s.msgs = append(s.msgs[:i], s.msgs[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
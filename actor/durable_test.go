@@ -0,0 +1,67 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_DurableMailboxMustRedeliverAcrossSystems(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	store := NewMemoryMailboxStore()
+
+	// The first System never gets to handle its messages, as though the
+	// process exited whilst they were waiting.
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := make(chan struct{})
+	ref, _ := sys.Spawn("orders", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		select {
+		case <-block:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}), DurableMailbox(store))
+
+	for i := 0; i < 3; i++ {
+		ref.Tell(i)
+	}
+	<-time.After(time.Millisecond * 20)
+	sys.Shutdown(context.Background())
+	close(block)
+
+	if pending, _ := store.Peek(0); len(pending) != 3 {
+		t.Fatal("expected interrupted and unhandled messages to remain in the store", len(pending))
+	}
+
+	sys, err = NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan interface{}, 10)
+	ref, _ = sys.Spawn("orders", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		handled <- msg
+		return nil
+	}), DurableMailbox(store))
+	ref.Tell(3)
+
+	<-time.After(time.Millisecond * 50)
+	if len(handled) != 4 || <-handled != 0 {
+		t.Error("expected stored messages to be redelivered ahead of new ones", len(handled))
+	}
+
+	if pending, _ := store.Peek(0); len(pending) != 0 {
+		t.Error("expected handled messages to be acknowledged", len(pending))
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
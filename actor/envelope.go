@@ -0,0 +1,104 @@
+package actor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrNoMessage is returned by Forward when the context doesn't belong to an
+// actor handling a message.
+var ErrNoMessage = errors.New("actor: no message being handled")
+
+// NewEnvelope wraps a message in an Envelope with a new ID. Given the context
+// passed to Handle, the Envelope's Sender is the actor handling the current
+// message, whilst its CorrelationID and Headers are inherited from the
+// Envelope of that message - whose ID becomes the CausationID. Should the
+// System have a Tracer then the current trace context is injected into the
+// Headers; see System.Trace.
+func NewEnvelope(ctx context.Context, msg interface{}) *Envelope {
+	env := &Envelope{Message: msg, ID: newID(), Created: time.Now()}
+	env.CorrelationID = env.ID
+	env.Sender, _ = Self(ctx)
+
+	if cause, ok := CurrentEnvelope(ctx); ok {
+		env.CausationID = cause.ID
+		if cause.CorrelationID != "" {
+			env.CorrelationID = cause.CorrelationID
+		}
+
+		if len(cause.Headers) > 0 {
+			env.Headers = make(map[string]string, len(cause.Headers))
+			for k, v := range cause.Headers {
+				env.Headers[k] = v
+			}
+		}
+	}
+
+	if env.Sender != nil {
+		env.Sender.system.inject(ctx, env)
+	}
+
+	return env
+}
+
+// CurrentEnvelope returns the Envelope of the message being handled, given
+// the context passed to Handle; it returns false should the message not
+// have been sent within an Envelope.
+func CurrentEnvelope(ctx context.Context) (*Envelope, bool) {
+	env, ok := ctx.Value(envelopeKey{}).(*Envelope)
+	return env, ok
+}
+
+// Send delivers a message to the actor within an Envelope built by
+// NewEnvelope, as Tell does; it's how an actor sends a message which
+// follows from the one it's handling.
+func Send(ctx context.Context, ref *ActorRef, msg interface{}) error {
+	return ref.Tell(newEnvelopeTo(ctx, ref, msg))
+}
+
+// Request sends a message to the actor within an Envelope built by
+// NewEnvelope, with the reply being delivered to the replyTo actor as a
+// Response message whose InReplyTo is the Envelope's ID. Should replyTo be
+// nil then the reply is delivered to the actor handling the current
+// message, given the context passed to Handle.
+//
+// Unlike Ask, Request doesn't wait for the reply; it suits actors which
+// mustn't block whilst handling a message.
+func Request(ctx context.Context, ref *ActorRef, msg interface{}, replyTo *ActorRef) (string, error) {
+	env := newEnvelopeTo(ctx, ref, msg)
+	env.ReplyToRef = replyTo
+	if replyTo == nil {
+		env.ReplyToRef = env.Sender
+	}
+
+	return env.ID, ref.Tell(env)
+}
+
+// Forward delivers the message being handled, given the context passed to
+// Handle, to another actor - within its original Envelope, should it have
+// one - so that it's the other actor which responds to it. The message is
+// then considered handled by this actor.
+func Forward(ctx context.Context, ref *ActorRef) error {
+	cur, ok := ctx.Value(currentKey{}).(*current)
+	if !ok {
+		return ErrNoMessage
+	}
+
+	if err := ref.tell(ctx, cur.msg); err != nil {
+		return err
+	}
+
+	cur.forwarded = true
+	cur.runtime.holding = false
+	return nil
+}
+
+// newID returns a random identifier for an Envelope.
+func newID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,98 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_EnvelopeMustPropagateMetadata(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan *Envelope, 1)
+	audit, _ := sys.Spawn("audit", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		env, _ := CurrentEnvelope(ctx)
+		received <- env
+		return nil
+	}))
+
+	billing, _ := sys.Spawn("billing", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		return Send(ctx, audit, "charged")
+	}))
+
+	origin := NewEnvelope(context.Background(), "charge")
+	origin.Headers = map[string]string{"trace": "abc"}
+	billing.Tell(origin)
+
+	env := <-received
+	switch {
+	case env.Message != "charged" || env.Sender != billing:
+		t.Error("expected the envelope to carry the message and its sender", env.Message, env.Sender)
+	case env.CorrelationID != origin.ID || env.CausationID != origin.ID || env.ID == origin.ID:
+		t.Error("expected the envelope to be correlated with its cause", env.CorrelationID, env.CausationID)
+	case env.Headers["trace"] != "abc":
+		t.Error("expected the headers to be inherited", env.Headers)
+	case env.Created.Before(origin.Created):
+		t.Error("expected the envelope's creation time to be recorded", env.Created)
+	}
+
+	if origin.Sender != nil || origin.CausationID != "" {
+		t.Error("expected an envelope sent from outside an actor to have no sender or cause")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_RequestMustDeliverTheReplyToAnActor(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pricing, _ := sys.Spawn("pricing", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		Reply(ctx, msg.(int)*2)
+		return nil
+	}))
+
+	type quote struct {
+		id    string
+		price interface{}
+	}
+
+	quotes := make(chan quote, 1)
+	var requested string
+	checkout, _ := sys.Spawn("checkout", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		switch m := msg.(type) {
+		case int:
+			id, err := Request(ctx, pricing, m, nil)
+			requested = id
+			return err
+		case Response:
+			quotes <- quote{id: m.InReplyTo, price: m.Value}
+		}
+		return nil
+	}))
+
+	checkout.Tell(21)
+	select {
+	case q := <-quotes:
+		if q.price != 42 || q.id != requested {
+			t.Error("expected the reply to be delivered to the requesting actor", q, requested)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a reply")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
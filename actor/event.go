@@ -0,0 +1,133 @@
+package actor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// EventHandler handles the events published to an EventManager; returning
+// an error causes the handler to be restarted.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event interface{}) error
+}
+
+// EventHandlerFunc adapts a function to the EventHandler interface.
+type EventHandlerFunc func(ctx context.Context, event interface{}) error
+
+// HandleEvent calls f.
+func (f EventHandlerFunc) HandleEvent(ctx context.Context, event interface{}) error {
+	return f(ctx, event)
+}
+
+// EventManager mirrors OTP's gen_event: events published to the manager are
+// fanned out to every attached handler. Handlers may be added and removed at
+// runtime, and each is run as a child actor of the manager - so a faulty
+// handler is restarted without affecting the manager, or its siblings.
+//
+// Events are delivered to each handler in the order they were published;
+// a handler whose mailbox is full blocks the manager, unless it's added with
+// an OverflowPolicy.
+type EventManager struct {
+	sys *System
+	ref *ActorRef
+
+	mu       sync.Mutex
+	handlers map[string]*ActorRef
+}
+
+// NewEventManager spawns an EventManager under the System, with the given
+// name.
+func NewEventManager(sys *System, name string, opts ...SpawnOption) (*EventManager, error) {
+	m := &EventManager{sys: sys, handlers: map[string]*ActorRef{}}
+
+	ref, err := sys.Spawn(name, ActorFunc(m.publish), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.ref = ref
+	return m, nil
+}
+
+// Ref returns the ActorRef of the manager; stopping it detaches and stops
+// every handler.
+func (m *EventManager) Ref() *ActorRef {
+	return m.ref
+}
+
+// AddHandler attaches a handler to the manager, which receives every event
+// published after it's added. Each handler is spawned as an actor named
+// after both the manager and the handler, with the given options.
+func (m *EventManager) AddHandler(name string, h EventHandler, opts ...SpawnOption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.handlers[name]; ok {
+		return fmt.Errorf("%w: %q", ErrNameTaken, name)
+	}
+
+	ref, err := m.sys.spawn(m.ref, m.handlerName(name), ActorFunc(func(ctx context.Context, msg interface{}) error {
+		return h.HandleEvent(ctx, msg)
+	}), opts...)
+	if err != nil {
+		return err
+	}
+
+	m.handlers[name] = ref
+	return nil
+}
+
+// RemoveHandler detaches, and stops, the named handler; it returns false
+// should there be no such handler.
+func (m *EventManager) RemoveHandler(name string) bool {
+	m.mu.Lock()
+	_, ok := m.handlers[name]
+	delete(m.handlers, name)
+	m.mu.Unlock()
+
+	return ok && m.sys.Stop(m.handlerName(name))
+}
+
+// Handlers returns the names of the attached handlers, sorted
+// alphabetically.
+func (m *EventManager) Handlers() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.handlers))
+	for name := range m.handlers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Notify publishes an event to every attached handler, without waiting for
+// it to be handled.
+func (m *EventManager) Notify(event interface{}) error {
+	return m.ref.Tell(event)
+}
+
+func (m *EventManager) handlerName(name string) string {
+	return fmt.Sprintf("%s/%s", m.ref.name, name)
+}
+
+// publish fans an event out to the attached handlers.
+func (m *EventManager) publish(ctx context.Context, event interface{}) error {
+	m.mu.Lock()
+	handlers := make([]*ActorRef, 0, len(m.handlers))
+	for _, ref := range m.handlers {
+		handlers = append(handlers, ref)
+	}
+	m.mu.Unlock()
+
+	for _, ref := range handlers {
+		// A handler which has since been removed is simply skipped.
+		ref.tell(ctx, event)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_EventManagerMustIsolateFaultyHandlers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mgr, err := NewEventManager(sys, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logged := make(chan interface{}, 8)
+	mgr.AddHandler("logger", EventHandlerFunc(func(ctx context.Context, event interface{}) error {
+		logged <- event
+		return nil
+	}))
+
+	mgr.AddHandler("faulty", EventHandlerFunc(func(ctx context.Context, event interface{}) error {
+		return errors.New("faulty")
+	}))
+
+	if err := mgr.AddHandler("logger", EventHandlerFunc(nil)); !errors.Is(err, ErrNameTaken) {
+		t.Error("expected handler names to be unique", err)
+	}
+
+	mgr.Notify("first")
+	mgr.Notify("second")
+	<-time.After(time.Millisecond * 50)
+
+	if len(logged) != 2 {
+		t.Error("expected every event to reach the logger", len(logged))
+	}
+
+	if restarts := len(sys.Supervisor().History("events/faulty", 0)); restarts != 2 {
+		t.Error("expected the faulty handler to be restarted", restarts)
+	}
+
+	if len(sys.Supervisor().History("events", 0)) != 0 || !mgr.Ref().Alive() {
+		t.Error("expected the manager to be unaffected by the faulty handler")
+	}
+
+	if !mgr.RemoveHandler("faulty") || len(mgr.Handlers()) != 1 {
+		t.Error("expected the handler to be removed", mgr.Handlers())
+	}
+
+	sys.Stop("events")
+	if len(sys.Actors()) != 0 {
+		t.Error("expected the handlers to be stopped with the manager", sys.Actors())
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
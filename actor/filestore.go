@@ -0,0 +1,288 @@
+package actor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrCorruptStore is returned by OpenFileMailboxStore when a record within
+// the file, other than the last, is corrupt.
+var ErrCorruptStore = errors.New("actor: corrupt mailbox store")
+
+const (
+	opAppend byte = iota + 1
+	opAck
+)
+
+// compactAfter is the number of acknowledgements after which a
+// FileMailboxStore's file is rewritten to contain only pending messages,
+// should they be outnumbered by those acknowledged.
+const compactAfter = 1024
+
+// FileMailboxStore is a MailboxStore backed by an append-only file, in which
+// each message appended and acknowledged is recorded; upon being opened the
+// file is replayed to find the pending messages, and it's periodically
+// compacted to discard those which have been acknowledged.
+//
+// Messages are encoded with encoding/gob, so their concrete types must be
+// registered via gob.Register before the store is used; as Envelopes can't
+// be encoded, messages sent by Ask, Send and Request can't be stored. Each
+// append is synced to disk before it returns, whilst acknowledgements aren't
+// - at worst, a message is redelivered.
+type FileMailboxStore struct {
+	path string
+
+	mu      sync.Mutex
+	file    *os.File
+	next    uint64
+	pending []StoredMessage
+	acked   int
+}
+
+// storedRecord is the encoded form of a message.
+type storedRecord struct {
+	Message interface{}
+}
+
+// OpenFileMailboxStore opens the store at the path, creating the file should
+// it not exist. A torn record at the end of the file, as may be left by the
+// process exiting mid-write, is discarded.
+func OpenFileMailboxStore(path string) (*FileMailboxStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileMailboxStore{path: path, file: file}
+	end, err := s.load()
+	if err == nil {
+		err = file.Truncate(end)
+	}
+
+	if err == nil {
+		_, err = file.Seek(end, io.SeekStart)
+	}
+
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Append records the message, syncing it to disk.
+func (s *FileMailboxStore) Append(msg interface{}) (uint64, error) {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(storedRecord{Message: msg}); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.next + 1
+	if err := s.write(s.file, opAppend, id, payload.Bytes()); err != nil {
+		return 0, err
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return 0, err
+	}
+
+	s.next = id
+	s.pending = append(s.pending, StoredMessage{ID: id, Message: msg})
+	return id, nil
+}
+
+// Peek returns up to n of the oldest pending messages.
+func (s *FileMailboxStore) Peek(n int) ([]StoredMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n < 1 || n > len(s.pending) {
+		n = len(s.pending)
+	}
+
+	return append([]StoredMessage(nil), s.pending[:n]...), nil
+}
+
+// Ack records the message as acknowledged, compacting the file should
+// enough messages have been acknowledged.
+func (s *FileMailboxStore) Ack(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.remove(id) {
+		return nil
+	}
+
+	if err := s.write(s.file, opAck, id, nil); err != nil {
+		return err
+	}
+
+	s.acked++
+	if s.acked >= compactAfter && s.acked > len(s.pending) {
+		return s.compact()
+	}
+
+	return nil
+}
+
+// Close closes the file.
+func (s *FileMailboxStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// remove forgets a pending message, returning false should there be none
+// with the ID.
+func (s *FileMailboxStore) remove(id uint64) bool {
+	for i, m := range s.pending {
+		if m.ID == id {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// write writes a record: its operation, ID and payload length, followed by
+// the payload and a checksum of all that precedes it.
+func (s *FileMailboxStore) write(w io.Writer, op byte, id uint64, payload []byte) error {
+	record := make([]byte, 13+len(payload)+4)
+	record[0] = op
+	binary.BigEndian.PutUint64(record[1:], id)
+	binary.BigEndian.PutUint32(record[9:], uint32(len(payload)))
+	copy(record[13:], payload)
+
+	sum := len(record) - 4
+	binary.BigEndian.PutUint32(record[sum:], crc32.ChecksumIEEE(record[:sum]))
+
+	_, err := w.Write(record)
+	return err
+}
+
+// load replays the file, returning the offset at which the last intact
+// record ends.
+func (s *FileMailboxStore) load() (int64, error) {
+	r := bufio.NewReader(s.file)
+
+	var end int64
+	for {
+		header := make([]byte, 13)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return end, nil
+		}
+
+		length := binary.BigEndian.Uint32(header[9:])
+		body := make([]byte, int(length)+4)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return end, nil
+		}
+
+		record := append(header, body[:length]...)
+		if crc32.ChecksumIEEE(record) != binary.BigEndian.Uint32(body[length:]) {
+			if _, err := r.Peek(1); err == io.EOF {
+				return end, nil
+			}
+
+			return 0, fmt.Errorf("%w: %q at offset %d", ErrCorruptStore, s.path, end)
+		}
+
+		id := binary.BigEndian.Uint64(header[1:])
+		switch header[0] {
+		case opAppend:
+			var decoded storedRecord
+			if err := gob.NewDecoder(bytes.NewReader(body[:length])).Decode(&decoded); err != nil {
+				return 0, err
+			}
+
+			s.pending = append(s.pending, StoredMessage{ID: id, Message: decoded.Message})
+			if id > s.next {
+				s.next = id
+			}
+		case opAck:
+			s.remove(id)
+		}
+
+		end += int64(len(header) + len(body))
+	}
+}
+
+// compact rewrites the file to contain only the pending messages, replacing
+// the original once the rewrite has been synced to disk.
+func (s *FileMailboxStore) compact() error {
+	tmp := s.path + ".compact"
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	for _, m := range s.pending {
+		var payload bytes.Buffer
+		if err = gob.NewEncoder(&payload).Encode(storedRecord{Message: m.Message}); err != nil {
+			break
+		}
+
+		if err = s.write(w, opAppend, m.ID, payload.Bytes()); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if err == nil {
+		err = file.Sync()
+	}
+
+	file.Close()
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// The original is closed ahead of being replaced, as an open file can't
+	// be replaced on every platform.
+	s.file.Close()
+	renamed := os.Rename(tmp, s.path)
+	if renamed != nil {
+		os.Remove(tmp)
+	}
+
+	if file, err = s.reopen(); err != nil {
+		return err
+	}
+
+	s.file, s.acked = file, 0
+	return renamed
+}
+
+// reopen opens the file for appending, following a compaction.
+func (s *FileMailboxStore) reopen() (*os.File, error) {
+	file, err := os.OpenFile(s.path, os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
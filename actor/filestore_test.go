@@ -0,0 +1,63 @@
+package actor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_FileMailboxStoreMustSurviveReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.log")
+
+	store, err := OpenFileMailboxStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range []string{"a", "b", "c"} {
+		if _, err := store.Append(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pending, _ := store.Peek(1)
+	store.Ack(pending[0].ID)
+	store.Close()
+
+	// A torn record, as left by the process exiting mid-write, is discarded.
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	file.Write([]byte{opAppend, 0, 0})
+	file.Close()
+
+	store, err = OpenFileMailboxStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, _ = store.Peek(0)
+	if len(pending) != 2 || pending[0].Message != "b" || pending[1].Message != "c" {
+		t.Fatal("expected the unacknowledged messages to be recovered", pending)
+	}
+
+	for i := 0; i < compactAfter; i++ {
+		id, _ := store.Append("x")
+		store.Ack(id)
+	}
+
+	if info, _ := os.Stat(path); info.Size() > 1024 {
+		t.Error("expected acknowledged messages to be compacted away", info.Size())
+	}
+
+	id, _ := store.Append("d")
+	store.Close()
+
+	store, err = OpenFileMailboxStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if pending, _ = store.Peek(0); len(pending) != 3 || pending[2].ID != id {
+		t.Error("expected the compacted store to be appended to", pending)
+	}
+}
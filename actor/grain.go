@@ -0,0 +1,74 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownGrain is returned by Grain when no factory has been registered
+// for the kind of grain.
+var ErrUnknownGrain = errors.New("actor: unknown grain")
+
+// DefaultGrainIdleTimeout is the IdleTimeout applied to grains, unless
+// overridden by the options given to RegisterGrain.
+const DefaultGrainIdleTimeout = 5 * time.Minute
+
+// GrainFactory creates the Actor backing the grain with the given identity.
+type GrainFactory func(id string) Actor
+
+type grainKind struct {
+	factory GrainFactory
+	opts    []SpawnOption
+}
+
+// RegisterGrain registers the factory for a kind of virtual actor, or
+// grain; see Grain. The options are applied to each grain of the kind.
+func (sys *System) RegisterGrain(kind string, factory GrainFactory, opts ...SpawnOption) error {
+	sys.grainsMu.Lock()
+	defer sys.grainsMu.Unlock()
+
+	if _, ok := sys.grains[kind]; ok {
+		return fmt.Errorf("%w: %q", ErrNameTaken, kind)
+	}
+
+	sys.grains[kind] = grainKind{factory: factory, opts: opts}
+	return nil
+}
+
+// Grain returns the ActorRef of the virtual actor of the given kind and
+// identity, which is named "<kind>/<id>". A grain always exists from the
+// perspective of its callers: it's created by the kind's GrainFactory upon
+// first being requested, but only activated upon being sent a message, and
+// is passivated once idle for its IdleTimeout; see IdleTimeout. Should the
+// grain have been stopped then a new one is created in its place.
+func (sys *System) Grain(kind, id string) (*ActorRef, error) {
+	sys.grainsMu.Lock()
+	defer sys.grainsMu.Unlock()
+
+	k, ok := sys.grains[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGrain, kind)
+	}
+
+	name := kind + "/" + id
+	shard := sys.registry.shard(name)
+	shard.mu.RLock()
+	ref, exists := shard.actors[name]
+	shard.mu.RUnlock()
+
+	if exists {
+		return ref, nil
+	}
+
+	opts := append([]SpawnOption{IdleTimeout(DefaultGrainIdleTimeout)}, k.opts...)
+	return sys.spawn(nil, name, k.factory(id), append(opts, startPassive())...)
+}
+
+// startPassive spawns the actor passivated, so it's only activated upon
+// being sent a message.
+func startPassive() SpawnOption {
+	return func(o *spawnOptions) {
+		o.passive = true
+	}
+}
@@ -0,0 +1,71 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type order struct {
+	id    string
+	items int
+}
+
+func (o *order) Handle(ctx context.Context, msg interface{}) error {
+	o.items++
+	Reply(ctx, o.id)
+	return nil
+}
+
+func Test_GrainsMustBeActivatedOnDemand(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := 0
+	sys.RegisterGrain("order", func(id string) Actor {
+		created++
+		return &order{id: id}
+	}, IdleTimeout(time.Millisecond*20))
+
+	if err := sys.RegisterGrain("order", nil); !errors.Is(err, ErrNameTaken) {
+		t.Error("expected grain kinds to be unique", err)
+	}
+
+	if _, err := sys.Grain("invoice", "1"); !errors.Is(err, ErrUnknownGrain) {
+		t.Error("expected ErrUnknownGrain for an unregistered kind", err)
+	}
+
+	ref, err := sys.Grain("order", "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ref.Passive() || ref.Name() != "order/42" {
+		t.Error("expected the grain to be created passivated", ref.Name())
+	}
+
+	if id, err := Ask[string](context.Background(), ref, "add"); id != "42" || err != nil {
+		t.Error("expected the grain to be activated by a message", id, err)
+	}
+
+	<-time.After(time.Millisecond * 60)
+	same, _ := sys.Grain("order", "42")
+	if same != ref || !ref.Passive() || created != 1 {
+		t.Error("expected the grain to be passivated and retain its identity", created)
+	}
+
+	sys.Stop("order/42")
+	if fresh, _ := sys.Grain("order", "42"); fresh == ref || created != 2 {
+		t.Error("expected a stopped grain to be recreated", created)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,22 @@
+module go.fergus.london/go-supervise/actor/grpcremote
+
+// The root module supports Go 1.18, but grpc requires Go 1.25.
+go 1.25.0
+
+require (
+	go.fergus.london/go-supervise v0.1.0
+	google.golang.org/grpc v1.84.0
+)
+
+require (
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The root module is replaced whilst developing within this repository;
+// the replacement is ignored by modules which depend upon this one.
+replace go.fergus.london/go-supervise => ../..
@@ -0,0 +1,183 @@
+// Package grpcremote provides an actor.Transport over gRPC, allowing the
+// actors of a System to be sent messages from other processes.
+//
+// The receiving process registers the Remote service with its gRPC server,
+// which delivers each message it receives to the named actor:
+//
+//	s := grpc.NewServer()
+//	grpcremote.Register(s, sys)
+//	go s.Serve(lis)
+//
+// Whilst the sending process creates refs for the remote actors, via a
+// Transport:
+//
+//	t := grpcremote.NewTransport(grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	defer t.Close()
+//
+//	ref, err := sys.RemoteRef(t, "billing.internal:9000", "billing")
+//	total, err := actor.Ask[int](ctx, ref, GetTotal{})
+//
+// Messages, and the values replied with, are encoded with encoding/gob -
+// so their concrete types must be registered via gob.Register in both
+// processes; actor.ClusterHeartbeat is registered already, so the transport
+// may carry an actor.Cluster's heartbeats.
+package grpcremote
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"sync"
+
+	"go.fergus.london/go-supervise/actor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// ServiceName is the name of the gRPC service.
+const ServiceName = "gosupervise.actor.Remote"
+
+const deliverMethod = "/" + ServiceName + "/Deliver"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+	gob.Register(actor.ClusterHeartbeat{})
+}
+
+// codec encodes the service's messages with encoding/gob, as they carry
+// arbitrary Go values rather than protocol buffers.
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (codec) Name() string {
+	return "actorgob"
+}
+
+// deliverer is implemented by the service's handler.
+type deliverer interface {
+	deliver(ctx context.Context, msg *actor.RemoteMessage) (*actor.RemoteReply, error)
+}
+
+type server struct {
+	sys *actor.System
+}
+
+// Register registers the Remote service with the gRPC server, delivering
+// the messages it receives to the System's actors.
+func Register(s grpc.ServiceRegistrar, sys *actor.System) {
+	s.RegisterService(&serviceDesc, &server{sys: sys})
+}
+
+// deliver delivers a message, replying with any error in doing so.
+func (s *server) deliver(ctx context.Context, msg *actor.RemoteMessage) (*actor.RemoteReply, error) {
+	reply, err := s.sys.Deliver(ctx, *msg)
+	if err != nil {
+		reply = actor.NewRemoteReply(nil, err)
+	}
+
+	return &reply, nil
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*deliverer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Deliver",
+		Handler:    deliverHandler,
+	}},
+}
+
+func deliverHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	msg := new(actor.RemoteMessage)
+	if err := dec(msg); err != nil {
+		return nil, err
+	}
+
+	d := srv.(deliverer)
+	if interceptor == nil {
+		return d.deliver(ctx, msg)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: deliverMethod}
+	return interceptor(ctx, msg, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return d.deliver(ctx, req.(*actor.RemoteMessage))
+	})
+}
+
+// Transport sends messages to remote Systems over gRPC, maintaining a
+// connection to each address.
+type Transport struct {
+	opts []grpc.DialOption
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewTransport returns a Transport whose connections are created with the
+// given options, which must include the transport credentials to use.
+func NewTransport(opts ...grpc.DialOption) *Transport {
+	return &Transport{opts: opts, conns: map[string]*grpc.ClientConn{}}
+}
+
+// Send delivers a message to the System at the address, which is a gRPC
+// target such as "host:port". Should the message not expect a reply then
+// any error in delivering it is returned.
+func (t *Transport) Send(ctx context.Context, addr string, msg actor.RemoteMessage) (actor.RemoteReply, error) {
+	conn, err := t.conn(addr)
+	if err != nil {
+		return actor.RemoteReply{}, err
+	}
+
+	var reply actor.RemoteReply
+	if err := conn.Invoke(ctx, deliverMethod, &msg, &reply, grpc.CallContentSubtype(codec{}.Name())); err != nil {
+		return actor.RemoteReply{}, err
+	}
+
+	if !msg.Reply {
+		return reply, reply.Err()
+	}
+
+	return reply, nil
+}
+
+// Close closes every connection.
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var err error
+	for addr, conn := range t.conns {
+		if cerr := conn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		delete(t.conns, addr)
+	}
+
+	return err
+}
+
+func (t *Transport) conn(addr string) (*grpc.ClientConn, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if conn, ok := t.conns[addr]; ok {
+		return conn, nil
+	}
+
+	conn, err := grpc.NewClient(addr, t.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	t.conns[addr] = conn
+	return conn, nil
+}
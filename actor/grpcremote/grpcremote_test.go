@@ -0,0 +1,52 @@
+package grpcremote
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"go.fergus.london/go-supervise/actor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func Test_TransportMustDeliverToRemoteActors(t *testing.T) {
+	remote, _ := actor.NewSystem(context.Background())
+	defer remote.Shutdown(context.Background())
+
+	remote.Spawn("billing", actor.ActorFunc(func(ctx context.Context, msg interface{}) error {
+		actor.Reply(ctx, len(msg.(string)))
+		return nil
+	}))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := grpc.NewServer()
+	Register(s, remote)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	local, _ := actor.NewSystem(context.Background())
+	defer local.Shutdown(context.Background())
+
+	transport := NewTransport(grpc.WithTransportCredentials(insecure.NewCredentials()))
+	defer transport.Close()
+
+	ref, err := local.RemoteRef(transport, lis.Addr().String(), "billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := actor.Ask[int](context.Background(), ref, "invoice"); n != 7 || err != nil {
+		t.Error("expected the remote actor's reply", n, err)
+	}
+
+	_, err = transport.Send(context.Background(), lis.Addr().String(), actor.RemoteMessage{Actor: "missing", Message: "x"})
+	if !errors.Is(err, actor.ErrUnknownActor) {
+		t.Error("expected ErrUnknownActor for a missing remote actor", err)
+	}
+}
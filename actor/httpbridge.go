@@ -0,0 +1,206 @@
+package actor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// DefaultHTTPBodyLimit is the largest request body, in bytes, accepted by
+// an HTTPBridge unless another limit is set via WithBodyLimit.
+const DefaultHTTPBodyLimit = 1 << 20
+
+// HTTPBridge is an http.Handler which accepts messages for the System's
+// actors as JSON, allowing external systems - or curl, whilst debugging - to
+// drive the actors without a custom client. Each request is a POST of a
+// single HTTPMessage:
+//
+//	curl -d '{"actor": "billing", "type": "invoice", "message": {"amount": 100}}' \
+//		http://localhost:8080/actors
+//
+// Should the message's type have been registered via RegisterJSON then it's
+// decoded into that type; otherwise the actor receives the json.RawMessage.
+// Messages which don't expect a reply are accepted once enqueued, whilst
+// those which do are responded to with an HTTPReply once the actor replies.
+// Requests with a body larger than DefaultHTTPBodyLimit are rejected with
+// a 413 Request Entity Too Large.
+type HTTPBridge struct {
+	sys   *System
+	limit int64
+
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// HTTPMessage is the JSON form of a message accepted by an HTTPBridge.
+type HTTPMessage struct {
+	// Actor is the name of the receiving actor, as known to Whereis.
+	Actor string `json:"actor"`
+	// Type is the name the message's type was registered with, if any.
+	Type string `json:"type,omitempty"`
+	// Message is the message itself.
+	Message json.RawMessage `json:"message"`
+	// Reply denotes whether to wait for the actor's reply.
+	Reply bool `json:"reply,omitempty"`
+	// CorrelationID and Headers populate those of the Envelope the message
+	// is delivered within.
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+}
+
+// HTTPReply is the JSON form of an actor's reply, or of the error in
+// delivering a message.
+type HTTPReply struct {
+	Value interface{} `json:"value,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// NewHTTPBridge returns an HTTPBridge for the System's actors.
+func NewHTTPBridge(sys *System) *HTTPBridge {
+	return &HTTPBridge{sys: sys, limit: DefaultHTTPBodyLimit, types: map[string]reflect.Type{}}
+}
+
+// WithBodyLimit sets the largest request body, in bytes, which the bridge
+// accepts; it must be set before the bridge serves any requests.
+func (b *HTTPBridge) WithBodyLimit(n int64) {
+	b.limit = n
+}
+
+// RegisterJSON registers the type T under the name, so messages of that
+// type are decoded into a T.
+func RegisterJSON[T any](b *HTTPBridge, name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.types[name] = reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// ServeHTTP delivers the message carried by the request. Should the request
+// have no deadline of its own then DefaultCallTimeout applies to waiting for
+// the reply.
+func (b *HTTPBridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeReply(w, http.StatusMethodNotAllowed, HTTPReply{Error: "method not allowed"})
+		return
+	}
+
+	var in HTTPMessage
+	body := &limitedBody{Reader: http.MaxBytesReader(w, r.Body, b.limit), limit: b.limit}
+	if err := json.NewDecoder(body).Decode(&in); err != nil {
+		status := http.StatusBadRequest
+		if body.exceeded {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		writeReply(w, status, HTTPReply{Error: err.Error()})
+		return
+	}
+
+	msg, err := b.decode(in)
+	if err != nil {
+		writeReply(w, http.StatusBadRequest, HTTPReply{Error: err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultCallTimeout)
+		defer cancel()
+	}
+
+	id := newID()
+	correlation := in.CorrelationID
+	if correlation == "" {
+		correlation = id
+	}
+
+	reply, err := b.sys.Deliver(ctx, RemoteMessage{
+		Actor:         in.Actor,
+		Message:       msg,
+		ID:            id,
+		CorrelationID: correlation,
+		Headers:       in.Headers,
+		Reply:         in.Reply,
+	})
+	if err == nil {
+		err = reply.Err()
+	}
+
+	switch {
+	case err != nil:
+		writeReply(w, httpStatus(err), HTTPReply{Error: err.Error()})
+	case !in.Reply:
+		writeReply(w, http.StatusAccepted, HTTPReply{})
+	default:
+		writeReply(w, http.StatusOK, HTTPReply{Value: reply.Value})
+	}
+}
+
+// limitedBody records whether the request body exceeded the limit of the
+// http.MaxBytesReader it wraps, as http.MaxBytesError isn't available
+// before Go 1.19.
+type limitedBody struct {
+	io.Reader
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += int64(n)
+	if err != nil && err != io.EOF && b.read >= b.limit {
+		b.exceeded = true
+	}
+
+	return n, err
+}
+
+// decode decodes the message into its registered type, if any.
+func (b *HTTPBridge) decode(in HTTPMessage) (interface{}, error) {
+	if in.Type == "" {
+		return in.Message, nil
+	}
+
+	b.mu.RLock()
+	typ, ok := b.types[in.Type]
+	b.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("actor: unregistered message type %q", in.Type)
+	}
+
+	msg := reflect.New(typ)
+	if err := json.Unmarshal(in.Message, msg.Interface()); err != nil {
+		return nil, err
+	}
+
+	return msg.Elem().Interface(), nil
+}
+
+// httpStatus returns the status with which to respond to an error.
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrUnknownActor):
+		return http.StatusNotFound
+	case errors.Is(err, ErrStopped), errors.Is(err, ErrMailboxFull):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrTimeout), errors.Is(err, ErrDeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusInternalServerError
+}
+
+func writeReply(w http.ResponseWriter, status int, reply HTTPReply) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(reply)
+}
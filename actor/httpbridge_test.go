@@ -0,0 +1,82 @@
+package actor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type invoice struct {
+	Amount int `json:"amount"`
+}
+
+func Test_HTTPBridgeMustDeliverJSONMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan interface{}, 10)
+	sys.Spawn("billing", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		received <- msg
+		if inv, ok := msg.(invoice); ok {
+			Reply(ctx, inv.Amount*2)
+		}
+		return nil
+	}))
+
+	bridge := NewHTTPBridge(sys)
+	RegisterJSON[invoice](bridge, "invoice")
+
+	post := func(body string) (int, HTTPReply) {
+		w := httptest.NewRecorder()
+		bridge.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/actors", strings.NewReader(body)))
+
+		var reply HTTPReply
+		json.NewDecoder(w.Body).Decode(&reply)
+		return w.Code, reply
+	}
+
+	if status, reply := post(`{"actor": "billing", "type": "invoice", "message": {"amount": 21}, "reply": true}`); status != http.StatusOK || reply.Value != 42.0 {
+		t.Error("expected the actor's reply", status, reply)
+	}
+
+	if status, _ := post(`{"actor": "billing", "message": {"raw": true}}`); status != http.StatusAccepted {
+		t.Error("expected the message to be accepted", status)
+	}
+
+	if status, _ := post(`{"actor": "missing", "message": {}}`); status != http.StatusNotFound {
+		t.Error("expected a missing actor to be reported", status)
+	}
+
+	if status, _ := post(`{"actor": "billing", "type": "unknown", "message": {}}`); status != http.StatusBadRequest {
+		t.Error("expected an unregistered type to be rejected", status)
+	}
+
+	if status, _ := post(`{"actor": "billing"`); status != http.StatusBadRequest {
+		t.Error("expected a malformed body to be rejected", status)
+	}
+
+	bridge.WithBodyLimit(64)
+	oversized := `{"actor": "billing", "message": "` + strings.Repeat("x", 64) + `"}`
+	if status, _ := post(oversized); status != http.StatusRequestEntityTooLarge {
+		t.Error("expected a body over the limit to be rejected", status)
+	}
+
+	<-time.After(time.Millisecond * 20)
+	<-received
+	if raw, ok := (<-received).(json.RawMessage); !ok || string(raw) != `{"raw": true}` {
+		t.Error("expected an untyped message to be delivered raw", raw)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,83 @@
+package actor
+
+import "context"
+
+// Interceptor wraps the Actor handling each message, returning an Actor
+// which must pass the message on to the one it wraps; it's the extension
+// point for logging, metrics, authorisation checks and tracing, without the
+// actors themselves needing to be aware of it. Messages sent within an
+// Envelope are passed on unwrapped, with the Envelope available via
+// CurrentEnvelope; batches handled by a BatchHandler aren't intercepted.
+type Interceptor func(next Actor) Actor
+
+// Enqueue delivers a message to an actor's mailbox.
+type Enqueue func(ctx context.Context, ref *ActorRef, msg interface{}) error
+
+// EnqueueInterceptor wraps the delivery of each message sent to an actor,
+// returning an Enqueue which should pass the message on to the one it
+// wraps; returning an error instead rejects the message, and is returned to
+// the sender.
+type EnqueueInterceptor func(next Enqueue) Enqueue
+
+// Interceptors sets the Interceptors applied to the actor, within those of
+// the System; see System.Intercept. The first Interceptor is the outermost,
+// so it observes each message before any of the others.
+func Interceptors(interceptors ...Interceptor) SpawnOption {
+	return func(o *spawnOptions) {
+		o.interceptors = append(o.interceptors, interceptors...)
+	}
+}
+
+// EnqueueInterceptors sets the EnqueueInterceptors applied to messages sent
+// to the actor, within those of the System; see System.InterceptEnqueue.
+// The first EnqueueInterceptor is the outermost.
+func EnqueueInterceptors(interceptors ...EnqueueInterceptor) SpawnOption {
+	return func(o *spawnOptions) {
+		o.enqueueInterceptors = append(o.enqueueInterceptors, interceptors...)
+	}
+}
+
+// Intercept adds Interceptors which are applied to every actor spawned by
+// the System afterwards, outside of any given to the actor itself.
+func (sys *System) Intercept(interceptors ...Interceptor) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	sys.interceptors = append(sys.interceptors, interceptors...)
+}
+
+// InterceptEnqueue adds EnqueueInterceptors which are applied to every
+// actor spawned by the System afterwards, outside of any given to the actor
+// itself.
+func (sys *System) InterceptEnqueue(interceptors ...EnqueueInterceptor) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	sys.enqueueInterceptors = append(sys.enqueueInterceptors, interceptors...)
+}
+
+// intercepted applies the runtime's Interceptors to the Actor.
+func (r *runtime) intercepted(a Actor) Actor {
+	for i := len(r.interceptors) - 1; i >= 0; i-- {
+		a = r.interceptors[i](a)
+	}
+
+	return a
+}
+
+// chainEnqueue applies the EnqueueInterceptors to the delivery of messages
+// to the actor's mailbox, returning nil should there be none.
+func chainEnqueue(interceptors []EnqueueInterceptor) Enqueue {
+	if len(interceptors) == 0 {
+		return nil
+	}
+
+	enqueue := Enqueue(func(ctx context.Context, ref *ActorRef, msg interface{}) error {
+		return ref.deliver(ctx, msg)
+	})
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		enqueue = interceptors[i](enqueue)
+	}
+
+	return enqueue
+}
@@ -0,0 +1,76 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_InterceptorsMustWrapHandleAndEnqueue(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu    sync.Mutex
+		trace []string
+	)
+	record := func(entry string) {
+		mu.Lock()
+		defer mu.Unlock()
+		trace = append(trace, entry)
+	}
+
+	tracing := func(label string) Interceptor {
+		return func(next Actor) Actor {
+			return ActorFunc(func(ctx context.Context, msg interface{}) error {
+				record(label)
+				return next.Handle(ctx, msg)
+			})
+		}
+	}
+
+	errForbidden := errors.New("forbidden")
+	sys.Intercept(tracing("system"))
+	sys.InterceptEnqueue(func(next Enqueue) Enqueue {
+		return func(ctx context.Context, ref *ActorRef, msg interface{}) error {
+			if msg == "secret" {
+				return errForbidden
+			}
+			return next(ctx, ref, msg)
+		}
+	})
+
+	ref, err := sys.Spawn("intercepted", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		record(msg.(string))
+		Reply(ctx, "ok")
+		return nil
+	}), Interceptors(tracing("actor")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ref.Tell("secret"); !errors.Is(err, errForbidden) {
+		t.Error("expected the enqueue interceptor to reject the message", err)
+	}
+
+	if reply, err := Ask[string](context.Background(), ref, "hello"); reply != "ok" || err != nil {
+		t.Error("expected the message to pass through the interceptors", reply, err)
+	}
+
+	mu.Lock()
+	if len(trace) != 3 || trace[0] != "system" || trace[1] != "actor" || trace[2] != "hello" {
+		t.Error("expected system interceptors to wrap those of the actor", trace)
+	}
+	mu.Unlock()
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
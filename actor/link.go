@@ -0,0 +1,164 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrLinkedExit is the reason an actor is restarted upon the abnormal exit
+// of an actor it's linked to.
+var ErrLinkedExit = errors.New("actor: linked actor exited")
+
+// Down is delivered to the actors monitoring an actor whenever it exits.
+type Down struct {
+	// Actor is the actor which exited.
+	Actor *ActorRef
+	// Reason is the error the actor failed with, in which case it's
+	// restarted; it's nil should the actor have been stopped, and therefore
+	// won't be restarted.
+	Reason error
+}
+
+// linkExit instructs an actor to exit due to the abnormal exit of an actor
+// it's linked to. Every exit caused by the same failure shares a wave, so
+// that an exit propagating around a cycle of links is acted upon only once.
+type linkExit struct {
+	reason error
+	wave   uint64
+}
+
+var waves uint64
+
+// Monitor delivers a Down message to this actor whenever the other actor
+// exits, until Demonitor is called or either actor is stopped. Should the
+// other actor already be stopped then Down is delivered immediately, with a
+// Reason of ErrStopped. Down is delivered as though by Tell, so shouldn't be
+// used for an actor to monitor itself.
+func (ref *ActorRef) Monitor(other *ActorRef) {
+	other.mu.Lock()
+	if !other.terminated {
+		other.monitors[ref] = true
+	}
+	terminated := other.terminated
+	other.mu.Unlock()
+
+	if terminated {
+		ref.Tell(Down{Actor: other, Reason: other.errStopped()})
+	}
+}
+
+// Demonitor stops this actor from monitoring the other.
+func (ref *ActorRef) Demonitor(other *ActorRef) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+
+	delete(other.monitors, ref)
+}
+
+// Link links this actor with the other, such that should either fail then
+// the other is restarted too, with a reason wrapping ErrLinkedExit; the
+// restart propagates in turn to any actors linked to it. Stopping an actor
+// isn't a failure, and so doesn't propagate.
+func (ref *ActorRef) Link(other *ActorRef) {
+	ref.mu.Lock()
+	ref.links[other] = true
+	ref.mu.Unlock()
+
+	other.mu.Lock()
+	other.links[ref] = true
+	other.mu.Unlock()
+}
+
+// Unlink removes the link between this actor and the other.
+func (ref *ActorRef) Unlink(other *ActorRef) {
+	ref.mu.Lock()
+	delete(ref.links, other)
+	ref.mu.Unlock()
+
+	other.mu.Lock()
+	delete(other.links, ref)
+	other.mu.Unlock()
+}
+
+// exited notifies the actor's monitors and links of it exiting; a wave of
+// zero denotes the exit didn't originate from a linked actor.
+func (ref *ActorRef) exited(reason error, wave uint64) {
+	ref.mu.Lock()
+	if wave == 0 {
+		wave = atomic.AddUint64(&waves, 1)
+	}
+	ref.wave = wave
+
+	monitors := make([]*ActorRef, 0, len(ref.monitors))
+	for m := range ref.monitors {
+		monitors = append(monitors, m)
+	}
+
+	links := make([]*ActorRef, 0, len(ref.links))
+	for l := range ref.links {
+		links = append(links, l)
+	}
+
+	if reason == nil {
+		ref.terminated = true
+		ref.monitors = map[*ActorRef]bool{}
+		ref.links = map[*ActorRef]bool{}
+	}
+	ref.mu.Unlock()
+
+	for _, m := range monitors {
+		if !m.notifyDown(Down{Actor: ref, Reason: reason}) {
+			m.Demonitor(ref)
+		}
+	}
+
+	if reason == nil {
+		for _, l := range links {
+			l.Unlink(ref)
+		}
+		return
+	}
+
+	ref.escalate(reason, wave)
+
+	exit := linkExit{reason: fmt.Errorf("%w: %q: %v", ErrLinkedExit, ref.name, reason), wave: wave}
+	for _, l := range links {
+		l.kill(exit)
+	}
+}
+
+// kill causes the actor to exit due to a linked actor, unless it has
+// already exited as part of the same wave.
+func (ref *ActorRef) kill(exit linkExit) {
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	if ref.terminated || ref.wave == exit.wave {
+		return
+	}
+	ref.wave = exit.wave
+
+	select {
+	case ref.exits <- exit:
+	default:
+		// An exit is already pending.
+	}
+}
+
+// notifyDown delivers Down to the monitoring actor without blocking the
+// actor which exited - so actors monitoring one another can't deadlock -
+// routing it to the dead letters should the monitor's mailbox be full. It
+// returns false should the monitor have stopped.
+func (ref *ActorRef) notifyDown(down Down) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ref.tell(ctx, down)
+	if errors.Is(err, context.Canceled) {
+		ref.drop(down)
+	}
+
+	return !errors.Is(err, ErrStopped)
+}
@@ -0,0 +1,210 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_MonitorMustDeliverDownUponExit(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("failed")
+	worker, _ := sys.Spawn("worker", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		return failure
+	}))
+
+	downs := make(chan Down, 4)
+	watcher, _ := sys.Spawn("watcher", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		if down, ok := msg.(Down); ok {
+			downs <- down
+		}
+		return nil
+	}))
+
+	watcher.Monitor(worker)
+	worker.Tell("fail")
+
+	select {
+	case down := <-downs:
+		if down.Actor != worker || !errors.Is(down.Reason, failure) {
+			t.Error("expected Down with the reason for the failure", down)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Down upon the actor failing")
+	}
+
+	sys.Stop("worker")
+	select {
+	case down := <-downs:
+		if down.Reason != nil {
+			t.Error("expected Down without a reason upon the actor stopping", down.Reason)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Down upon the actor stopping")
+	}
+
+	watcher.Monitor(worker)
+	select {
+	case down := <-downs:
+		if !errors.Is(down.Reason, ErrStopped) {
+			t.Error("expected Down with ErrStopped for a stopped actor", down.Reason)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Down upon monitoring a stopped actor")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_MonitorMustBeRemovedOnceStopped(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var downs int32
+	sys.OnDeadLetter(func(letter DeadLetter) {
+		if _, ok := letter.Message.(Down); ok {
+			atomic.AddInt32(&downs, 1)
+		}
+	})
+
+	worker, _ := sys.Spawn("worker", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		return errors.New("failed")
+	}))
+	watcher, _ := sys.Spawn("watcher", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		return nil
+	}))
+
+	watcher.Monitor(worker)
+	sys.Stop("watcher")
+
+	for i := 0; i < 3; i++ {
+		worker.Tell("fail")
+		<-time.After(time.Millisecond * 20)
+	}
+
+	if n := atomic.LoadInt32(&downs); n != 1 {
+		t.Error("expected the stopped monitor to only be sent a single Down", n)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_MonitorMustNotBlockUponFullMailbox(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dropped int32
+	sys.OnDeadLetter(func(letter DeadLetter) {
+		if _, ok := letter.Message.(Down); ok && errors.Is(letter.Reason, ErrMailboxFull) {
+			atomic.AddInt32(&dropped, 1)
+		}
+	})
+
+	release := make(chan struct{})
+	watcher, _ := sys.Spawn("watcher", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		<-release
+		return nil
+	}), MailboxSize(1))
+
+	worker, _ := sys.Spawn("worker", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		if msg == "fail" {
+			return errors.New("failed")
+		}
+		Reply(ctx, msg)
+		return nil
+	}))
+
+	watcher.Monitor(worker)
+	watcher.Tell("busy")
+	<-time.After(time.Millisecond * 20)
+	watcher.Tell("queued")
+
+	worker.Tell("fail")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if reply, err := Ask[string](ctx, worker, "ping"); reply != "ping" || err != nil {
+		t.Error("expected the worker to restart whilst its monitor's mailbox is full", reply, err)
+	}
+
+	if n := atomic.LoadInt32(&dropped); n != 1 {
+		t.Error("expected the Down to be routed to the dead letters", n)
+	}
+
+	close(release)
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_LinkMustPropagateAbnormalExits(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("failed")
+	a, _ := sys.Spawn("a", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		return failure
+	}))
+
+	idle := ActorFunc(func(ctx context.Context, msg interface{}) error { return nil })
+	b, _ := sys.Spawn("b", idle)
+	c, _ := sys.Spawn("c", idle)
+
+	// A cycle of links, through which an exit must propagate only once.
+	a.Link(b)
+	b.Link(c)
+	c.Link(a)
+
+	a.Tell("fail")
+	<-time.After(time.Millisecond * 100)
+
+	for _, ref := range []*ActorRef{a, b, c} {
+		info, _ := ref.Info()
+		if info.Restarts != 1 {
+			t.Error("expected the exit to restart each linked actor once", ref.Name(), info.Restarts)
+		}
+	}
+
+	history := sys.Supervisor().History("b", 0)
+	if len(history) != 1 {
+		t.Fatal("expected a single exit for the linked actor", history)
+	}
+
+	if reason, _ := history[0].Reason.(error); !errors.Is(reason, ErrLinkedExit) {
+		t.Error("expected the linked actor to exit with ErrLinkedExit", history[0].Reason)
+	}
+
+	b.Unlink(c)
+	c.Unlink(a)
+	sys.Stop("a")
+	<-time.After(time.Millisecond * 50)
+
+	if info, _ := b.Info(); info.Restarts != 1 || !b.Alive() {
+		t.Error("expected stopping an actor to not propagate", info.Restarts)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
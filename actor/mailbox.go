@@ -0,0 +1,188 @@
+package actor
+
+import (
+	"sync"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// rateWindow is the interval over which a Mailbox's enqueue rate is
+// measured.
+const rateWindow = time.Second
+
+// Mailbox holds the messages waiting to be handled by an actor; it outlives
+// any individual run of the actor, so messages aren't lost upon a restart.
+//
+// Messages sent via an ActorRef are instrumented, with the Mailbox recording
+// how many are enqueued - and at what rate - along with how long each waits
+// before being handled; see Stats. Messages sent to the channels directly
+// are handled as normal, but aren't measured.
+type Mailbox struct {
+	// Messages receives the messages to be handled by the Actor.
+	Messages chan interface{}
+	// Control receives ControlMessages, which take precedence over
+	// Messages.
+	Control chan ControlMessage
+	// Urgent receives High priority messages, which take precedence over
+	// Messages; it's nil unless created by NewPriorityMailbox.
+	Urgent chan interface{}
+
+	mu           sync.Mutex
+	enqueued     int
+	dequeued     int
+	totalLatency time.Duration
+	lastLatency  time.Duration
+	maxLatency   time.Duration
+	window       time.Time
+	windowCount  int
+	rate         float64
+	clock        supervisor.Clock
+}
+
+// MailboxStats contains the statistics for a Mailbox.
+type MailboxStats struct {
+	// Depth is the number of messages waiting in the mailbox, of any
+	// Priority.
+	Depth int
+	// Capacity is the number of messages the mailbox can hold, of any
+	// Priority.
+	Capacity int
+	// Enqueued is the number of messages which have been enqueued.
+	Enqueued int
+	// Rate is the number of messages enqueued per second, measured over
+	// the most recent second.
+	Rate float64
+	// LastLatency is how long the most recently handled message waited in
+	// the mailbox.
+	LastLatency time.Duration
+	// MeanLatency is the average time messages have waited in the mailbox.
+	MeanLatency time.Duration
+	// MaxLatency is the longest time a message has waited in the mailbox.
+	MaxLatency time.Duration
+}
+
+// MailboxMetrics may be implemented by the supervisor.Metrics given to a
+// System, in which case it's also notified of messages passing through the
+// mailboxes of the System's actors.
+type MailboxMetrics interface {
+	// MessageEnqueued is called once a message has been enqueued, with the
+	// resulting depth of the mailbox.
+	MessageEnqueued(actor string, depth int)
+	// MessageDequeued is called as a message is about to be handled, with
+	// how long it waited in the mailbox.
+	MessageDequeued(actor string, latency time.Duration)
+}
+
+// queued wraps a message sent via an ActorRef, recording when it was sent.
+type queued struct {
+	msg interface{}
+	at  time.Time
+}
+
+// NewMailbox returns a Mailbox which can hold size messages; values below
+// zero are treated as zero.
+func NewMailbox(size int) *Mailbox {
+	if size < 0 {
+		size = 0
+	}
+
+	return &Mailbox{
+		Messages: make(chan interface{}, size),
+		Control:  make(chan ControlMessage, 1),
+	}
+}
+
+// Stats returns the statistics for the Mailbox.
+func (m *Mailbox) Stats() MailboxStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rollLocked(m.now())
+	depth, capacity := m.depth()
+	stats := MailboxStats{
+		Depth:       depth,
+		Capacity:    capacity,
+		Enqueued:    m.enqueued,
+		Rate:        m.rate,
+		LastLatency: m.lastLatency,
+		MaxLatency:  m.maxLatency,
+	}
+
+	if m.dequeued > 0 {
+		stats.MeanLatency = m.totalLatency / time.Duration(m.dequeued)
+	}
+
+	return stats
+}
+
+// depth returns the number of messages waiting in the Mailbox, along with
+// the number it can hold.
+func (m *Mailbox) depth() (int, int) {
+	return len(m.Messages) + len(m.Urgent), cap(m.Messages) + cap(m.Urgent)
+}
+
+// now returns the current time upon the Clock of the System the Mailbox
+// belongs to, should it belong to one.
+func (m *Mailbox) now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+
+	return m.clock.Now()
+}
+
+func (m *Mailbox) recordEnqueue(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rollLocked(now)
+	m.enqueued++
+	m.windowCount++
+}
+
+// recordDequeue unwraps a message received from the Mailbox, recording how
+// long it waited should it have been sent via an ActorRef.
+func (m *Mailbox) recordDequeue(msg interface{}) (interface{}, time.Duration, bool) {
+	q, ok := msg.(queued)
+	if !ok {
+		return msg, 0, false
+	}
+
+	latency := m.now().Sub(q.at)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dequeued++
+	m.totalLatency += latency
+	m.lastLatency = latency
+	if latency > m.maxLatency {
+		m.maxLatency = latency
+	}
+
+	return q.msg, latency, true
+}
+
+// rollLocked begins a new rate window should the current one have elapsed.
+func (m *Mailbox) rollLocked(now time.Time) {
+	if m.window.IsZero() {
+		m.window = now
+		return
+	}
+
+	if elapsed := now.Sub(m.window); elapsed >= rateWindow {
+		m.rate = float64(m.windowCount) / elapsed.Seconds()
+		m.window, m.windowCount = now, 0
+	}
+}
+
+// unwrap returns a message as it was sent, should it have been sent via an
+// ActorRef.
+func unwrap(msg interface{}) interface{} {
+	if q, ok := msg.(queued); ok {
+		return q.msg
+	}
+
+	return msg
+}
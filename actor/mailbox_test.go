@@ -0,0 +1,111 @@
+package actor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+type mailboxMetrics struct {
+	mu       sync.Mutex
+	enqueued int
+	latency  time.Duration
+}
+
+func (m *mailboxMetrics) WorkerRestarted(supervisor.WorkerInfo) {}
+
+func (m *mailboxMetrics) MessageEnqueued(actor string, depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enqueued++
+}
+
+func (m *mailboxMetrics) MessageDequeued(actor string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency += latency
+}
+
+func Test_MailboxMustRecordDepthAndLatency(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	metrics := &mailboxMetrics{}
+	sys, err := NewSystem(context.Background(), SystemMetrics(metrics))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	ref, _ := sys.Spawn("slow", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		<-release
+		return nil
+	}), MailboxSize(8))
+
+	for i := 0; i < 5; i++ {
+		ref.Tell(i)
+	}
+	<-time.After(time.Millisecond * 20)
+
+	stats := ref.MailboxStats()
+	if stats.Enqueued != 5 || stats.Depth != 4 || stats.Capacity != 8 {
+		t.Error("expected the mailbox to record enqueued messages", stats)
+	}
+
+	if info, _ := ref.Info(); info.QueueDepth != 4 || info.QueueCapacity != 8 {
+		t.Error("expected the mailbox depth to be observed by the Supervisor", info)
+	}
+
+	close(release)
+	<-time.After(time.Millisecond * 20)
+
+	stats = ref.MailboxStats()
+	if stats.Depth != 0 || stats.MaxLatency < time.Millisecond*20 || stats.MeanLatency == 0 {
+		t.Error("expected the mailbox to record queueing latency", stats)
+	}
+
+	metrics.mu.Lock()
+	if metrics.enqueued != 5 || metrics.latency == 0 {
+		t.Error("expected MailboxMetrics to be notified", metrics.enqueued, metrics.latency)
+	}
+	metrics.mu.Unlock()
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_MailboxLatencyMustFollowTheSystemClock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	clock := supervisor.NewFakeClock(time.Unix(0, 0))
+	sys, err := NewSystem(context.Background(), SystemClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	ref, _ := sys.Spawn("slow", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		if msg == "first" {
+			<-release
+		}
+		return nil
+	}))
+
+	ref.Tell("first")
+	<-time.After(time.Millisecond * 20)
+	ref.Tell("second")
+
+	clock.Advance(time.Minute)
+	close(release)
+	<-time.After(time.Millisecond * 20)
+
+	if stats := ref.MailboxStats(); stats.MaxLatency != time.Minute {
+		t.Error("expected the latency to be measured upon the clock", stats)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,22 @@
+module go.fergus.london/go-supervise/actor/natsremote
+
+// The root module supports Go 1.18, but nats.go requires Go 1.23.
+go 1.23.0
+
+require (
+	github.com/nats-io/nats.go v1.48.0
+	go.fergus.london/go-supervise v0.1.0
+)
+
+require (
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/nats-io/nkeys v0.4.11 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.37.0 // indirect
+	golang.org/x/sys v0.32.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The root module is replaced whilst developing within this repository;
+// the replacement is ignored by modules which depend upon this one.
+replace go.fergus.london/go-supervise => ../..
@@ -0,0 +1,159 @@
+// Package natsremote provides an actor.Transport over NATS, in which the
+// mailbox of each actor maps to a subject: messages for the actor "billing"
+// of the System serving the prefix "orders" are published to the subject
+// "orders.billing". This makes Tell and Ask location transparent across a
+// NATS cluster, whilst the receiving System applies the supervision and
+// delivery policies of its actors as usual.
+//
+// The receiving process serves its System under a prefix:
+//
+//	sub, err := natsremote.Serve(nc, sys, "orders")
+//	defer sub.Unsubscribe()
+//
+// Whilst the sending process creates refs for the remote actors, with the
+// prefix as their address:
+//
+//	ref, err := sys.RemoteRef(natsremote.NewTransport(nc), "orders", "billing")
+//	total, err := actor.Ask[int](ctx, ref, GetTotal{})
+//
+// Messages, and the values replied with, are encoded with encoding/gob -
+// so their concrete types must be registered via gob.Register in both
+// processes; actor.ClusterHeartbeat is registered already, so the transport
+// may carry an actor.Cluster's heartbeats. Messages which don't expect a reply are published without
+// waiting for them to be delivered, so errors in delivering them aren't
+// reported to the sender.
+package natsremote
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"go.fergus.london/go-supervise/actor"
+)
+
+func init() {
+	gob.Register(actor.ClusterHeartbeat{})
+}
+
+// Option configures how a System is served.
+type Option func(*options)
+
+type options struct {
+	queue string
+}
+
+// QueueGroup serves the System as a member of the NATS queue group, so that
+// each message is delivered to only one of the processes serving the
+// prefix; it suits replicas of a stateless actor.
+func QueueGroup(name string) Option {
+	return func(o *options) {
+		o.queue = name
+	}
+}
+
+// Serve subscribes to the subjects of every actor of the System under the
+// prefix, delivering the messages published to them; messages expecting a
+// reply are responded to once the actor replies, or DefaultCallTimeout has
+// passed. Actors whose names contain the characters '.', '*' or '>' can't
+// be sent messages, as they're special to NATS subjects.
+func Serve(nc *nats.Conn, sys *actor.System, prefix string, opts ...Option) (*nats.Subscription, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	deliver := func(m *nats.Msg) {
+		var msg actor.RemoteMessage
+		if err := decode(m.Data, &msg); err != nil {
+			respond(m, actor.NewRemoteReply(nil, err))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), actor.DefaultCallTimeout)
+		defer cancel()
+
+		msg.Actor = strings.TrimPrefix(m.Subject, prefix+".")
+		reply, err := sys.Deliver(ctx, msg)
+		if err != nil {
+			reply = actor.NewRemoteReply(nil, err)
+		}
+
+		respond(m, reply)
+	}
+
+	// A subscription's messages are handled one at a time, so those which
+	// await a reply are delivered separately lest a slow actor hold up the
+	// rest.
+	handler := func(m *nats.Msg) {
+		if m.Reply != "" {
+			go deliver(m)
+			return
+		}
+
+		deliver(m)
+	}
+
+	subject := prefix + ".>"
+	if o.queue != "" {
+		return nc.QueueSubscribe(subject, o.queue, handler)
+	}
+
+	return nc.Subscribe(subject, handler)
+}
+
+// Transport sends messages to remote Systems over a NATS connection; the
+// address of a System is the prefix it's served under.
+type Transport struct {
+	nc *nats.Conn
+}
+
+// NewTransport returns a Transport publishing via the connection.
+func NewTransport(nc *nats.Conn) *Transport {
+	return &Transport{nc: nc}
+}
+
+// Send publishes a message to the subject of the actor under the prefix,
+// waiting for the reply should the message expect one.
+func (t *Transport) Send(ctx context.Context, prefix string, msg actor.RemoteMessage) (actor.RemoteReply, error) {
+	data, err := encode(msg)
+	if err != nil {
+		return actor.RemoteReply{}, err
+	}
+
+	subject := prefix + "." + msg.Actor
+	if !msg.Reply {
+		return actor.RemoteReply{}, t.nc.Publish(subject, data)
+	}
+
+	resp, err := t.nc.RequestWithContext(ctx, subject, data)
+	if err != nil {
+		return actor.RemoteReply{}, err
+	}
+
+	var reply actor.RemoteReply
+	err = decode(resp.Data, &reply)
+	return reply, err
+}
+
+func respond(m *nats.Msg, reply actor.RemoteReply) {
+	if m.Reply == "" {
+		return
+	}
+
+	if data, err := encode(reply); err == nil {
+		m.Respond(data)
+	}
+}
+
+func encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func decode(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,55 @@
+package natsremote
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.fergus.london/go-supervise/actor"
+)
+
+// Test_TransportMustDeliverToRemoteActors requires a NATS server, whose URL
+// is given by NATS_URL.
+func Test_TransportMustDeliverToRemoteActors(t *testing.T) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		t.Skip("NATS_URL isn't set")
+	}
+
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+
+	remote, _ := actor.NewSystem(context.Background())
+	defer remote.Shutdown(context.Background())
+
+	remote.Spawn("billing", actor.ActorFunc(func(ctx context.Context, msg interface{}) error {
+		actor.Reply(ctx, len(msg.(string)))
+		return nil
+	}))
+
+	sub, err := Serve(nc, remote, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	local, _ := actor.NewSystem(context.Background())
+	defer local.Shutdown(context.Background())
+
+	ref, err := local.RemoteRef(NewTransport(nc), "orders", "billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if n, err := actor.Ask[int](ctx, ref, "invoice"); n != 7 || err != nil {
+		t.Error("expected the remote actor's reply", n, err)
+	}
+}
@@ -0,0 +1,94 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrMailboxFull is returned when sending to an actor whose mailbox is full,
+// should the actor have been spawned with the Reject overflow policy. It's
+// also the reply to an Ask whose message is dropped.
+var ErrMailboxFull = errors.New("actor: mailbox full")
+
+// Overflow determines what happens to a message sent to an actor whose
+// mailbox is full.
+type Overflow int
+
+const (
+	// Block waits for space in the mailbox; it's the default.
+	Block Overflow = iota
+	// DropNewest drops the message being sent.
+	DropNewest
+	// DropOldest drops the oldest message waiting in the mailbox, to make
+	// space for the message being sent. A mailbox without any capacity has
+	// no oldest message to drop, so the message being sent is dropped
+	// instead, as with DropNewest.
+	DropOldest
+	// Reject drops the message being sent, returning ErrMailboxFull to the
+	// sender.
+	Reject
+)
+
+// OverflowPolicy sets what happens to messages sent to the actor whilst its
+// mailbox is full. Dropped messages are counted by Dropped, and routed to
+// the System's dead letters; see OnDeadLetter.
+func OverflowPolicy(overflow Overflow) SpawnOption {
+	return func(o *spawnOptions) {
+		o.overflow = overflow
+	}
+}
+
+// Dropped returns the number of messages sent to the actor which have been
+// dropped due to its overflow policy.
+func (ref *ActorRef) Dropped() int {
+	return int(atomic.LoadUint64(&ref.dropped))
+}
+
+// offer delivers a message to the actor's mailbox according to its overflow
+// policy, returning false should the policy be Block and the mailbox full.
+func (ref *ActorRef) offer(lane chan interface{}, msg interface{}) (bool, error) {
+	select {
+	case lane <- msg:
+		ref.enqueued()
+		return true, nil
+	default:
+	}
+
+	overflow := ref.overflow
+	if overflow == DropOldest && cap(lane) == 0 {
+		overflow = DropNewest
+	}
+
+	switch overflow {
+	case DropNewest:
+		ref.drop(msg)
+		return true, nil
+	case Reject:
+		ref.drop(msg)
+		return true, fmt.Errorf("%w: %q", ErrMailboxFull, ref.name)
+	case DropOldest:
+		for {
+			select {
+			case lane <- msg:
+				ref.enqueued()
+				return true, nil
+			default:
+			}
+
+			select {
+			case oldest := <-lane:
+				ref.drop(oldest)
+			default:
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// drop records a message being dropped due to the overflow policy.
+func (ref *ActorRef) drop(msg interface{}) {
+	atomic.AddUint64(&ref.dropped, 1)
+	ref.deadLetter(msg, fmt.Errorf("%w: %q", ErrMailboxFull, ref.name))
+}
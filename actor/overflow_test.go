@@ -0,0 +1,123 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_OverflowPoliciesMustDropMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var letters []DeadLetter
+	sys.OnDeadLetter(func(letter DeadLetter) {
+		letters = append(letters, letter)
+	})
+
+	release := make(chan struct{})
+	handled := make(chan interface{}, 8)
+	blocked := func(ctx context.Context, msg interface{}) error {
+		<-release
+		handled <- msg
+		return nil
+	}
+
+	oldest, _ := sys.Spawn("oldest", ActorFunc(blocked), MailboxSize(2), OverflowPolicy(DropOldest))
+	newest, _ := sys.Spawn("newest", ActorFunc(blocked), MailboxSize(1), OverflowPolicy(DropNewest))
+	reject, _ := sys.Spawn("reject", ActorFunc(blocked), MailboxSize(1), OverflowPolicy(Reject))
+
+	// The first message is held by the blocked actor, filling the mailbox
+	// with those which follow.
+	for i := 0; i < 4; i++ {
+		if err := oldest.Tell(i); err != nil {
+			t.Error("expected DropOldest to accept every message", err)
+		}
+		<-time.After(time.Millisecond * 10)
+	}
+
+	newest.Tell(0)
+	<-time.After(time.Millisecond * 10)
+	newest.Tell(1)
+	if err := newest.Tell(2); err != nil {
+		t.Error("expected DropNewest to not return an error", err)
+	}
+
+	reject.Tell(0)
+	<-time.After(time.Millisecond * 10)
+	reject.Tell(1)
+	if err := reject.Tell(2); !errors.Is(err, ErrMailboxFull) {
+		t.Error("expected Reject to return ErrMailboxFull", err)
+	}
+
+	if _, err := Ask[int](context.Background(), newest, 3); !errors.Is(err, ErrMailboxFull) {
+		t.Error("expected a dropped Ask to be replied to with ErrMailboxFull", err)
+	}
+
+	if oldest.Dropped() != 1 || newest.Dropped() != 2 || reject.Dropped() != 1 {
+		t.Error("expected dropped messages to be counted", oldest.Dropped(), newest.Dropped(), reject.Dropped())
+	}
+
+	if len(letters) != 4 || letters[0].Actor != oldest || letters[0].Message != 1 {
+		t.Error("expected dropped messages to be passed to OnDeadLetter", letters)
+	}
+
+	close(release)
+	<-time.After(time.Millisecond * 50)
+
+	var got []interface{}
+	for len(handled) > 0 {
+		if msg := <-handled; msg != nil {
+			got = append(got, msg)
+		}
+	}
+
+	if len(got) != 7 {
+		t.Error("expected the remaining messages to be handled", got)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_DropOldestMustDropNewestWithoutCapacity(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sys.OnDeadLetter(func(DeadLetter) {})
+
+	release := make(chan struct{})
+	ref, _ := sys.Spawn("unbuffered", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		<-release
+		return nil
+	}), MailboxSize(0), OverflowPolicy(DropOldest))
+
+	ref.Tell(0)
+	<-time.After(time.Millisecond * 10)
+
+	sent := make(chan error, 1)
+	go func() { sent <- ref.Tell(1) }()
+
+	select {
+	case err := <-sent:
+		if err != nil || ref.Dropped() != 1 {
+			t.Error("expected the message to be dropped", err, ref.Dropped())
+		}
+	case <-time.After(time.Second):
+		t.Error("expected Tell to return whilst the actor is busy")
+	}
+
+	close(release)
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,109 @@
+package actor
+
+import (
+	"context"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// Terminator may be implemented by an Actor to release its resources as it
+// stops handling messages; that is, upon being stopped - in which case the
+// context has already been cancelled - or passivated. See IdleTimeout.
+type Terminator interface {
+	Terminate(ctx context.Context)
+}
+
+// IdleTimeout passivates the actor should it receive no messages for the
+// given duration: Terminate is called, should the Actor implement
+// Terminator, and its worker is removed from the Supervisor. The actor's
+// ActorRef remains valid, and the next message sent to it transparently
+// reactivates it - calling Init, should the Actor implement Initialiser.
+//
+// This suits systems of many per-entity actors, most of which are idle at
+// any one time.
+func IdleTimeout(d time.Duration) SpawnOption {
+	return func(o *spawnOptions) {
+		o.idle = d
+	}
+}
+
+// Passive returns whether the actor is passivated; see IdleTimeout.
+func (ref *ActorRef) Passive() bool {
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	return ref.passive
+}
+
+// idleTimer returns a timer which fires once the actor has been idle for its
+// IdleTimeout, if any.
+func (r *runtime) idleTimer() (supervisor.Timer, <-chan time.Time) {
+	if r.idle <= 0 || r.passivated == nil {
+		return nil, nil
+	}
+
+	t := r.clock.NewTimer(r.idle)
+	return t, t.C()
+}
+
+// resetIdle restarts the idle timer, as the actor awaits its next message.
+func (r *runtime) resetIdle(t supervisor.Timer) {
+	if t == nil {
+		return
+	}
+
+	t.Reset(r.idle)
+}
+
+// terminate calls Terminate, should the Actor implement Terminator.
+func (r *runtime) terminate(ctx context.Context) {
+	if t, ok := r.actor.(Terminator); ok {
+		t.Terminate(ctx)
+	}
+}
+
+// passivate terminates the actor, and has it removed from the Supervisor;
+// it waits for the removal to cancel the context, lest it be restarted.
+func (r *runtime) passivate(ctx context.Context) {
+	r.terminate(ctx)
+	r.passivated()
+	<-ctx.Done()
+}
+
+// passivate removes a passivated actor's worker, reactivating it should a
+// message have arrived in the meantime.
+func (sys *System) passivate(ref *ActorRef) {
+	sys.root.RemoveWorker(ref.name)
+
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	if ref.terminated || !ref.Alive() {
+		return
+	}
+
+	if depth, _ := ref.mailbox.depth(); depth > 0 {
+		sys.activateLocked(ref)
+		return
+	}
+
+	ref.passive = true
+}
+
+// wake reactivates the actor should it be passivated.
+func (ref *ActorRef) wake() {
+	ref.mu.Lock()
+	defer ref.mu.Unlock()
+
+	if ref.passive {
+		ref.passive = false
+		ref.system.activateLocked(ref)
+	}
+}
+
+func (sys *System) activateLocked(ref *ActorRef) {
+	if err := sys.root.AddWorker(ref.spec); err != nil {
+		supervisor.Log("actor: failed to reactivate " + ref.name + ": " + err.Error())
+	}
+}
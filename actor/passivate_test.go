@@ -0,0 +1,67 @@
+package actor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type entity struct {
+	inits, terminations int32
+}
+
+func (e *entity) Init(ctx context.Context) error {
+	atomic.AddInt32(&e.inits, 1)
+	return nil
+}
+
+func (e *entity) Terminate(ctx context.Context) {
+	atomic.AddInt32(&e.terminations, 1)
+}
+
+func (e *entity) Handle(ctx context.Context, msg interface{}) error {
+	Reply(ctx, msg)
+	return nil
+}
+
+func Test_IdleActorsMustBePassivatedAndReactivated(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := &entity{}
+	ref, _ := sys.Spawn("order-1", e, IdleTimeout(time.Millisecond*20))
+
+	ref.Tell("created")
+	<-time.After(time.Millisecond * 60)
+
+	if !ref.Passive() || atomic.LoadInt32(&e.terminations) != 1 {
+		t.Error("expected the idle actor to be passivated", atomic.LoadInt32(&e.terminations))
+	}
+
+	if _, ok := ref.Info(); ok {
+		t.Error("expected the passivated actor's worker to be removed")
+	}
+
+	if reply, err := Ask[string](context.Background(), ref, "paid"); reply != "paid" || err != nil {
+		t.Error("expected the actor to be reactivated by a message", reply, err)
+	}
+
+	if ref.Passive() || atomic.LoadInt32(&e.inits) != 2 {
+		t.Error("expected the actor to be re-initialised", atomic.LoadInt32(&e.inits))
+	}
+
+	<-time.After(time.Millisecond * 60)
+	if !sys.Stop("order-1") {
+		t.Error("expected a passivated actor to be stopped")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,251 @@
+package actor
+
+import (
+	"context"
+	"sync"
+)
+
+// EventSourced is an Actor whose state is derived entirely from the events
+// it emits; see Persistent. Handle validates each command, emitting the
+// events which follow from it via Emit, whilst Apply updates the state with
+// a single event - and mustn't fail, as it's also used to rebuild the state
+// from the Journal.
+type EventSourced interface {
+	Actor
+	Apply(event interface{})
+}
+
+// Snapshotter may be implemented by an EventSourced actor to have its state
+// saved periodically, so it needn't be rebuilt from every event; see
+// SnapshotEvery.
+type Snapshotter interface {
+	// Snapshot returns the actor's current state.
+	Snapshot() interface{}
+	// Restore replaces the actor's state with one returned by Snapshot.
+	Restore(state interface{})
+}
+
+// Journal is the append-only store of the events emitted by EventSourced
+// actors, each of which is identified by its persistence ID. Events are
+// numbered in the order they're appended, from 1.
+type Journal interface {
+	// Append persists the events, following those already persisted.
+	Append(ctx context.Context, id string, events []interface{}) error
+	// Replay calls fn with each persisted event numbered after from, in
+	// order.
+	Replay(ctx context.Context, id string, from uint64, fn func(seq uint64, event interface{})) error
+}
+
+// SnapshotStore stores the latest snapshot of each EventSourced actor,
+// along with the number of the last event it reflects.
+type SnapshotStore interface {
+	SaveSnapshot(ctx context.Context, id string, seq uint64, state interface{}) error
+	// LoadSnapshot returns a sequence number of 0 should there be no
+	// snapshot.
+	LoadSnapshot(ctx context.Context, id string) (seq uint64, state interface{}, err error)
+}
+
+// PersistOption configures a Persistent actor.
+type PersistOption func(*persistent)
+
+// SnapshotEvery saves a snapshot of the actor's state to the store after
+// every n events, should the actor implement Snapshotter; upon starting the
+// actor is restored from the snapshot, and then only the events which
+// followed it are replayed.
+func SnapshotEvery(store SnapshotStore, n int) PersistOption {
+	return func(p *persistent) {
+		p.snapshots, p.every = store, uint64(n)
+	}
+}
+
+type emittedKey struct{}
+
+// Emit emits events from the EventSourced actor handling the current
+// command, given the context passed to Handle. Once Handle returns without
+// error the events are appended to the Journal, and only then applied to
+// the actor; should Handle fail then they're discarded. It returns false
+// should the context not belong to a Persistent actor.
+func Emit(ctx context.Context, events ...interface{}) bool {
+	emitted, ok := ctx.Value(emittedKey{}).(*[]interface{})
+	if ok {
+		*emitted = append(*emitted, events...)
+	}
+
+	return ok
+}
+
+// persistent is the Actor returned by Persistent.
+type persistent struct {
+	id        string
+	journal   Journal
+	factory   func() EventSourced
+	snapshots SnapshotStore
+	every     uint64
+
+	actor EventSourced
+	seq   uint64
+	saved uint64
+}
+
+// Persistent adapts an EventSourced actor, created by the factory, into an
+// Actor whose events are persisted to the Journal under the given ID. Each
+// time the actor is started - including upon being restarted, or
+// reactivated once passivated - a new instance is created, and its state
+// rebuilt by replaying its events.
+//
+//	ref, err := sys.Spawn("account-42", actor.Persistent("account-42", journal, func() actor.EventSourced {
+//		return &Account{}
+//	}))
+func Persistent(id string, journal Journal, factory func() EventSourced, opts ...PersistOption) Actor {
+	p := &persistent{id: id, journal: journal, factory: factory}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Init rebuilds the actor's state, before calling its own Init should it
+// implement Initialiser.
+func (p *persistent) Init(ctx context.Context) error {
+	p.actor, p.seq, p.saved = p.factory(), 0, 0
+
+	if snapshotter, ok := p.actor.(Snapshotter); ok && p.snapshots != nil {
+		seq, state, err := p.snapshots.LoadSnapshot(ctx, p.id)
+		if err != nil {
+			return err
+		}
+
+		if seq > 0 {
+			snapshotter.Restore(state)
+			p.seq, p.saved = seq, seq
+		}
+	}
+
+	err := p.journal.Replay(ctx, p.id, p.seq, func(seq uint64, event interface{}) {
+		p.actor.Apply(event)
+		p.seq = seq
+	})
+	if err != nil {
+		return err
+	}
+
+	if init, ok := p.actor.(Initialiser); ok {
+		return init.Init(ctx)
+	}
+
+	return nil
+}
+
+// Handle passes a command to the actor, persisting and then applying the
+// events it emits.
+func (p *persistent) Handle(ctx context.Context, msg interface{}) error {
+	var emitted []interface{}
+	if err := p.actor.Handle(context.WithValue(ctx, emittedKey{}, &emitted), msg); err != nil {
+		return err
+	}
+
+	if len(emitted) == 0 {
+		return nil
+	}
+
+	if err := p.journal.Append(ctx, p.id, emitted); err != nil {
+		return err
+	}
+
+	for _, event := range emitted {
+		p.actor.Apply(event)
+	}
+
+	p.seq += uint64(len(emitted))
+	return p.snapshot(ctx)
+}
+
+// Terminate calls the actor's Terminate, should it implement Terminator.
+func (p *persistent) Terminate(ctx context.Context) {
+	if t, ok := p.actor.(Terminator); ok {
+		t.Terminate(ctx)
+	}
+}
+
+// snapshot saves a snapshot should enough events have been persisted since
+// the last.
+func (p *persistent) snapshot(ctx context.Context) error {
+	snapshotter, ok := p.actor.(Snapshotter)
+	if !ok || p.snapshots == nil || p.every == 0 || p.seq-p.saved < p.every {
+		return nil
+	}
+
+	if err := p.snapshots.SaveSnapshot(ctx, p.id, p.seq, snapshotter.Snapshot()); err != nil {
+		return err
+	}
+
+	p.saved = p.seq
+	return nil
+}
+
+// MemoryJournal is an in-memory Journal and SnapshotStore, suited to tests
+// and to actors whose events needn't outlive the process.
+type MemoryJournal struct {
+	mu        sync.Mutex
+	events    map[string][]interface{}
+	snapshots map[string]memorySnapshot
+}
+
+type memorySnapshot struct {
+	seq   uint64
+	state interface{}
+}
+
+// NewMemoryJournal returns an empty MemoryJournal.
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{events: map[string][]interface{}{}, snapshots: map[string]memorySnapshot{}}
+}
+
+// Append persists the events.
+func (j *MemoryJournal) Append(ctx context.Context, id string, events []interface{}) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.events[id] = append(j.events[id], events...)
+	return nil
+}
+
+// Replay calls fn with each event numbered after from.
+func (j *MemoryJournal) Replay(ctx context.Context, id string, from uint64, fn func(seq uint64, event interface{})) error {
+	j.mu.Lock()
+	events := j.events[id]
+	j.mu.Unlock()
+
+	for i := from; i < uint64(len(events)); i++ {
+		fn(i+1, events[i])
+	}
+
+	return nil
+}
+
+// Events returns the number of events persisted under the ID.
+func (j *MemoryJournal) Events(id string) int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return len(j.events[id])
+}
+
+// SaveSnapshot replaces the snapshot.
+func (j *MemoryJournal) SaveSnapshot(ctx context.Context, id string, seq uint64, state interface{}) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.snapshots[id] = memorySnapshot{seq: seq, state: state}
+	return nil
+}
+
+// LoadSnapshot returns the snapshot, if any.
+func (j *MemoryJournal) LoadSnapshot(ctx context.Context, id string) (uint64, interface{}, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	s := j.snapshots[id]
+	return s.seq, s.state, nil
+}
@@ -0,0 +1,79 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type deposited struct{ amount int }
+
+type account struct {
+	balance  int
+	replayed int
+}
+
+func (a *account) Handle(ctx context.Context, msg interface{}) error {
+	switch amount := msg.(type) {
+	case int:
+		if amount <= 0 {
+			return errors.New("invalid amount")
+		}
+		Emit(ctx, deposited{amount})
+	case string:
+		Reply(ctx, a.balance)
+	}
+	return nil
+}
+
+func (a *account) Apply(event interface{}) {
+	a.balance += event.(deposited).amount
+	a.replayed++
+}
+
+func (a *account) Snapshot() interface{}     { return a.balance }
+func (a *account) Restore(state interface{}) { a.balance = state.(int) }
+
+func Test_PersistentActorMustBeRebuiltFromItsJournal(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	journal := NewMemoryJournal()
+	var latest *account
+	ref, err := sys.Spawn("account", Persistent("account-1", journal, func() EventSourced {
+		latest = &account{}
+		return latest
+	}, SnapshotEvery(journal, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, amount := range []int{10, 20, 0, 5} {
+		ref.Tell(amount)
+		<-time.After(time.Millisecond * 20)
+	}
+
+	if balance, err := Ask[int](context.Background(), ref, "balance"); balance != 35 || err != nil {
+		t.Error("expected the state to survive the failed command", balance, err)
+	}
+
+	if journal.Events("account-1") != 3 {
+		t.Error("expected only the emitted events to be journalled", journal.Events("account-1"))
+	}
+
+	ref.TellControl(Restart)
+	<-time.After(time.Millisecond * 50)
+	if balance, _ := Ask[int](context.Background(), ref, "balance"); balance != 35 || latest.replayed != 1 {
+		t.Error("expected the state to be restored from the snapshot and later events", balance, latest.replayed)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
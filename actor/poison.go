@@ -0,0 +1,50 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPoisoned is the reason a message is routed to the dead letters, upon
+// having caused the actor to panic too many times; see PoisonThreshold.
+var ErrPoisoned = errors.New("actor: poison message")
+
+// PoisonThreshold redelivers a message which causes the actor to panic to
+// the restarted actor, rather than it being lost, until it has caused n
+// consecutive panics; it's then quarantined to the dead letters with a
+// reason wrapping ErrPoisoned, so a single malformed message can't trap the
+// actor in a crash loop. A message sent by Ask is replied to once it's
+// either handled or quarantined.
+//
+// By default - or with a threshold below 1 - a message which causes a panic
+// is lost.
+func PoisonThreshold(n int) SpawnOption {
+	return func(o *spawnOptions) {
+		o.poison = n
+	}
+}
+
+// redeliver queues the message held by the previous run for redelivery, or
+// quarantines it should it have reached the PoisonThreshold.
+func (r *runtime) redeliver() {
+	if !r.holding {
+		return
+	}
+
+	msg := r.inflight
+	r.inflight, r.holding = nil, false
+	if r.poison < 1 {
+		r.settle(msg)
+		return
+	}
+
+	if r.failures >= r.poison {
+		r.failures = 0
+		if r.ref != nil {
+			r.ref.deadLetter(msg, fmt.Errorf("%w: %q", ErrPoisoned, r.ref.name))
+		}
+		return
+	}
+
+	r.replay = append([]interface{}{msg}, r.replay...)
+}
@@ -0,0 +1,78 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_PoisonMessagesMustBeQuarantined(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	letters := make(chan DeadLetter, 4)
+	sys.OnDeadLetter(func(letter DeadLetter) {
+		letters <- letter
+	})
+
+	attempts := 0
+	handled := make(chan interface{}, 4)
+	ref, _ := sys.Spawn("parser", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		switch msg {
+		case "flaky":
+			if attempts++; attempts < 2 {
+				panic("flaky")
+			}
+		case "malformed":
+			panic("malformed")
+		}
+
+		handled <- msg
+		return nil
+	}), PoisonThreshold(3))
+
+	ref.Tell("flaky")
+	ref.Tell("malformed")
+	ref.Tell("valid")
+
+	reply := make(chan error, 1)
+	go func() {
+		<-time.After(time.Millisecond * 10)
+		_, err := Ask[string](context.Background(), ref, "malformed")
+		reply <- err
+	}()
+
+	for _, expected := range []string{"flaky", "valid"} {
+		select {
+		case msg := <-handled:
+			if msg != expected {
+				t.Error("expected messages to be handled in order", msg, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the actor to move past the poison message")
+		}
+	}
+
+	if err := <-reply; !errors.Is(err, ErrPoisoned) {
+		t.Error("expected the Ask to be replied to with ErrPoisoned", err)
+	}
+
+	letter := <-letters
+	if letter.Message != "malformed" || !errors.Is(letter.Reason, ErrPoisoned) {
+		t.Error("expected the poison message to be quarantined", letter)
+	}
+
+	if restarts := len(sys.Supervisor().History("parser", 0)); restarts != 7 {
+		t.Error("expected the poison message to be retried up to the threshold", restarts)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
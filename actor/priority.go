@@ -0,0 +1,67 @@
+package actor
+
+// Priority determines the order in which an actor handles the messages
+// waiting in a priority mailbox.
+type Priority int
+
+const (
+	// Normal messages are handled in the order they're sent.
+	Normal Priority = iota
+	// High messages are handled ahead of any Normal messages waiting in a
+	// priority mailbox, though after any ControlMessages.
+	High
+)
+
+// Prioritised is implemented by messages which carry a Priority; a message
+// sent by Ask is given the Priority of the message its Envelope carries.
+// Messages which don't implement it have a Priority of Normal.
+type Prioritised interface {
+	Priority() Priority
+}
+
+// NewPriorityMailbox returns a Mailbox which can hold size Normal messages,
+// along with urgent High priority messages. As with ControlMessages, High
+// priority messages preempt those waiting in Messages, so a flooded actor
+// can still be reconfigured promptly.
+func NewPriorityMailbox(size, urgent int) *Mailbox {
+	if urgent < 0 {
+		urgent = 0
+	}
+
+	m := NewMailbox(size)
+	m.Urgent = make(chan interface{}, urgent)
+	return m
+}
+
+// PriorityMailbox gives the actor a priority mailbox, which can hold urgent
+// High priority messages in addition to those permitted by MailboxSize;
+// see NewPriorityMailbox. The actor's OverflowPolicy applies to each
+// priority separately.
+func PriorityMailbox(urgent int) SpawnOption {
+	return func(o *spawnOptions) {
+		o.priority = true
+		o.urgentSize = urgent
+	}
+}
+
+// priorityOf returns the Priority of a message.
+func priorityOf(msg interface{}) Priority {
+	if env, ok := msg.(*Envelope); ok {
+		msg = env.Message
+	}
+
+	if p, ok := msg.(Prioritised); ok {
+		return p.Priority()
+	}
+
+	return Normal
+}
+
+// lane returns the channel of the Mailbox a message should be sent on.
+func (m *Mailbox) lane(msg interface{}) chan interface{} {
+	if m.Urgent != nil && priorityOf(msg) >= High {
+		return m.Urgent
+	}
+
+	return m.Messages
+}
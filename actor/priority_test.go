@@ -0,0 +1,66 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type reconfigure struct{}
+
+func (reconfigure) Priority() Priority { return High }
+
+func Test_PriorityMailboxMustPreemptNormalMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	handled := make(chan interface{}, 16)
+	ref, _ := sys.Spawn("flooded", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		<-release
+		handled <- msg
+		Reply(ctx, "ok")
+		return nil
+	}), MailboxSize(8), PriorityMailbox(2))
+
+	for i := 0; i < 8; i++ {
+		ref.Tell(i)
+	}
+	<-time.After(time.Millisecond * 10)
+
+	ref.Tell(reconfigure{})
+	if ref.Pending() != 8 {
+		t.Error("expected the urgent message to be held alongside the backlog", ref.Pending())
+	}
+
+	replies := make(chan error, 1)
+	go func() {
+		_, err := Ask[string](context.Background(), ref, reconfigure{})
+		replies <- err
+	}()
+	<-time.After(time.Millisecond * 10)
+
+	close(release)
+	if err := <-replies; err != nil {
+		t.Error("expected the urgent Ask to be replied to", err)
+	}
+
+	if first, second := <-handled, <-handled; first != 0 {
+		t.Error("expected the message in progress to be handled first", first)
+	} else if _, ok := second.(reconfigure); !ok {
+		t.Error("expected the urgent message to preempt the backlog", second)
+	}
+
+	if third := <-handled; third != (reconfigure{}) {
+		t.Error("expected the urgent Ask to preempt the backlog", third)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,217 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSlowSubscriber is the reason a message is dead-lettered upon its
+// subscriber being disconnected for being too slow; see Disconnect.
+var ErrSlowSubscriber = errors.New("actor: slow subscriber disconnected")
+
+// SlowSubscriber determines what happens to a message published to a topic
+// whilst one of its subscribers' mailboxes is full.
+type SlowSubscriber int
+
+const (
+	// Buffer waits for space in the subscriber's mailbox, holding up the
+	// delivery of the topic's later messages; it's the default.
+	Buffer SlowSubscriber = iota
+	// Drop drops the message for the subscriber, as an OverflowPolicy of
+	// DropNewest would.
+	Drop
+	// Disconnect drops the message, and unsubscribes the subscriber.
+	Disconnect
+)
+
+// PubSub provides topic-based publish and subscribe between actors. Each
+// topic is an actor, spawned upon first being used and named after both the
+// PubSub and the topic, which fans out the messages published to it - so
+// the fan-out is supervised like any other actor, and messages are
+// delivered to each subscriber in the order they were published.
+type PubSub struct {
+	sys  *System
+	name string
+
+	mu     sync.Mutex
+	topics map[string]*topic
+}
+
+// topic is the state of a topic's actor.
+type topic struct {
+	ref *ActorRef
+
+	mu      sync.Mutex
+	subs    map[*Subscription]bool
+	spawned int
+}
+
+// Subscription is the subscription of an actor to a topic.
+type Subscription struct {
+	topic  *topic
+	ref    *ActorRef
+	policy SlowSubscriber
+	// owned is set should the actor have been spawned by SubscribeFunc, in
+	// which case it's stopped upon being unsubscribed.
+	owned bool
+}
+
+// NewPubSub returns a PubSub under the System, whose topics' actors are
+// named "<name>/<topic>".
+func NewPubSub(sys *System, name string) *PubSub {
+	return &PubSub{sys: sys, name: name, topics: map[string]*topic{}}
+}
+
+// Publish publishes a message to every subscriber of the topic, without
+// waiting for it to be delivered; messages published to a topic without
+// subscribers are discarded.
+func (ps *PubSub) Publish(name string, msg interface{}) error {
+	t, err := ps.topic(name)
+	if err != nil {
+		return err
+	}
+
+	return t.ref.Tell(msg)
+}
+
+// Subscribe subscribes an actor to the topic, which receives every message
+// published after it subscribes; should its mailbox be full then the
+// policy applies. The subscription ends upon the actor being stopped.
+func (ps *PubSub) Subscribe(name string, ref *ActorRef, policy SlowSubscriber) (*Subscription, error) {
+	t, err := ps.topic(name)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Subscription{topic: t, ref: ref, policy: policy}
+	t.mu.Lock()
+	t.subs[sub] = true
+	t.mu.Unlock()
+
+	return sub, nil
+}
+
+// SubscribeFunc subscribes a function to the topic, as Subscribe does; the
+// function is run as a child actor of the topic, spawned with the given
+// options, so it's restarted should it fail.
+func (ps *PubSub) SubscribeFunc(name string, fn func(ctx context.Context, msg interface{}) error, policy SlowSubscriber, opts ...SpawnOption) (*Subscription, error) {
+	t, err := ps.topic(name)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ref, err := ps.sys.spawn(t.ref, fmt.Sprintf("%s/%d", t.ref.name, t.spawned), ActorFunc(fn), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	t.spawned++
+	sub := &Subscription{topic: t, ref: ref, policy: policy, owned: true}
+	t.subs[sub] = true
+	return sub, nil
+}
+
+// Subscribers returns the number of live subscribers to the topic.
+func (ps *PubSub) Subscribers(name string) int {
+	ps.mu.Lock()
+	t, ok := ps.topics[name]
+	ps.mu.Unlock()
+
+	if !ok {
+		return 0
+	}
+
+	return len(t.subscribers())
+}
+
+// Ref returns the subscribed actor.
+func (s *Subscription) Ref() *ActorRef {
+	return s.ref
+}
+
+// Unsubscribe ends the subscription, stopping the actor should it have been
+// spawned by SubscribeFunc; it returns false should the subscription have
+// already ended.
+func (s *Subscription) Unsubscribe() bool {
+	s.topic.mu.Lock()
+	ok := s.topic.subs[s]
+	delete(s.topic.subs, s)
+	s.topic.mu.Unlock()
+
+	if ok && s.owned {
+		s.ref.system.Stop(s.ref.name)
+	}
+
+	return ok
+}
+
+// topic returns the named topic, spawning its actor should it not exist.
+func (ps *PubSub) topic(name string) (*topic, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if t, ok := ps.topics[name]; ok && t.ref.Alive() {
+		return t, nil
+	}
+
+	t := &topic{subs: map[*Subscription]bool{}}
+	ref, err := ps.sys.Spawn(fmt.Sprintf("%s/%s", ps.name, name), ActorFunc(t.publish))
+	if err != nil {
+		return nil, err
+	}
+
+	t.ref = ref
+	ps.topics[name] = t
+	return t, nil
+}
+
+// subscribers returns the live subscriptions to the topic, forgetting any
+// whose actor has stopped.
+func (t *topic) subscribers() []*Subscription {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	subs := make([]*Subscription, 0, len(t.subs))
+	for sub := range t.subs {
+		if !sub.ref.Alive() {
+			delete(t.subs, sub)
+			continue
+		}
+
+		subs = append(subs, sub)
+	}
+
+	return subs
+}
+
+// publish fans a message out to the topic's subscribers.
+func (t *topic) publish(ctx context.Context, msg interface{}) error {
+	for _, sub := range t.subscribers() {
+		if sub.policy == Buffer {
+			// A subscriber which stops in the meantime routes the message
+			// to the dead letters itself.
+			sub.ref.tell(ctx, msg)
+			continue
+		}
+
+		lane := sub.ref.mailbox.lane(msg)
+		if delivered, _ := sub.ref.offer(lane, queued{msg: msg, at: sub.ref.system.clock.Now()}); delivered {
+			continue
+		}
+
+		if sub.policy == Drop {
+			sub.ref.drop(msg)
+			continue
+		}
+
+		sub.Unsubscribe()
+		sub.ref.deadLetter(msg, fmt.Errorf("%w: %q", ErrSlowSubscriber, sub.ref.name))
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_PubSubMustFanOutToSubscribers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps := NewPubSub(sys, "pubsub")
+	received := make(chan interface{}, 10)
+
+	ref, _ := sys.Spawn("listener", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		received <- msg
+		return nil
+	}))
+	if _, err := ps.Subscribe("config", ref, Buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := ps.SubscribeFunc("config", func(ctx context.Context, msg interface{}) error {
+		received <- msg
+		return nil
+	}, Buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps.Publish("config", "reload")
+	ps.Publish("other", "ignored")
+	<-time.After(time.Millisecond * 50)
+	if len(received) != 2 {
+		t.Error("expected the message to reach every subscriber", len(received))
+	}
+
+	if !sub.Unsubscribe() || sub.Ref().Alive() || ps.Subscribers("config") != 1 {
+		t.Error("expected the function subscriber to be stopped upon unsubscribing")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PubSubMustApplySlowSubscriberPolicy(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps := NewPubSub(sys, "pubsub")
+	block := make(chan struct{})
+	slow := func(ctx context.Context, msg interface{}) error {
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+
+	dropping, _ := ps.SubscribeFunc("ticks", slow, Drop, MailboxSize(1))
+	ps.SubscribeFunc("ticks", slow, Disconnect, MailboxSize(1))
+
+	for i := 0; i < 4; i++ {
+		ps.Publish("ticks", i)
+		<-time.After(time.Millisecond * 10)
+	}
+
+	if dropping.Ref().Dropped() == 0 {
+		t.Error("expected messages to be dropped for a slow subscriber")
+	}
+
+	if ps.Subscribers("ticks") != 1 {
+		t.Error("expected the slow subscriber to be disconnected", ps.Subscribers("ticks"))
+	}
+
+	close(block)
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,205 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrStopped is returned when sending to an actor which has stopped, or
+// whose System is stopping.
+var ErrStopped = errors.New("actor: stopped")
+
+// ActorRef is a handle to an actor spawned by a System, through which
+// messages are sent to it.
+type ActorRef struct {
+	// dropped and timeouts are accessed atomically, so are first to ensure
+	// their alignment.
+	dropped  uint64
+	timeouts uint64
+
+	name    string
+	mailbox *Mailbox
+	system  *System
+
+	stopOnce sync.Once
+	stopped  chan struct{}
+	exits    chan linkExit
+	overflow Overflow
+
+	parent    *ActorRef
+	escalates bool
+	spec      supervisor.WorkerSpec
+	enqueue   Enqueue
+	store     MailboxStore
+
+	mu         sync.Mutex
+	monitors   map[*ActorRef]bool
+	links      map[*ActorRef]bool
+	children   map[*ActorRef]bool
+	timers     map[*Timer]bool
+	wave       uint64
+	terminated bool
+	passive    bool
+}
+
+func newActorRef(name string, mailbox *Mailbox, sys *System) *ActorRef {
+	return &ActorRef{
+		name:     name,
+		mailbox:  mailbox,
+		system:   sys,
+		stopped:  make(chan struct{}),
+		exits:    make(chan linkExit, 1),
+		monitors: map[*ActorRef]bool{},
+		links:    map[*ActorRef]bool{},
+		children: map[*ActorRef]bool{},
+		timers:   map[*Timer]bool{},
+	}
+}
+
+// Name returns the name of the actor.
+func (ref *ActorRef) Name() string {
+	return ref.name
+}
+
+// Tell delivers a message to the actor's mailbox, blocking whilst the
+// mailbox is full - unless the actor was spawned with an OverflowPolicy
+// other than Block; it returns ErrStopped should the actor stop first.
+func (ref *ActorRef) Tell(msg interface{}) error {
+	return ref.tell(context.Background(), msg)
+}
+
+// tell delivers a message as Tell does, but gives up should the context be
+// cancelled whilst waiting for space in the mailbox.
+func (ref *ActorRef) tell(ctx context.Context, msg interface{}) error {
+	if ref.enqueue != nil {
+		return ref.enqueue(ctx, ref, msg)
+	}
+
+	return ref.deliver(ctx, msg)
+}
+
+// deliver delivers a message to the actor's mailbox, once it has passed
+// through any EnqueueInterceptors.
+func (ref *ActorRef) deliver(ctx context.Context, msg interface{}) error {
+	if !ref.Alive() {
+		err := ref.errStopped()
+		ref.deadLetter(msg, err)
+		return err
+	}
+
+	lane := ref.mailbox.lane(msg)
+	msg, err := ref.persist(msg)
+	if err != nil {
+		return err
+	}
+
+	msg = queued{msg: msg, at: ref.system.clock.Now()}
+	if delivered, err := ref.offer(lane, msg); delivered {
+		return err
+	}
+
+	cancelled, stopping := ref.system.done()
+	select {
+	case lane <- msg:
+		ref.enqueued()
+		return nil
+	case <-ctx.Done():
+		acknowledge(msg)
+		return ctx.Err()
+	case <-ref.stopped:
+	case <-cancelled:
+	case <-stopping:
+	}
+
+	err = ref.errStopped()
+	ref.deadLetter(msg, err)
+	return err
+}
+
+// TellControl delivers a ControlMessage to the actor, which acts upon it
+// ahead of any messages waiting in its mailbox.
+func (ref *ActorRef) TellControl(msg ControlMessage) error {
+	if !ref.Alive() {
+		return ref.errStopped()
+	}
+
+	cancelled, stopping := ref.system.done()
+	select {
+	case ref.mailbox.Control <- msg:
+		return nil
+	case <-ref.stopped:
+	case <-cancelled:
+	case <-stopping:
+	}
+
+	return ref.errStopped()
+}
+
+// Alive returns whether the actor may still receive messages; that is, it
+// hasn't been stopped, nor has its System.
+func (ref *ActorRef) Alive() bool {
+	cancelled, stopping := ref.system.done()
+	select {
+	case <-ref.stopped:
+	case <-cancelled:
+	case <-stopping:
+	default:
+		return true
+	}
+
+	return false
+}
+
+// Done returns a channel which is closed once the actor has been stopped,
+// either directly or by the System being shut down.
+func (ref *ActorRef) Done() <-chan struct{} {
+	return ref.stopped
+}
+
+// Info returns the supervisor's statistics for the actor, such as whether
+// it's running and how many times it has been restarted; it returns false
+// should the actor have been stopped, or be passivated.
+func (ref *ActorRef) Info() (supervisor.WorkerInfo, bool) {
+	infos := ref.system.root.WorkerInfo(ref.name)
+	if len(infos) == 0 {
+		return supervisor.WorkerInfo{}, false
+	}
+
+	return infos[0], true
+}
+
+// Pending returns the number of messages waiting in the actor's mailbox.
+func (ref *ActorRef) Pending() int {
+	depth, _ := ref.mailbox.depth()
+	return depth
+}
+
+// MailboxStats returns the statistics for the actor's mailbox.
+func (ref *ActorRef) MailboxStats() MailboxStats {
+	return ref.mailbox.Stats()
+}
+
+// enqueued records a message having been enqueued in the actor's mailbox,
+// reactivating the actor should it be passivated.
+func (ref *ActorRef) enqueued() {
+	ref.mailbox.recordEnqueue(ref.system.clock.Now())
+	if ref.system.metrics != nil {
+		depth, _ := ref.mailbox.depth()
+		ref.system.metrics.MessageEnqueued(ref.name, depth)
+	}
+
+	ref.wake()
+}
+
+func (ref *ActorRef) markStopped() {
+	ref.stopOnce.Do(func() { close(ref.stopped) })
+	ref.cancelTimers()
+}
+
+func (ref *ActorRef) errStopped() error {
+	return fmt.Errorf("%w: %q", ErrStopped, ref.name)
+}
@@ -0,0 +1,63 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_ActorRefMustReportLifecycle(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan interface{}, 10)
+	ref, err := sys.Spawn("worker", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		handled <- msg
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref.TellControl(Restart)
+	ref.Tell("hello")
+	if msg := <-handled; msg != "hello" {
+		t.Error("unexpected message", msg)
+	}
+
+	if info, ok := ref.Info(); !ok || !ref.Alive() || info.Restarts != 1 {
+		t.Error("expected the actor to be alive after restarting", info)
+	}
+
+	sys.Stop("worker")
+	select {
+	case <-ref.Done():
+	default:
+		t.Error("expected the actor to be done once stopped")
+	}
+
+	if err := ref.Tell("ignored"); !errors.Is(err, ErrStopped) || ref.Alive() {
+		t.Error("expected a stopped actor to reject messages", err)
+	}
+
+	if _, ok := ref.Info(); ok {
+		t.Error("expected no statistics for a stopped actor")
+	}
+
+	other, _ := sys.Spawn("other", ActorFunc(func(ctx context.Context, msg interface{}) error { return nil }))
+	if err := sys.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := other.TellControl(Restart); !errors.Is(err, ErrStopped) {
+		t.Error("expected actors to stop with their system", err)
+	}
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,235 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrNameTaken is returned when registering a name which already refers to
+// another actor.
+var ErrNameTaken = errors.New("actor: name taken")
+
+// registryShards is the number of shards the System's registry is divided
+// into; each name belongs to a single shard, so spawning, stopping and
+// looking up actors by different names rarely contend on the same lock.
+const registryShards = 64
+
+// registry holds the System's actors, and the names registered to them,
+// sharded by name.
+type registry struct {
+	shards [registryShards]registryShard
+}
+
+// registryShard holds the actors, and registered names, belonging to a
+// single shard. Aliases are kept in the shard of the actor they refer to,
+// allowing them to be removed once the actor stops without visiting every
+// shard.
+type registryShard struct {
+	mu      sync.RWMutex
+	actors  map[string]*ActorRef
+	names   map[string]*ActorRef
+	aliases map[string][]string
+}
+
+func newRegistry() *registry {
+	r := &registry{}
+	for i := range r.shards {
+		r.shards[i] = registryShard{
+			actors:  map[string]*ActorRef{},
+			names:   map[string]*ActorRef{},
+			aliases: map[string][]string{},
+		}
+	}
+
+	return r
+}
+
+// shardIndex hashes the name with FNV-1a, without allocating.
+func shardIndex(name string) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(name); i++ {
+		h ^= uint32(name[i])
+		h *= 16777619
+	}
+
+	return int(h % registryShards)
+}
+
+func (r *registry) shard(name string) *registryShard {
+	return &r.shards[shardIndex(name)]
+}
+
+// lockPair locks the shards of both names in index order, returning a
+// function to unlock them.
+func (r *registry) lockPair(a, b string) func() {
+	i, j := shardIndex(a), shardIndex(b)
+	if i == j {
+		r.shards[i].mu.Lock()
+		return r.shards[i].mu.Unlock
+	}
+
+	if i > j {
+		i, j = j, i
+	}
+
+	r.shards[i].mu.Lock()
+	r.shards[j].mu.Lock()
+	return func() {
+		r.shards[j].mu.Unlock()
+		r.shards[i].mu.Unlock()
+	}
+}
+
+// each calls fn with every shard, holding its read lock.
+func (r *registry) each(fn func(*registryShard)) {
+	for i := range r.shards {
+		shard := &r.shards[i]
+		shard.mu.RLock()
+		fn(shard)
+		shard.mu.RUnlock()
+	}
+}
+
+func (s *registryShard) whereisLocked(name string) (*ActorRef, bool) {
+	if ref, ok := s.actors[name]; ok {
+		return ref, true
+	}
+
+	ref, ok := s.names[name]
+	return ref, ok
+}
+
+// dropAliasLocked forgets that the name was registered to the actor.
+func (s *registryShard) dropAliasLocked(actor, name string) {
+	aliases := s.aliases[actor]
+	for i, alias := range aliases {
+		if alias == name {
+			aliases = append(aliases[:i], aliases[i+1:]...)
+			break
+		}
+	}
+
+	if len(aliases) == 0 {
+		delete(s.aliases, actor)
+		return
+	}
+
+	s.aliases[actor] = aliases
+}
+
+// Register registers an additional name for an actor, by which it can be
+// found via Whereis. As an ActorRef remains valid across restarts of its
+// actor, so does the registration; it's removed once the actor is stopped,
+// or upon Unregister. Registering a name which already refers to the same
+// actor has no effect.
+//
+// This decouples senders from the construction of the actors they send to:
+// a sender can look up "billing" without knowing which actor currently
+// provides it, and the name can be re-registered to a replacement.
+func (sys *System) Register(name string, ref *ActorRef) error {
+	if ref.system != sys {
+		return ref.errStopped()
+	}
+
+	unlock := sys.registry.lockPair(ref.name, name)
+	defer unlock()
+
+	owner := sys.registry.shard(ref.name)
+	if owner.actors[ref.name] != ref {
+		return ref.errStopped()
+	}
+
+	shard := sys.registry.shard(name)
+	if existing, ok := shard.whereisLocked(name); ok {
+		if existing != ref {
+			return fmt.Errorf("%w: %q", ErrNameTaken, name)
+		}
+
+		return nil
+	}
+
+	shard.names[name] = ref
+	owner.aliases[ref.name] = append(owner.aliases[ref.name], name)
+	return nil
+}
+
+// Unregister removes a name registered via Register; an actor's own name
+// can't be unregistered. It returns whether the name was registered.
+func (sys *System) Unregister(name string) bool {
+	shard := sys.registry.shard(name)
+	shard.mu.RLock()
+	ref, ok := shard.names[name]
+	shard.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	unlock := sys.registry.lockPair(ref.name, name)
+	defer unlock()
+
+	// The name may have been unregistered, or registered to another actor,
+	// whilst the locks were acquired.
+	if shard.names[name] != ref {
+		return false
+	}
+
+	delete(shard.names, name)
+	sys.registry.shard(ref.name).dropAliasLocked(ref.name, name)
+	return true
+}
+
+// Whereis returns the actor known by name, which may be either the name it
+// was spawned with or one registered to it.
+func (sys *System) Whereis(name string) (*ActorRef, bool) {
+	shard := sys.registry.shard(name)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	return shard.whereisLocked(name)
+}
+
+// Registered returns every name registered via Register, sorted
+// alphabetically.
+func (sys *System) Registered() []string {
+	names := []string{}
+	sys.registry.each(func(shard *registryShard) {
+		for name := range shard.names {
+			names = append(names, name)
+		}
+	})
+
+	sort.Strings(names)
+	return names
+}
+
+// unregister removes the actor, along with every name registered to it,
+// returning false should it have already been removed.
+func (sys *System) unregister(ref *ActorRef) bool {
+	owner := sys.registry.shard(ref.name)
+	owner.mu.Lock()
+	if owner.actors[ref.name] != ref {
+		owner.mu.Unlock()
+		return false
+	}
+
+	delete(owner.actors, ref.name)
+	aliases := owner.aliases[ref.name]
+	delete(owner.aliases, ref.name)
+	owner.mu.Unlock()
+
+	// As the actor is no longer in its shard, Register can't add aliases to
+	// it in the meantime.
+	for _, name := range aliases {
+		shard := sys.registry.shard(name)
+		shard.mu.Lock()
+		if shard.names[name] == ref {
+			delete(shard.names, name)
+		}
+		shard.mu.Unlock()
+	}
+
+	return true
+}
@@ -0,0 +1,126 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_RegistryMustResolveNamesAcrossRestarts(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	echo := ActorFunc(func(ctx context.Context, msg interface{}) error {
+		Reply(ctx, msg)
+		return nil
+	})
+
+	v1, _ := sys.Spawn("billing-v1", echo)
+	v2, _ := sys.Spawn("billing-v2", echo)
+	if err := sys.Register("billing", v1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sys.Register("billing", v2); !errors.Is(err, ErrNameTaken) {
+		t.Error("expected a registered name to be taken", err)
+	}
+
+	if _, err := sys.Spawn("billing", echo); !errors.Is(err, ErrNameTaken) {
+		t.Error("expected a registered name to be unavailable to spawn", err)
+	}
+
+	v1.TellControl(Restart)
+	ref, ok := sys.Whereis("billing")
+	if !ok || ref != v1 {
+		t.Fatal("expected the name to resolve to the registered actor")
+	}
+
+	if reply, err := Ask[string](context.Background(), ref, "ping"); reply != "ping" || err != nil {
+		t.Error("expected the registration to survive a restart", reply, err)
+	}
+
+	if !sys.Stop("billing") {
+		t.Error("expected the actor to be stopped by its registered name")
+	}
+
+	if _, ok := sys.Whereis("billing"); ok || len(sys.Registered()) != 0 {
+		t.Error("expected the registration to be removed with the actor")
+	}
+
+	if err := sys.Register("billing", v2); err != nil {
+		t.Error("expected the name to be re-registered", err)
+	}
+
+	if err := sys.Register("other", v1); !errors.Is(err, ErrStopped) {
+		t.Error("expected a stopped actor to not be registered", err)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_RegistryMustSpawnLookupAndStopConcurrently(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	echo := ActorFunc(func(ctx context.Context, msg interface{}) error {
+		Reply(ctx, msg)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name, alias := fmt.Sprintf("actor-%d", i), fmt.Sprintf("alias-%d", i)
+			ref, err := sys.Spawn(name, echo)
+			if err != nil {
+				t.Error("expected the actor to be spawned", err)
+				return
+			}
+
+			if err := sys.Register(alias, ref); err != nil {
+				t.Error("expected the alias to be registered", err)
+			}
+
+			if found, ok := sys.Whereis(alias); !ok || found != ref {
+				t.Error("expected the alias to resolve to the actor", alias)
+			}
+
+			if i%2 == 0 && !sys.Stop(alias) {
+				t.Error("expected the actor to be stopped by its alias", alias)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	if actors, registered := len(sys.Actors()), len(sys.Registered()); actors != 100 || registered != 100 {
+		t.Error("expected only the actors which weren't stopped to remain", actors, registered)
+	}
+
+	if sys.Unregister("alias-1") && sys.Unregister("alias-1") {
+		t.Error("expected an alias to only be unregistered once")
+	}
+
+	if _, ok := sys.Whereis("alias-1"); ok {
+		t.Error("expected the unregistered alias to be forgotten")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
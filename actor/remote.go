@@ -0,0 +1,177 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownActor is returned when delivering a RemoteMessage to an actor
+// which doesn't exist within the System.
+var ErrUnknownActor = errors.New("actor: unknown actor")
+
+// RemoteMessage is the form in which a message is sent to an actor in
+// another process; Transports are responsible for its encoding.
+type RemoteMessage struct {
+	// Actor is the name of the receiving actor, as known to Whereis.
+	Actor string
+	// Message is the message itself.
+	Message interface{}
+	// ID, CorrelationID, CausationID and Headers are those of the Envelope
+	// the message was sent within.
+	ID            string
+	CorrelationID string
+	CausationID   string
+	Headers       map[string]string
+	// Reply denotes whether the sender awaits a reply.
+	Reply bool
+}
+
+// RemoteReply is the form in which the reply to a RemoteMessage is returned
+// to its sender.
+type RemoteReply struct {
+	// Value is the value replied with.
+	Value interface{}
+	// Error is the text of the error replied with, if any.
+	Error string
+	// Kind is the text of the error of this package which the error
+	// replied with wraps, if any, allowing it to be matched via errors.Is
+	// by the sender.
+	Kind string
+}
+
+// Transport carries RemoteMessages to the Systems of other processes, each
+// of which is identified by an address whose form is particular to the
+// Transport; it's the extension point for remote messaging. The receiving
+// end of a Transport passes each RemoteMessage to System.Deliver.
+type Transport interface {
+	// Send delivers a RemoteMessage to the System at the address, waiting
+	// for its reply should the message expect one.
+	Send(ctx context.Context, addr string, msg RemoteMessage) (RemoteReply, error)
+}
+
+// remoteKinds are the errors of this package which survive being replied
+// to a remote sender.
+var remoteKinds = []error{
+	ErrStopped, ErrUnknownActor, ErrNoReply, ErrActorFailed, ErrMailboxFull,
+	ErrDeadlineExceeded, ErrRetriesExhausted, ErrPoisoned, ErrNoRoutees,
+	ErrUnexpectedMessage, ErrTimeout,
+}
+
+// remoteError is an error replied by a remote actor.
+type remoteError struct {
+	text string
+	kind error
+}
+
+func (e remoteError) Error() string { return e.text }
+func (e remoteError) Unwrap() error { return e.kind }
+
+// Deliver delivers a RemoteMessage, received by a Transport, to the named
+// actor within its Envelope; should the message expect a reply then it
+// waits for it, subject to the context.
+func (sys *System) Deliver(ctx context.Context, msg RemoteMessage) (RemoteReply, error) {
+	ref, ok := sys.Whereis(msg.Actor)
+	if !ok {
+		return RemoteReply{}, fmt.Errorf("%w: %q", ErrUnknownActor, msg.Actor)
+	}
+
+	env := &Envelope{
+		Message:       msg.Message,
+		ID:            msg.ID,
+		CorrelationID: msg.CorrelationID,
+		CausationID:   msg.CausationID,
+		Headers:       msg.Headers,
+		Created:       time.Now(),
+	}
+
+	if !msg.Reply {
+		return RemoteReply{}, ref.tell(ctx, env)
+	}
+
+	resp, err := ref.ask(ctx, env)
+	if err != nil {
+		return NewRemoteReply(nil, err), nil
+	}
+
+	return NewRemoteReply(resp.Value, nil), nil
+}
+
+// NewRemoteReply returns the RemoteReply carrying the value, or error.
+func NewRemoteReply(value interface{}, err error) RemoteReply {
+	if err == nil {
+		return RemoteReply{Value: value}
+	}
+
+	reply := RemoteReply{Error: err.Error()}
+	for _, kind := range remoteKinds {
+		if errors.Is(err, kind) {
+			reply.Kind = kind.Error()
+			break
+		}
+	}
+
+	return reply
+}
+
+// Err returns the error replied with, if any; should it have wrapped an
+// error of this package then so does the returned error.
+func (r RemoteReply) Err() error {
+	if r.Error == "" {
+		return nil
+	}
+
+	err := remoteError{text: r.Error}
+	for _, kind := range remoteKinds {
+		if kind.Error() == r.Kind {
+			err.kind = kind
+		}
+	}
+
+	return err
+}
+
+// RemoteRef returns an ActorRef for the named actor of the System at the
+// address, through which messages are sent via the Transport. The ActorRef
+// belongs to a local proxy actor, named "<name>@<addr>" and spawned with
+// the given options, which forwards each message it receives - relaying the
+// reply to those sent by Ask or Request - so remote actors can be used
+// wherever a local one can.
+//
+// The proxy sends one message at a time; a Pool of proxies allows several
+// to be in flight at once. Messages which can't be sent are routed to the
+// dead letters, or replied to with the Transport's error.
+func (sys *System) RemoteRef(t Transport, addr, name string, opts ...SpawnOption) (*ActorRef, error) {
+	return sys.Spawn(fmt.Sprintf("%s@%s", name, addr), ActorFunc(func(ctx context.Context, msg interface{}) error {
+		// The message is sent within a new Envelope, whose metadata - and
+		// any trace context - follows from that it was received within.
+		env := NewEnvelope(ctx, msg)
+		remote := RemoteMessage{
+			Actor:         name,
+			Message:       msg,
+			ID:            env.ID,
+			CorrelationID: env.CorrelationID,
+			CausationID:   env.CausationID,
+			Headers:       env.Headers,
+		}
+
+		received, replying := CurrentEnvelope(ctx)
+		remote.Reply = replying && (received.ReplyTo != nil || received.ReplyToRef != nil)
+
+		reply, err := t.Send(ctx, addr, remote)
+		switch {
+		case err != nil && replying:
+			ReplyError(ctx, err)
+		case err != nil:
+			self, _ := Self(ctx)
+			self.deadLetter(msg, err)
+		case remote.Reply && reply.Err() != nil:
+			ReplyError(ctx, reply.Err())
+		case remote.Reply:
+			Reply(ctx, reply.Value)
+		}
+
+		return nil
+	}), opts...)
+}
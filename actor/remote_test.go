@@ -0,0 +1,72 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// loopback is a Transport delivering to Systems within the same process.
+type loopback map[string]*System
+
+func (l loopback) Send(ctx context.Context, addr string, msg RemoteMessage) (RemoteReply, error) {
+	sys, ok := l[addr]
+	if !ok {
+		return RemoteReply{}, errors.New("unreachable")
+	}
+
+	return sys.Deliver(ctx, msg)
+}
+
+func Test_RemoteRefMustRelayMessagesAndReplies(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	local, _ := NewSystem(context.Background())
+	remote, _ := NewSystem(context.Background())
+	transport := loopback{"remote:1": remote}
+
+	received := make(chan interface{}, 10)
+	remote.Spawn("billing", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		received <- msg
+		if msg == "fail" {
+			ReplyError(ctx, ErrMailboxFull)
+			return nil
+		}
+		Reply(ctx, 42)
+		return nil
+	}))
+
+	ref, err := local.RemoteRef(transport, "remote:1", "billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ref.Name() != "billing@remote:1" {
+		t.Error("expected the proxy to be named after the remote actor", ref.Name())
+	}
+
+	ref.Tell("invoice")
+	if total, err := Ask[int](context.Background(), ref, "total"); total != 42 || err != nil {
+		t.Error("expected the remote actor's reply to be relayed", total, err)
+	}
+
+	if _, err := Ask[int](context.Background(), ref, "fail"); !errors.Is(err, ErrMailboxFull) {
+		t.Error("expected the remote error to match its sentinel", err)
+	}
+
+	if len(received) != 3 {
+		t.Error("expected every message to reach the remote actor", len(received))
+	}
+
+	missing, _ := local.RemoteRef(transport, "remote:1", "missing")
+	if _, err := Ask[int](context.Background(), missing, "total"); !errors.Is(err, ErrUnknownActor) {
+		t.Error("expected ErrUnknownActor for a missing remote actor", err)
+	}
+
+	local.Shutdown(context.Background())
+	remote.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,76 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrRetriesExhausted is the reason a message is routed to the dead letters
+// upon failing every attempt permitted by the actor's RetryMessages policy.
+var ErrRetriesExhausted = errors.New("actor: retries exhausted")
+
+// RetryMessages gives the actor at-least-once processing of its messages:
+// should Handle return an error, or panic, then the message is re-enqueued
+// after the policy's Backoff, until the policy's Attempts are exhausted -
+// at which point it's routed to the dead letters with a reason wrapping
+// ErrRetriesExhausted. A message sent by Ask is replied to once it's either
+// handled or dead-lettered.
+//
+// The actor is still restarted upon each failure, so the Supervisor's
+// RestartPolicy must permit at least as many restarts. Messages are
+// re-enqueued behind those already waiting in the mailbox; RetryMessages
+// takes precedence over PoisonThreshold.
+func RetryMessages(policy supervisor.RetryPolicy) SpawnOption {
+	return func(o *spawnOptions) {
+		o.retries = &policy
+	}
+}
+
+// retried wraps a message which has been re-enqueued, carrying the number
+// of attempts which have failed.
+type retried struct {
+	msg     interface{}
+	attempt int
+}
+
+func (m retried) Priority() Priority { return priorityOf(m.msg) }
+
+// unretried unwraps a message received from the mailbox, returning the
+// number of attempts which have failed.
+func unretried(msg interface{}) (interface{}, int) {
+	if m, ok := msg.(retried); ok {
+		return m.msg, m.attempt
+	}
+
+	return msg, 0
+}
+
+// redelivers returns whether a message which causes the actor to fail will
+// be redelivered, rather than lost.
+func (r *runtime) redelivers(panicked bool) bool {
+	return r.retries != nil || (panicked && r.poison >= 1) || (r.acking && !r.acked)
+}
+
+// retry re-enqueues a failed message after the backoff, or routes it to the
+// dead letters once its attempts are exhausted; it returns false should the
+// actor not have a RetryMessages policy.
+func (r *runtime) retry(msg interface{}, attempt int, reason error) bool {
+	if r.retries == nil || r.ref == nil {
+		return false
+	}
+
+	if attempt+1 >= r.retries.Attempts {
+		r.ref.deadLetter(msg, fmt.Errorf("%w: %q after %d attempts: %v", ErrRetriesExhausted, r.ref.name, attempt+1, reason))
+		return true
+	}
+
+	// Unlike a Timer, the retry isn't cancelled should the actor be stopped;
+	// the message is instead routed to the dead letters.
+	ref, next := r.ref, retried{msg: msg, attempt: attempt + 1}
+	supervisor.AfterFunc(r.clock, r.retries.Backoff.Duration(attempt), func() {
+		ref.Tell(next)
+	})
+	return true
+}
@@ -0,0 +1,72 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func Test_RetryMessagesMustRedeliverWithBackoff(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	letters := make(chan DeadLetter, 4)
+	sys.OnDeadLetter(func(letter DeadLetter) {
+		letters <- letter
+	})
+
+	attempts := map[interface{}]int{}
+	handled := make(chan interface{}, 4)
+	ref, _ := sys.Spawn("sink", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		attempts[msg]++
+		switch {
+		case msg == "transient" && attempts[msg] < 3:
+			return errors.New("unavailable")
+		case msg == "permanent":
+			panic("permanent")
+		}
+
+		handled <- msg
+		Reply(ctx, msg)
+		return nil
+	}), RetryMessages(supervisor.RetryPolicy{
+		Attempts: 3,
+		Backoff:  supervisor.Backoff{Initial: time.Millisecond * 10, Multiplier: 2},
+	}))
+
+	ref.Tell("transient")
+	ref.Tell("other")
+
+	if msg := <-handled; msg != "other" {
+		t.Error("expected other messages to be handled whilst awaiting the retry", msg)
+	}
+
+	select {
+	case msg := <-handled:
+		if msg != "transient" {
+			t.Error("expected the message to succeed upon being retried", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the message to be retried")
+	}
+
+	if _, err := Ask[string](context.Background(), ref, "permanent"); !errors.Is(err, ErrRetriesExhausted) {
+		t.Error("expected the Ask to be replied to once retries are exhausted", err)
+	}
+
+	letter := <-letters
+	if letter.Message != "permanent" || !errors.Is(letter.Reason, ErrRetriesExhausted) {
+		t.Error("expected the message to be dead-lettered", letter)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,155 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoRoutees is the reason a message sent to a Pool, or BroadcastGroup,
+// is dead-lettered should it have no live routees, or members.
+var ErrNoRoutees = errors.New("actor: router has no routees")
+
+// Pool distributes the messages sent to it amongst a pool of identical
+// actors, or routees, according to its RoutingStrategy; messages sent
+// within an Envelope are forwarded intact, so it's the routee which replies.
+// See Forward.
+//
+// Each routee is spawned as a child actor of the Pool, so is supervised
+// individually - a faulty routee is restarted without affecting the Pool,
+// or its siblings - and the pool may be resized at runtime.
+type Pool struct {
+	sys      *System
+	ref      *ActorRef
+	factory  func() Actor
+	strategy RoutingStrategy
+	opts     []SpawnOption
+
+	mu      sync.Mutex
+	routees []*ActorRef
+	spawned int
+}
+
+// NewPool spawns a Pool under the System, with the given name, along with n
+// routees created by the factory; the options are applied to each routee.
+func NewPool(sys *System, name string, n int, factory func() Actor, strategy RoutingStrategy, opts ...SpawnOption) (*Pool, error) {
+	p := &Pool{sys: sys, factory: factory, strategy: strategy, opts: opts}
+
+	ref, err := sys.Spawn(name, ActorFunc(p.route))
+	if err != nil {
+		return nil, err
+	}
+
+	p.ref = ref
+	if err := p.Resize(n); err != nil {
+		sys.Stop(ref.name)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// NewRouter spawns a Pool whose messages are distributed amongst its
+// routees in round-robin order; see RoundRobin.
+func NewRouter(sys *System, name string, n int, factory func() Actor, opts ...SpawnOption) (*Pool, error) {
+	return NewPool(sys, name, n, factory, RoundRobin(), opts...)
+}
+
+// NewHashRouter spawns a Pool whose messages are routed by the key
+// extracted from each; see ConsistentHash.
+func NewHashRouter(sys *System, name string, n int, factory func() Actor, key func(msg interface{}) string, opts ...SpawnOption) (*Pool, error) {
+	return NewPool(sys, name, n, factory, ConsistentHash(key), opts...)
+}
+
+// Ref returns the ActorRef of the Pool, to which messages are sent;
+// stopping it stops every routee.
+func (p *Pool) Ref() *ActorRef {
+	return p.ref
+}
+
+// Routees returns the live routees, in the order they were spawned.
+func (p *Pool) Routees() []*ActorRef {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pruneLocked()
+	return append([]*ActorRef(nil), p.routees...)
+}
+
+// Resize spawns, or stops, routees until the Pool has n of them; routees
+// are stopped newest first, and any messages waiting in their mailboxes are
+// routed to the System's dead letters.
+func (p *Pool) Resize(n int) error {
+	p.mu.Lock()
+	p.pruneLocked()
+
+	for len(p.routees) < n {
+		name := fmt.Sprintf("%s/%d", p.ref.name, p.spawned)
+		ref, err := p.sys.spawn(p.ref, name, p.factory(), p.opts...)
+		if err != nil {
+			p.mu.Unlock()
+			return err
+		}
+
+		p.spawned++
+		p.routees = append(p.routees, ref)
+	}
+
+	var stopped []*ActorRef
+	for len(p.routees) > n && len(p.routees) > 0 {
+		last := len(p.routees) - 1
+		stopped = append(stopped, p.routees[last])
+		p.routees = p.routees[:last]
+	}
+	p.mu.Unlock()
+
+	for _, ref := range stopped {
+		p.sys.Stop(ref.name)
+	}
+
+	return nil
+}
+
+// route forwards a message to the routee chosen by the RoutingStrategy.
+func (p *Pool) route(ctx context.Context, msg interface{}) error {
+	routee := p.pick(msg)
+	if routee == nil {
+		if env, ok := CurrentEnvelope(ctx); ok {
+			msg = env
+		}
+
+		p.ref.deadLetter(msg, fmt.Errorf("%w: %q", ErrNoRoutees, p.ref.name))
+		return nil
+	}
+
+	// A routee which stops in the meantime routes the message to the dead
+	// letters itself.
+	Forward(ctx, routee)
+	return nil
+}
+
+// pick returns the live routee to which the message is routed, if any.
+func (p *Pool) pick(msg interface{}) *ActorRef {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pruneLocked()
+	if len(p.routees) == 0 {
+		return nil
+	}
+
+	return p.strategy.Route(msg, p.routees)
+}
+
+// pruneLocked forgets any routees which have been stopped.
+func (p *Pool) pruneLocked() {
+	live := p.routees[:0]
+	for _, ref := range p.routees {
+		if ref.Alive() {
+			live = append(live, ref)
+		}
+	}
+
+	p.routees = live
+}
@@ -0,0 +1,164 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_RouterMustDistributeMessagesRoundRobin(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router, err := NewRouter(sys, "pool", 3, func() Actor {
+		return ActorFunc(func(ctx context.Context, msg interface{}) error {
+			self, _ := Self(ctx)
+			Reply(ctx, self.Name())
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := map[string]int{}
+	for i := 0; i < 6; i++ {
+		name, err := Ask[string](context.Background(), router.Ref(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handled[name]++
+	}
+
+	if len(handled) != 3 || handled["pool/0"] != 2 || handled["pool/2"] != 2 {
+		t.Error("expected messages to be distributed evenly amongst routees", handled)
+	}
+
+	router.Resize(1)
+	if routees := router.Routees(); len(routees) != 1 || len(router.Ref().Children()) != 1 {
+		t.Error("expected the router to be resized", len(routees))
+	}
+
+	router.Resize(0)
+	if _, err := Ask[string](context.Background(), router.Ref(), "lost"); !errors.Is(err, ErrNoRoutees) {
+		t.Error("expected ErrNoRoutees from an empty router", err)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_HashRouterMustRouteEachKeyToOneRoutee(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router, err := NewHashRouter(sys, "shards", 4, func() Actor {
+		return ActorFunc(func(ctx context.Context, msg interface{}) error {
+			self, _ := Self(ctx)
+			Reply(ctx, self.Name())
+			return nil
+		})
+	}, func(msg interface{}) string {
+		return msg.(string)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"alice", "bob", "carol", "dave", "erin", "frank"}
+	owners := map[string]string{}
+	for _, key := range keys {
+		for i := 0; i < 3; i++ {
+			owner, err := Ask[string](context.Background(), router.Ref(), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if prev, ok := owners[key]; ok && prev != owner {
+				t.Error("expected each key to be routed to the same routee", key, prev, owner)
+			}
+			owners[key] = owner
+		}
+	}
+
+	router.Resize(5)
+	for _, key := range keys {
+		owner, _ := Ask[string](context.Background(), router.Ref(), key)
+		if owner != owners[key] && owner != "shards/4" {
+			t.Error("expected only keys moving to the new routee to change owner", key, owner)
+		}
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PoolMustRouteByItsStrategy(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first routee never handles a message, so its mailbox fills.
+	block := make(chan struct{})
+	routees := 0
+	pool, err := NewPool(sys, "least", 2, func() Actor {
+		first := routees == 0
+		routees++
+		return ActorFunc(func(ctx context.Context, msg interface{}) error {
+			if first {
+				select {
+				case <-block:
+				case <-ctx.Done():
+				}
+			}
+			return nil
+		})
+	}, LeastMailbox())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 6; i++ {
+		pool.Ref().Tell(i)
+		<-time.After(time.Millisecond * 10)
+	}
+
+	if first := pool.Routees()[0]; first.Pending() > 1 {
+		t.Error("expected messages to avoid the busiest routee", first.Pending())
+	}
+
+	custom, err := NewPool(sys, "custom", 2, func() Actor {
+		return ActorFunc(func(ctx context.Context, msg interface{}) error {
+			self, _ := Self(ctx)
+			Reply(ctx, self.Name())
+			return nil
+		})
+	}, RoutingFunc(func(msg interface{}, routees []*ActorRef) *ActorRef {
+		return routees[len(routees)-1]
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name, _ := Ask[string](context.Background(), custom.Ref(), "hi"); name != "custom/1" {
+		t.Error("expected a custom strategy to choose the routee", name)
+	}
+
+	close(block)
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
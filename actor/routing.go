@@ -0,0 +1,93 @@
+package actor
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// RoutingStrategy chooses the routee of a Pool to which each message is
+// routed. Route is called with the message - unwrapped from its Envelope,
+// should it have one - and the Pool's live routees, of which there's at
+// least one, in the order they were spawned; it's never called concurrently
+// by the same Pool, so a RoutingStrategy may hold state without
+// synchronising access to it - but mustn't be shared between Pools. A
+// message is routed to the dead letters should Route return nil.
+type RoutingStrategy interface {
+	Route(msg interface{}, routees []*ActorRef) *ActorRef
+}
+
+// RoutingFunc adapts a function to the RoutingStrategy interface.
+type RoutingFunc func(msg interface{}, routees []*ActorRef) *ActorRef
+
+// Route calls f.
+func (f RoutingFunc) Route(msg interface{}, routees []*ActorRef) *ActorRef {
+	return f(msg, routees)
+}
+
+// RoundRobin routes messages to each routee in turn.
+func RoundRobin() RoutingStrategy {
+	next := 0
+	return RoutingFunc(func(msg interface{}, routees []*ActorRef) *ActorRef {
+		next %= len(routees)
+		routee := routees[next]
+		next++
+		return routee
+	})
+}
+
+// Random routes each message to a routee chosen at random.
+func Random() RoutingStrategy {
+	return RoutingFunc(func(msg interface{}, routees []*ActorRef) *ActorRef {
+		return routees[rand.Intn(len(routees))]
+	})
+}
+
+// LeastMailbox routes each message to the routee with the fewest messages
+// waiting in its mailbox, favouring the earliest spawned should several
+// have as few.
+func LeastMailbox() RoutingStrategy {
+	return RoutingFunc(func(msg interface{}, routees []*ActorRef) *ActorRef {
+		routee, fewest := routees[0], routees[0].Pending()
+		for _, ref := range routees[1:] {
+			if pending := ref.Pending(); pending < fewest {
+				routee, fewest = ref, pending
+			}
+		}
+
+		return routee
+	})
+}
+
+// ConsistentHash routes each message by hashing the key extracted from it;
+// messages with the same key are handled by the same routee, and so in the
+// order they were sent. Routees are chosen by rendezvous hashing, so
+// resizing the Pool only moves the keys of the routees which are added or
+// removed.
+func ConsistentHash(key func(msg interface{}) string) RoutingStrategy {
+	return RoutingFunc(func(msg interface{}, routees []*ActorRef) *ActorRef {
+		k := key(msg)
+
+		var (
+			routee *ActorRef
+			best   uint64
+		)
+
+		for _, ref := range routees {
+			if sum := rendezvous(ref.name, k); routee == nil || sum > best {
+				routee, best = ref, sum
+			}
+		}
+
+		return routee
+	})
+}
+
+// rendezvous returns the weight of the key for the named node, the node with
+// the greatest weight being the key's owner.
+func rendezvous(node, key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(node))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return h.Sum64()
+}
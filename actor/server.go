@@ -0,0 +1,90 @@
+package actor
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCallTimeout is how long Call waits for a reply, should its context
+// not have a deadline.
+const DefaultCallTimeout = 5 * time.Second
+
+// Server is a higher-level behaviour mirroring OTP's gen_server, in which
+// messages are divided into synchronous calls, asynchronous casts, and
+// out-of-band info messages; see ServerActor.
+type Server interface {
+	// Init prepares the Server's state as it starts, and again upon each
+	// restart; returning an error fails the Server.
+	Init(ctx context.Context) error
+	// HandleCall handles a request sent via Call, returning the reply.
+	// Returning an error both fails the Server and is returned to the
+	// caller; to reply with an error without failing, use ReplyError.
+	HandleCall(ctx context.Context, req interface{}) (interface{}, error)
+	// HandleCast handles a message sent via Cast.
+	HandleCast(ctx context.Context, msg interface{}) error
+	// HandleInfo handles any other message, such as those sent via Tell or
+	// System.SendAfter, and the Down messages of monitored actors.
+	HandleInfo(ctx context.Context, msg interface{}) error
+}
+
+// call and cast wrap the messages sent via Call and Cast, carrying the
+// Priority of the message they wrap.
+type (
+	call struct{ req interface{} }
+	cast struct{ msg interface{} }
+)
+
+func (c call) Priority() Priority { return priorityOf(c.req) }
+func (c cast) Priority() Priority { return priorityOf(c.msg) }
+
+// ServerActor adapts a Server to an Actor, which can be spawned by a System
+// and is restarted upon failing like any other actor.
+//
+//	ref, err := sys.Spawn("cache", actor.ServerActor(&Cache{}))
+//	value, err := actor.Call[string](ctx, ref, Get{Key: "k"})
+func ServerActor(srv Server) Actor {
+	return &serverActor{srv: srv}
+}
+
+type serverActor struct {
+	srv Server
+}
+
+func (s *serverActor) Init(ctx context.Context) error {
+	return s.srv.Init(ctx)
+}
+
+func (s *serverActor) Handle(ctx context.Context, msg interface{}) error {
+	switch m := msg.(type) {
+	case call:
+		reply, err := s.srv.HandleCall(ctx, m.req)
+		if err != nil {
+			return err
+		}
+
+		Reply(ctx, reply)
+		return nil
+	case cast:
+		return s.srv.HandleCast(ctx, m.msg)
+	default:
+		return s.srv.HandleInfo(ctx, msg)
+	}
+}
+
+// Call sends a request to a Server and waits for its reply, which must be of
+// type T; should the context not have a deadline then DefaultCallTimeout is
+// applied, after which ErrTimeout is returned.
+func Call[T any](ctx context.Context, ref *ActorRef, req interface{}) (T, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultCallTimeout)
+		defer cancel()
+	}
+
+	return Ask[T](ctx, ref, call{req: req})
+}
+
+// Cast sends a message to a Server without waiting for it to be handled.
+func Cast(ref *ActorRef, msg interface{}) error {
+	return ref.Tell(cast{msg: msg})
+}
@@ -0,0 +1,88 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type counter struct {
+	count int
+	inits int
+	infos chan interface{}
+}
+
+func (c *counter) Init(ctx context.Context) error {
+	c.count = 0
+	c.inits++
+	return nil
+}
+
+func (c *counter) HandleCall(ctx context.Context, req interface{}) (interface{}, error) {
+	switch req {
+	case "get":
+		return c.count, nil
+	case "inits":
+		return c.inits, nil
+	case "crash":
+		return nil, errors.New("crashed")
+	}
+
+	return nil, nil
+}
+
+func (c *counter) HandleCast(ctx context.Context, msg interface{}) error {
+	c.count += msg.(int)
+	return nil
+}
+
+func (c *counter) HandleInfo(ctx context.Context, msg interface{}) error {
+	c.infos <- msg
+	return nil
+}
+
+func Test_ServerMustDispatchCallsCastsAndInfo(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &counter{infos: make(chan interface{}, 1)}
+	ref, _ := sys.Spawn("counter", ServerActor(srv))
+
+	Cast(ref, 2)
+	Cast(ref, 3)
+	if count, err := Call[int](context.Background(), ref, "get"); count != 5 || err != nil {
+		t.Error("expected casts to be handled before the call", count, err)
+	}
+
+	sys.SendAfter(ref, "tick", time.Millisecond*10)
+	select {
+	case info := <-srv.infos:
+		if info != "tick" {
+			t.Error("expected the timer to be handled as info", info)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the timer to be delivered")
+	}
+
+	if _, err := Call[int](context.Background(), ref, "crash"); err == nil {
+		t.Error("expected the caller to receive the error")
+	}
+
+	if inits, _ := Call[int](context.Background(), ref, "inits"); inits != 2 {
+		t.Error("expected the server to be initialised upon restarting", inits)
+	}
+
+	if count, _ := Call[int](context.Background(), ref, "get"); count != 0 {
+		t.Error("expected the server's state to be reset", count)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
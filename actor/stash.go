@@ -0,0 +1,81 @@
+package actor
+
+import (
+	"context"
+	"time"
+)
+
+type currentKey struct{}
+
+// current is the message being handled by an actor, along with the
+// runtime handling it.
+type current struct {
+	runtime   *runtime
+	msg       interface{}
+	stashed   bool
+	forwarded bool
+	latency   time.Duration
+}
+
+// Stash sets aside the message being handled, given the context passed to
+// Handle, so that it can be handled later once the actor is ready for it;
+// see UnstashAll. A message sent by Ask isn't replied to until it's handled
+// without being stashed. It returns false should the context not belong to
+// an actor.
+//
+// Stashed messages survive the actor being restarted, upon which they're
+// unstashed - as the state they were waiting on has been lost.
+func Stash(ctx context.Context) bool {
+	cur, ok := ctx.Value(currentKey{}).(*current)
+	if !ok || cur.stashed {
+		return ok
+	}
+
+	cur.stashed = true
+	cur.runtime.holding = false
+	cur.runtime.stash = append(cur.runtime.stash, cur.msg)
+	return true
+}
+
+// UnstashAll returns every stashed message to the actor, given the context
+// passed to Handle; they're handled in the order they were stashed, once the
+// current message has been handled and ahead of any messages waiting in the
+// mailbox. It returns the number of messages unstashed.
+func UnstashAll(ctx context.Context) int {
+	cur, ok := ctx.Value(currentKey{}).(*current)
+	if !ok {
+		return 0
+	}
+
+	return cur.runtime.unstash()
+}
+
+// Stashed returns the number of messages stashed by the actor handling the
+// current message, given the context passed to Handle.
+func Stashed(ctx context.Context) int {
+	cur, ok := ctx.Value(currentKey{}).(*current)
+	if !ok {
+		return 0
+	}
+
+	return len(cur.runtime.stash)
+}
+
+// unstash moves the stashed messages ahead of any awaiting redelivery.
+func (r *runtime) unstash() int {
+	n := len(r.stash)
+	r.replay = append(r.stash, r.replay...)
+	r.stash = nil
+	return n
+}
+
+// next returns the next unstashed message, if any.
+func (r *runtime) next() (interface{}, bool) {
+	if len(r.replay) == 0 {
+		return nil, false
+	}
+
+	msg := r.replay[0]
+	r.replay = r.replay[1:]
+	return msg, true
+}
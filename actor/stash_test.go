@@ -0,0 +1,65 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_StashMustDeferMessagesUntilUnstashed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connected := false
+	handled := make(chan interface{}, 8)
+	ref, _ := sys.Spawn("conn", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		switch {
+		case msg == "connected":
+			connected = true
+			UnstashAll(ctx)
+		case !connected:
+			Stash(ctx)
+		default:
+			handled <- msg
+			Reply(ctx, msg)
+		}
+		return nil
+	}))
+
+	ref.Tell("first")
+	replies := make(chan error, 1)
+	go func() {
+		reply, err := Ask[string](context.Background(), ref, "second")
+		if reply != "second" {
+			t.Error("expected the stashed Ask to be replied to once handled", reply)
+		}
+		replies <- err
+	}()
+	<-time.After(time.Millisecond * 20)
+
+	if len(handled) != 0 {
+		t.Fatal("expected messages to be stashed whilst disconnected")
+	}
+
+	ref.Tell("connected")
+	ref.Tell("third")
+
+	if err := <-replies; err != nil {
+		t.Error("expected the stashed Ask to succeed", err)
+	}
+
+	for _, expected := range []string{"first", "second", "third"} {
+		if msg := <-handled; msg != expected {
+			t.Error("expected stashed messages to be handled in order", msg, expected)
+		}
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
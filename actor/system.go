@@ -0,0 +1,307 @@
+package actor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// SystemOption configures a System.
+type SystemOption func(*supervisor.Options)
+
+// SystemPolicy sets the RestartPolicy applied to the System's actors.
+func SystemPolicy(policy supervisor.RestartPolicy) SystemOption {
+	return func(o *supervisor.Options) {
+		o.Policy = policy
+	}
+}
+
+// SystemMetrics sets the Metrics notified of restarts of the System's
+// actors; should it also implement MailboxMetrics then it's notified of
+// messages passing through their mailboxes too.
+func SystemMetrics(m supervisor.Metrics) SystemOption {
+	return func(o *supervisor.Options) {
+		o.Metrics = m
+	}
+}
+
+// SystemClock sets the Clock of the System's root Supervisor, which times
+// its actors' Timers, retries, batches and idleness; see supervisor.Clock.
+func SystemClock(clock supervisor.Clock) SystemOption {
+	return func(o *supervisor.Options) {
+		o.Clock = clock
+	}
+}
+
+// SpawnOption configures an actor spawned by a System.
+type SpawnOption func(*spawnOptions)
+
+type spawnOptions struct {
+	mailboxSize   int
+	escalate      bool
+	overflow      Overflow
+	priority      bool
+	urgentSize    int
+	poison        int
+	retries       *supervisor.RetryPolicy
+	acking        bool
+	batchSize     int
+	batchWindow   time.Duration
+	deadline      time.Duration
+	deadlineFails bool
+	idle          time.Duration
+	passive       bool
+
+	interceptors        []Interceptor
+	enqueueInterceptors []EnqueueInterceptor
+	store               MailboxStore
+}
+
+// MailboxSize sets the number of messages the actor's mailbox can hold; it
+// defaults to DefaultMailboxSize.
+func MailboxSize(n int) SpawnOption {
+	return func(o *spawnOptions) {
+		o.mailboxSize = n
+	}
+}
+
+// System manages a set of actors, each of which is run by the System's
+// root Supervisor.
+type System struct {
+	// undelivered and spawned are accessed atomically, so are first to
+	// ensure their alignment.
+	undelivered uint64
+	spawned     uint64
+
+	ctx      context.Context
+	root     *supervisor.Supervisor
+	metrics  MailboxMetrics
+	registry *registry
+	clock    supervisor.Clock
+
+	mu          sync.RWMutex
+	deadLetters func(DeadLetter)
+
+	interceptors        []Interceptor
+	enqueueInterceptors []EnqueueInterceptor
+	tracer              Tracer
+
+	grainsMu sync.Mutex
+	grains   map[string]grainKind
+}
+
+// NewSystem returns a running System, whose root Supervisor is stopped once
+// the context is cancelled.
+func NewSystem(ctx context.Context, opts ...SystemOption) (*System, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	o := &supervisor.Options{Context: ctx}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	root, err := supervisor.NewSupervisorWithOptions(o)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, _ := o.Metrics.(MailboxMetrics)
+
+	root.Run()
+	return &System{
+		ctx:      o.Context,
+		root:     root,
+		metrics:  metrics,
+		registry: newRegistry(),
+		clock:    root.Clock(),
+		grains:   map[string]grainKind{},
+	}, nil
+}
+
+// Supervisor returns the System's root Supervisor.
+func (sys *System) Supervisor() *supervisor.Supervisor {
+	return sys.root
+}
+
+// Spawn starts an actor under the System, creating its mailbox. Actors must
+// have unique names, which mustn't be registered to another actor; should
+// the name be empty then one is generated.
+func (sys *System) Spawn(name string, a Actor, opts ...SpawnOption) (*ActorRef, error) {
+	return sys.spawn(nil, name, a, opts...)
+}
+
+// spawn starts an actor as Spawn does, as a child of the given parent
+// should it not be nil.
+func (sys *System) spawn(parent *ActorRef, name string, a Actor, opts ...SpawnOption) (*ActorRef, error) {
+	o := spawnOptions{mailboxSize: DefaultMailboxSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	mailbox := NewMailbox(o.mailboxSize)
+	if o.priority {
+		mailbox = NewPriorityMailbox(o.mailboxSize, o.urgentSize)
+	}
+
+	var stored []interface{}
+	if o.store != nil {
+		var err error
+		if stored, err = recoverStored(o.store); err != nil {
+			return nil, err
+		}
+	}
+
+	spawned := atomic.AddUint64(&sys.spawned, 1) - 1
+	if name == "" {
+		name = fmt.Sprintf("actor-%d", spawned)
+	}
+
+	mailbox.clock = sys.clock
+	ref := newActorRef(name, mailbox, sys)
+	ref.parent, ref.escalates, ref.overflow, ref.store = parent, o.escalate, o.overflow, o.store
+
+	sys.mu.RLock()
+	ref.enqueue = chainEnqueue(append(append([]EnqueueInterceptor(nil), sys.enqueueInterceptors...), o.enqueueInterceptors...))
+	interceptors := append(append([]Interceptor(nil), sys.interceptors...), o.interceptors...)
+	sys.mu.RUnlock()
+
+	// The name is reserved whilst the actor's worker is added, so it can't
+	// be registered to another actor in the meantime.
+	shard := sys.registry.shard(name)
+	shard.mu.Lock()
+	if _, ok := shard.names[name]; ok {
+		shard.mu.Unlock()
+		return nil, fmt.Errorf("%w: %q", ErrNameTaken, name)
+	}
+
+	_, exists := shard.actors[name]
+	if !exists {
+		shard.actors[name] = ref
+	}
+	shard.mu.Unlock()
+
+	r := &runtime{
+		actor:         a,
+		mailbox:       ref.mailbox,
+		ref:           ref,
+		replay:        stored,
+		poison:        o.poison,
+		retries:       o.retries,
+		acking:        o.acking,
+		batchSize:     o.batchSize,
+		batchWindow:   o.batchWindow,
+		deadline:      o.deadline,
+		deadlineFails: o.deadlineFails,
+		idle:          o.idle,
+		interceptors:  interceptors,
+		// The actor's worker can't remove itself, so its removal is left to
+		// another goroutine.
+		stopped:    func() { go sys.Stop(name) },
+		passivated: func() { go sys.passivate(ref) },
+	}
+	ref.spec = supervisor.WorkerSpec{Name: name, Worker: r.run}
+
+	release := func() {
+		if !exists {
+			shard.mu.Lock()
+			if shard.actors[name] == ref {
+				delete(shard.actors, name)
+			}
+			shard.mu.Unlock()
+		}
+	}
+
+	if parent != nil {
+		if err := parent.adopt(ref); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	if o.passive {
+		// A passive actor's worker is only added upon it being sent a
+		// message, so the duplicate name must be caught here instead.
+		if exists {
+			return nil, fmt.Errorf("%w: %q", ErrNameTaken, name)
+		}
+
+		ref.passive = true
+		return ref, nil
+	}
+
+	if err := sys.root.AddWorker(ref.spec); err != nil {
+		if parent != nil {
+			parent.orphan(ref)
+		}
+
+		release()
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// Stop stops the named actor, waiting for it to exit, and removes it from
+// the System along with any names registered to it; the actor may be named
+// by any of its registered names. The actor's children are then stopped in
+// turn. It returns false should there be no such actor.
+func (sys *System) Stop(name string) bool {
+	ref, ok := sys.Whereis(name)
+	if !ok || !sys.unregister(ref) {
+		return false
+	}
+
+	ref.markStopped()
+	removed := sys.root.RemoveWorker(ref.name) || ref.Passive()
+	ref.drain()
+	ref.exited(nil, 0)
+
+	if ref.parent != nil {
+		ref.parent.orphan(ref)
+	}
+
+	for _, child := range ref.Children() {
+		sys.Stop(child.name)
+	}
+
+	return removed
+}
+
+// Actors returns the names of every actor within the System, sorted
+// alphabetically.
+func (sys *System) Actors() []string {
+	names := []string{}
+	sys.registry.each(func(shard *registryShard) {
+		for name := range shard.actors {
+			names = append(names, name)
+		}
+	})
+
+	sort.Strings(names)
+	return names
+}
+
+// Shutdown gracefully stops every actor, along with the root Supervisor;
+// see Supervisor.Shutdown.
+func (sys *System) Shutdown(ctx context.Context) error {
+	sys.registry.each(func(shard *registryShard) {
+		for _, ref := range shard.actors {
+			ref.markStopped()
+		}
+	})
+
+	return sys.root.Shutdown(ctx)
+}
+
+// done returns the channels closed upon the System's context being
+// cancelled, and upon its root Supervisor beginning to stop.
+func (sys *System) done() (<-chan struct{}, <-chan struct{}) {
+	return sys.ctx.Done(), sys.root.Stopping()
+}
@@ -0,0 +1,71 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func Test_SystemMustSpawnSupervisedActors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	totals := make(chan int, 10)
+	total := 0
+	ref, err := sys.Spawn("counter", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		if msg == "panic" {
+			panic("testing")
+		}
+
+		total += msg.(int)
+		totals <- total
+		return nil
+	}), MailboxSize(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sys.Spawn("counter", ActorFunc(nil)); !errors.Is(err, supervisor.ErrDuplicateName) {
+		t.Error("expected actors to require unique names", err)
+	}
+
+	for _, msg := range []interface{}{1, "panic", 2} {
+		if err := ref.Tell(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	<-totals
+	if total := <-totals; total != 3 {
+		t.Error("expected the actor's state to survive its restart", total)
+	}
+
+	if workers := sys.Supervisor().WorkerInfo("counter"); len(workers) != 1 || workers[0].Restarts != 1 {
+		t.Error("expected the actor to be supervised by the root supervisor", workers)
+	}
+
+	anonymous, err := sys.Spawn("", ActorFunc(func(ctx context.Context, msg interface{}) error { return nil }))
+	if err != nil || anonymous.Name() == "" {
+		t.Fatal("expected a name to be generated", err)
+	}
+
+	anonymous.TellControl(Stop)
+	<-time.After(time.Millisecond * 50)
+
+	if actors := sys.Actors(); len(actors) != 1 || actors[0] != "counter" {
+		t.Error("expected the stopped actor to be removed", actors)
+	}
+
+	if err := sys.Shutdown(context.Background()); err != nil {
+		t.Error(err)
+	}
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,114 @@
+package actor
+
+import (
+	"sync"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// Timer delivers a message to an actor after a delay, and optionally at a
+// regular interval thereafter; see System.SendAfter and SendRepeatedly.
+// Timers are cancelled once the actor they deliver to is stopped, or its
+// System shut down, so needn't be stopped explicitly.
+type Timer struct {
+	ref      *ActorRef
+	msg      interface{}
+	interval time.Duration
+
+	mu      sync.Mutex
+	cancel  func() bool
+	stopped bool
+}
+
+// SendAfter sends a message to the actor once the duration has elapsed; a
+// Server receives it via HandleInfo. The returned Timer may be stopped to
+// cancel the message.
+func (sys *System) SendAfter(ref *ActorRef, msg interface{}, d time.Duration) *Timer {
+	return ref.schedule(msg, d, 0)
+}
+
+// SendRepeatedly sends a message to the actor every interval, until the
+// returned Timer is stopped. Should the actor's mailbox be full then the
+// next message is sent an interval after it has been delivered, rather than
+// messages accumulating.
+func (sys *System) SendRepeatedly(ref *ActorRef, msg interface{}, interval time.Duration) *Timer {
+	return ref.schedule(msg, interval, interval)
+}
+
+// Stop cancels the Timer, returning whether it was still active.
+func (t *Timer) Stop() bool {
+	t.mu.Lock()
+	active := !t.stopped
+	t.stopped = true
+	t.cancel()
+	t.mu.Unlock()
+
+	t.ref.mu.Lock()
+	delete(t.ref.timers, t)
+	t.ref.mu.Unlock()
+
+	return active
+}
+
+func (t *Timer) fire() {
+	t.mu.Lock()
+	stopped := t.stopped
+	t.mu.Unlock()
+
+	if stopped {
+		return
+	}
+
+	if err := t.ref.Tell(t.msg); err != nil || t.interval <= 0 {
+		t.Stop()
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.stopped {
+		t.cancel = supervisor.AfterFunc(t.ref.system.clock, t.interval, t.fire)
+	}
+}
+
+// schedule starts a Timer delivering to the actor, which is cancelled upon
+// the actor being stopped.
+func (ref *ActorRef) schedule(msg interface{}, d, interval time.Duration) *Timer {
+	t := &Timer{ref: ref, msg: msg, interval: interval}
+
+	t.mu.Lock()
+	t.cancel = supervisor.AfterFunc(ref.system.clock, d, t.fire)
+	t.mu.Unlock()
+
+	// The Timer may have already fired, and stopped, should the delay be
+	// short enough.
+	ref.mu.Lock()
+	t.mu.Lock()
+	if !t.stopped {
+		ref.timers[t] = true
+	}
+	t.mu.Unlock()
+	ref.mu.Unlock()
+
+	if !ref.Alive() {
+		t.Stop()
+	}
+
+	return t
+}
+
+// cancelTimers stops every Timer delivering to the actor.
+func (ref *ActorRef) cancelTimers() {
+	ref.mu.Lock()
+	timers := make([]*Timer, 0, len(ref.timers))
+	for t := range ref.timers {
+		timers = append(timers, t)
+	}
+	ref.mu.Unlock()
+
+	for _, t := range timers {
+		t.Stop()
+	}
+}
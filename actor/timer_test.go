@@ -0,0 +1,87 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func Test_TimersMustBeCancelledWithTheirActor(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ticks := make(chan interface{}, 16)
+	ref, _ := sys.Spawn("ticker", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		ticks <- msg
+		return nil
+	}))
+
+	repeating := sys.SendRepeatedly(ref, "tick", time.Millisecond*10)
+	cancelled := sys.SendAfter(ref, "cancelled", time.Millisecond*20)
+	if !cancelled.Stop() || cancelled.Stop() {
+		t.Error("expected Stop to report whether the timer was active")
+	}
+
+	<-time.After(time.Millisecond * 55)
+	if n := len(ticks); n < 3 || n > 6 {
+		t.Error("expected the message to be sent repeatedly", n)
+	}
+
+	sys.SendAfter(ref, "pending", time.Millisecond*20)
+	sys.Stop("ticker")
+	for len(ticks) > 0 {
+		if msg := <-ticks; msg != "tick" {
+			t.Error("expected only the repeated message to be delivered", msg)
+		}
+	}
+
+	<-time.After(time.Millisecond * 30)
+	if len(ticks) != 0 || repeating.Stop() {
+		t.Error("expected the timers to be cancelled with the actor")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_TimersMustFollowTheSystemClock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	clock := supervisor.NewFakeClock(time.Unix(0, 0))
+	sys, err := NewSystem(context.Background(), SystemClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ticks := make(chan interface{}, 16)
+	ref, _ := sys.Spawn("ticker", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		ticks <- msg
+		return nil
+	}))
+
+	sys.SendAfter(ref, "later", time.Hour)
+	<-time.After(time.Millisecond * 20)
+	if len(ticks) != 0 {
+		t.Fatal("expected the message to wait for the clock")
+	}
+
+	clock.Advance(time.Hour)
+	select {
+	case msg := <-ticks:
+		if msg != "later" {
+			t.Error("unexpected message", msg)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the message once the clock had advanced")
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
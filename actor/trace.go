@@ -0,0 +1,106 @@
+package actor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Tracer creates a span for each message handled by an actor, and
+// propagates trace context through the Headers of Envelopes; it's the
+// extension point for exporting traces to a tracing system, such as
+// OpenTelemetry - whose propagation.MapCarrier can wrap the Headers
+// directly. See System.Trace.
+type Tracer interface {
+	// Start is called as an actor begins handling a message, returning the
+	// context passed to Handle - carrying the span - along with a function
+	// which is called with the outcome once the message has been handled.
+	Start(ctx context.Context, span MessageSpan) (context.Context, func(err error))
+	// Inject writes the trace context carried by ctx to the Headers of an
+	// Envelope being sent, so the span of the actor which handles it is
+	// connected to that of its sender.
+	Inject(ctx context.Context, headers map[string]string)
+}
+
+// MessageSpan describes a message being handled by an actor, from which a
+// Tracer creates a span.
+type MessageSpan struct {
+	// Actor is the name of the actor handling the message.
+	Actor string
+	// MessageType is the Go type of the message.
+	MessageType string
+	// QueueLatency is how long the message waited in the actor's mailbox.
+	QueueLatency time.Duration
+	// Envelope is the Envelope the message was sent within, if any; its
+	// Headers carry the trace context of the message's sender.
+	Envelope *Envelope
+}
+
+type tracerKey struct{}
+
+// Trace has the Tracer create a span for each message handled by the
+// System's actors, and propagate trace context through the Envelopes they
+// send; as with Intercept, spans are only created for actors spawned
+// afterwards.
+func (sys *System) Trace(t Tracer) {
+	sys.mu.Lock()
+	sys.tracer = t
+	sys.mu.Unlock()
+
+	sys.Intercept(func(next Actor) Actor {
+		return ActorFunc(func(ctx context.Context, msg interface{}) (err error) {
+			span := MessageSpan{MessageType: fmt.Sprintf("%T", msg)}
+			if self, ok := Self(ctx); ok {
+				span.Actor = self.name
+			}
+
+			if cur, ok := ctx.Value(currentKey{}).(*current); ok {
+				span.QueueLatency = cur.latency
+			}
+
+			span.Envelope, _ = CurrentEnvelope(ctx)
+
+			ctx, end := t.Start(ctx, span)
+			defer func() {
+				if reason := recover(); reason != nil {
+					end(fmt.Errorf("%w: %v", ErrActorFailed, reason))
+					panic(reason)
+				}
+
+				end(err)
+			}()
+
+			return next.Handle(context.WithValue(ctx, tracerKey{}, t), msg)
+		})
+	})
+}
+
+// inject writes the trace context carried by ctx to the Envelope's Headers,
+// should the System have a Tracer.
+func (sys *System) inject(ctx context.Context, env *Envelope) {
+	sys.mu.RLock()
+	t := sys.tracer
+	sys.mu.RUnlock()
+
+	if t == nil {
+		return
+	}
+
+	if env.Headers == nil {
+		env.Headers = map[string]string{}
+	}
+
+	t.Inject(ctx, env.Headers)
+}
+
+// newEnvelopeTo builds an Envelope for a message sent to the actor, using
+// the Tracer of the actor's System should the message not be sent by an
+// actor.
+func newEnvelopeTo(ctx context.Context, ref *ActorRef, msg interface{}) *Envelope {
+	env := NewEnvelope(ctx, msg)
+	if env.Sender == nil {
+		ref.system.inject(ctx, env)
+	}
+
+	return env
+}
@@ -0,0 +1,82 @@
+package actor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []MessageSpan
+	ended int
+}
+
+func (t *recordingTracer) Start(ctx context.Context, span MessageSpan) (context.Context, func(error)) {
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, span.Actor), func(error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.ended++
+	}
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, headers map[string]string) {
+	if parent, ok := ctx.Value(spanKey{}).(string); ok {
+		headers["parent"] = parent
+	}
+}
+
+func Test_TracerMustConnectSpansThroughEnvelopes(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := &recordingTracer{}
+	sys.Trace(tracer)
+
+	b, _ := sys.Spawn("b", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		Reply(ctx, "done")
+		return nil
+	}))
+	a, _ := sys.Spawn("a", ActorFunc(func(ctx context.Context, msg interface{}) error {
+		reply, err := Ask[string](ctx, b, 42)
+		Reply(ctx, reply)
+		return err
+	}))
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "client")
+	if reply, err := Ask[string](ctx, a, "start"); reply != "done" || err != nil {
+		t.Fatal(reply, err)
+	}
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	if len(tracer.spans) != 2 || tracer.ended != 2 {
+		t.Fatal("expected a span per handled message", tracer.spans)
+	}
+
+	first, second := tracer.spans[0], tracer.spans[1]
+	if first.Actor != "a" || first.MessageType != "string" || first.Envelope.Headers["parent"] != "client" {
+		t.Error("expected the first span to continue the client's trace", first)
+	}
+
+	if second.Actor != "b" || second.MessageType != "int" || second.Envelope.Headers["parent"] != "a" {
+		t.Error("expected the second span to be connected to the first", second)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,79 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnexpectedMessage is returned by a TypedActor upon being sent a message
+// of the wrong type via its untyped ActorRef.
+var ErrUnexpectedMessage = errors.New("actor: unexpected message")
+
+// TypedActor is an Actor which handles messages of a single type, sparing
+// it from asserting the type of each message. As a generic type can't share
+// the name of the untyped Actor, the typed API is prefixed accordingly.
+type TypedActor[T any] interface {
+	Handle(ctx context.Context, msg T) error
+}
+
+// TypedActorFunc adapts a function to the TypedActor interface.
+type TypedActorFunc[T any] func(ctx context.Context, msg T) error
+
+// Handle calls f.
+func (f TypedActorFunc[T]) Handle(ctx context.Context, msg T) error {
+	return f(ctx, msg)
+}
+
+// Untyped adapts a TypedActor to the Actor interface; should it be sent a
+// message of any other type then it fails with ErrUnexpectedMessage. Should
+// the TypedActor implement TypedBatchHandler then so does the Actor.
+func Untyped[T any](a TypedActor[T]) Actor {
+	untyped := ActorFunc(func(ctx context.Context, msg interface{}) error {
+		typed, ok := msg.(T)
+		if !ok {
+			return fmt.Errorf("%w: %T", ErrUnexpectedMessage, msg)
+		}
+
+		return a.Handle(ctx, typed)
+	})
+
+	if batch, ok := a.(TypedBatchHandler[T]); ok {
+		return untypedBatch[T]{Actor: untyped, batch: batch}
+	}
+
+	return untyped
+}
+
+// TypedRef is a handle to a TypedActor, which only accepts messages of the
+// type the actor handles.
+type TypedRef[T any] struct {
+	ref *ActorRef
+}
+
+// SpawnTyped starts a TypedActor under the System, as Spawn does.
+func SpawnTyped[T any](sys *System, name string, a TypedActor[T], opts ...SpawnOption) (*TypedRef[T], error) {
+	ref, err := sys.Spawn(name, Untyped(a), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedRef[T]{ref: ref}, nil
+}
+
+// Tell delivers a message to the actor's mailbox; see ActorRef.Tell.
+func (t *TypedRef[T]) Tell(msg T) error {
+	return t.ref.Tell(msg)
+}
+
+// Ref returns the untyped ActorRef, through which the actor's lifecycle can
+// be queried and controlled.
+func (t *TypedRef[T]) Ref() *ActorRef {
+	return t.ref
+}
+
+// AskTyped sends a message to a TypedActor and waits for its reply of type
+// R; see Ask.
+func AskTyped[T, R any](ctx context.Context, ref *TypedRef[T], msg T) (R, error) {
+	return Ask[R](ctx, ref.ref, msg)
+}
@@ -0,0 +1,45 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type deposit struct {
+	amount int
+}
+
+func Test_TypedActorMustHandleTypedMessages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	sys, err := NewSystem(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balance := 0
+	ref, err := SpawnTyped[deposit](sys, "account", TypedActorFunc[deposit](func(ctx context.Context, msg deposit) error {
+		balance += msg.amount
+		Reply(ctx, balance)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref.Tell(deposit{amount: 10})
+	if total, err := AskTyped[deposit, int](context.Background(), ref, deposit{amount: 5}); total != 15 || err != nil {
+		t.Error("expected a typed reply", total, err)
+	}
+
+	if _, err := Ask[int](context.Background(), ref.Ref(), "not a deposit"); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Error("expected a message of the wrong type to be rejected", err)
+	}
+
+	sys.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
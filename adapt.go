@@ -0,0 +1,55 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrWorkerPanicked is returned by a function adapted via ToFunc should the
+// Supervisable panic.
+var ErrWorkerPanicked = errors.New("supervisor: worker panicked")
+
+// AdaptLegacy adapts a function with the done-channel signature to a
+// Supervisable, for code written before the requirements of Supervisable
+// were enforced; the function needn't recover panics, nor close the done
+// channel, as both are handled on its behalf. A function which does close
+// its channel is safe to adapt, as it's given a channel of its own.
+//
+// Together with Func and ToFunc this allows workers of either signature to
+// be mixed whilst migrating between them.
+func AdaptLegacy(fn func(context.Context, chan struct{})) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		fn(ctx, make(chan struct{}))
+	}
+}
+
+// ToFunc adapts a Supervisable to the context-only signature; it's the
+// reverse of Func. The returned function runs the Supervisable until it
+// exits, returning the error it reported via ReportError - or an error
+// wrapping ErrWorkerPanicked, should it have panicked.
+func ToFunc(s Supervisable) func(context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, report := withExitReport(ctx)
+
+		// A Supervisable which fails to recover its own panic is recovered
+		// here, so the panic is reported in the same way.
+		func() {
+			defer Recover(ctx, make(chan struct{}))
+			s(ctx, make(chan struct{}))
+		}()
+
+		exit := report.get()
+		switch {
+		case exit.Reason == nil:
+			return nil
+		case exit.Panicked:
+			return fmt.Errorf("%w: %v", ErrWorkerPanicked, exit.Reason)
+		}
+
+		// Only errors are reported via ReportError.
+		return exit.Reason.(error)
+	}
+}
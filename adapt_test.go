@@ -0,0 +1,65 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_AdaptLegacyMustRecoverOnBehalfOfTheWorker(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{
+			Name: "legacy",
+			Worker: AdaptLegacy(func(ctx context.Context, done chan struct{}) {
+				panic("testing")
+			}),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+
+	history := s.History("legacy", 0)
+	if len(history) == 0 || !history[0].Panicked || history[0].Reason != "testing" {
+		t.Error("panic not recovered on behalf of the worker", history)
+	}
+}
+
+func Test_ToFuncMustReturnTheReasonForExiting(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	reporting := ToFunc(func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+		ReportError(ctx, errTest)
+	})
+
+	if err := reporting(context.Background()); err != errTest {
+		t.Error("reported error not returned", err)
+	}
+
+	panicking := ToFunc(func(ctx context.Context, done chan struct{}) {
+		panic("testing")
+	})
+
+	if err := panicking(context.Background()); !errors.Is(err, ErrWorkerPanicked) {
+		t.Error("panic not returned as an error", err)
+	}
+
+	returning := ToFunc(func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+	})
+
+	if err := returning(context.Background()); err != nil {
+		t.Error("unexpected error", err)
+	}
+}
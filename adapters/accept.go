@@ -0,0 +1,198 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// DefaultMaxConnections is the number of connections an AcceptLoop handles
+// at once, should no MaxConnections be given.
+const DefaultMaxConnections = 1024
+
+// maxAcceptDelay bounds the backoff between retries of a temporary error
+// from Accept.
+const maxAcceptDelay = time.Second
+
+// MaxConnections sets the number of connections an AcceptLoop handles at
+// once; further connections aren't accepted until one of them closes.
+func MaxConnections(n int) Option {
+	return func(o *options) {
+		o.maxConns = n
+	}
+}
+
+// ConnHandler handles a connection accepted by an AcceptLoop; the
+// connection is closed once it returns. The context is cancelled once the
+// AcceptLoop is stopped, after which the handler has the GracePeriod to
+// return before the connection is closed beneath it.
+type ConnHandler func(ctx context.Context, conn net.Conn)
+
+// AcceptLoop returns a Supervisable which accepts connections from the
+// listener, handling each within its own goroutine - up to MaxConnections
+// at once - and reporting itself ready once accepting. The number of
+// connections being handled is reported via ObserveQueue.
+//
+// A handler which panics only affects its own connection, which is closed.
+// Temporary errors from Accept are retried with a backoff; any other error
+// is reported as the reason for the exit, upon which the accept loop is
+// restarted and the connections it was handling are closed. The listener
+// is closed upon the worker being stopped, and not before, so it's reused
+// by each restart.
+func AcceptLoop(ln net.Listener, handle ConnHandler, opts ...Option) supervisor.Supervisable {
+	o := newOptions(opts)
+
+	return func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		exited := make(chan struct{})
+		defer close(exited)
+		go func() {
+			select {
+			case <-ctx.Done():
+				ln.Close()
+			case <-exited:
+			}
+		}()
+
+		runCtx, cancel := context.WithCancel(ctx)
+		conns := &connSet{conns: map[net.Conn]bool{}, slots: make(chan struct{}, o.maxConns)}
+		defer conns.drain(supervisor.ClockFrom(ctx), cancel, o.grace)
+
+		supervisor.ObserveQueue(ctx, func() (int, int) {
+			return len(conns.slots), cap(conns.slots)
+		})
+
+		supervisor.Ready(ctx)
+		var (
+			delay time.Duration
+			retry supervisor.Timer
+		)
+		defer func() {
+			if retry != nil {
+				retry.Stop()
+			}
+		}()
+
+		for {
+			select {
+			case conns.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			conn, err := ln.Accept()
+			if err != nil {
+				<-conns.slots
+				if ctx.Err() != nil {
+					return
+				}
+
+				if temp, ok := err.(interface{ Temporary() bool }); ok && temp.Temporary() {
+					delay = backoff(delay)
+					if retry == nil {
+						retry = supervisor.ClockFrom(ctx).NewTimer(delay)
+					} else {
+						retry.Reset(delay)
+					}
+
+					select {
+					case <-retry.C():
+						continue
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				supervisor.ReportError(ctx, err)
+				return
+			}
+
+			delay = 0
+			conns.serve(runCtx, conn, handle)
+		}
+	}
+}
+
+// backoff returns the delay before the next retry of Accept, as used by
+// net/http.
+func backoff(delay time.Duration) time.Duration {
+	if delay == 0 {
+		return 5 * time.Millisecond
+	}
+
+	if delay *= 2; delay > maxAcceptDelay {
+		delay = maxAcceptDelay
+	}
+
+	return delay
+}
+
+// connSet tracks the connections being handled by an AcceptLoop, each of
+// which holds one of its slots.
+type connSet struct {
+	wg    sync.WaitGroup
+	slots chan struct{}
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+}
+
+func (s *connSet) serve(ctx context.Context, conn net.Conn, handle ConnHandler) {
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.slots }()
+		defer func() {
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+
+			conn.Close()
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				supervisor.Log(fmt.Sprintf("adapters: connection from %s panicked: %v", conn.RemoteAddr(), r))
+			}
+		}()
+
+		handle(ctx, conn)
+	}()
+}
+
+// drain cancels the handlers' context, giving them the grace period to
+// return before closing their connections, and waits for them to exit.
+func (s *connSet) drain(clock supervisor.Clock, cancel context.CancelFunc, grace time.Duration) {
+	cancel()
+
+	exited := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(exited)
+	}()
+
+	timer := clock.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-exited:
+		return
+	case <-timer.C():
+	}
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	<-exited
+}
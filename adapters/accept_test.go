@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func Test_AcceptLoopMustBoundAndDrainConnections(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan string, 10)
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{
+			Name: "accept",
+			Worker: AcceptLoop(ln, func(ctx context.Context, conn net.Conn) {
+				line, _ := bufio.NewReader(conn).ReadString('\n')
+				if line == "panic\n" {
+					panic("testing")
+				}
+
+				handled <- line
+				<-ctx.Done()
+			}, MaxConnections(2), GracePeriod(time.Millisecond*50)),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	dial := func(line string) net.Conn {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conn.Write([]byte(line))
+		return conn
+	}
+
+	panicked := dial("panic\n")
+	<-time.After(time.Millisecond * 20)
+	if _, err := panicked.Read(make([]byte, 1)); err == nil {
+		t.Error("expected the panicking connection to be closed")
+	}
+	panicked.Close()
+
+	first, second, third := dial("first\n"), dial("second\n"), dial("third\n")
+	defer first.Close()
+	defer second.Close()
+	defer third.Close()
+
+	<-time.After(time.Millisecond * 50)
+	if len(handled) != 2 {
+		t.Error("expected connections to be bounded by MaxConnections", len(handled))
+	}
+
+	infos := s.WorkerInfo("accept")
+	if len(infos) != 1 || infos[0].QueueDepth != 2 || infos[0].QueueCapacity != 2 || infos[0].Restarts != 0 {
+		t.Error("expected the connections to be observed, and the loop unaffected by the panic", infos)
+	}
+
+	s.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+
+	if _, err := net.Dial("tcp", ln.Addr().String()); err == nil {
+		t.Error("expected the listener to be closed upon stopping")
+	}
+}
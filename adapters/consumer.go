@@ -0,0 +1,182 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// DefaultFetchRetry is how a ConsumerLoop retries a failed Fetch, should no
+// FetchRetry be given.
+var DefaultFetchRetry = supervisor.RetryPolicy{
+	Attempts: 5,
+	Backoff:  supervisor.Backoff{Initial: 100 * time.Millisecond, Max: 5 * time.Second, Multiplier: 2},
+}
+
+// Consumer is the shape of a message broker's client - such as a Kafka
+// consumer group, an AMQP channel, or an SQS queue - allowing it to be run
+// by a ConsumerLoop.
+type Consumer[M any] interface {
+	// Fetch blocks until a message is available, returning it, or the
+	// context is cancelled.
+	Fetch(ctx context.Context) (M, error)
+	// Ack acknowledges a message as having been handled.
+	Ack(ctx context.Context, msg M) error
+	// Nack rejects a message, which failed to be handled, so the broker may
+	// redeliver it.
+	Nack(ctx context.Context, msg M) error
+}
+
+// Concurrency sets the number of messages a ConsumerLoop handles at once,
+// each fetched and handled by its own goroutine; it defaults to 1.
+func Concurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// FetchRetry sets how a ConsumerLoop retries a failed Fetch; see
+// DefaultFetchRetry.
+func FetchRetry(policy supervisor.RetryPolicy) Option {
+	return func(o *options) {
+		o.fetchRetry = policy
+	}
+}
+
+// ConsumerLoop returns a Supervisable which fetches messages from the
+// Consumer and passes them to the handler, acknowledging each it handles
+// and rejecting those it returns an error for - or panics upon. Up to
+// Concurrency messages are handled at once, the number in flight being
+// reported via ObserveQueue.
+//
+// A failed Fetch is retried according to FetchRetry; should the retries be
+// exhausted then the error is reported as the reason for the exit, upon
+// which the loop is restarted. Once the worker is stopped no further
+// messages are fetched, whilst those in flight are given the GracePeriod
+// to be handled before the handlers' context is cancelled.
+func ConsumerLoop[M any](c Consumer[M], handle func(ctx context.Context, msg M) error, opts ...Option) supervisor.Supervisable {
+	o := newOptions(opts)
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+
+	return func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		// Fetching stops as soon as the worker is stopped, or any goroutine
+		// fails; handling continues through the grace period.
+		fetchCtx, stopFetching := context.WithCancel(ctx)
+		defer stopFetching()
+
+		handleCtx, stopHandling := context.WithCancel(detached{ctx})
+		defer stopHandling()
+
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-finished:
+				return
+			}
+
+			timer := supervisor.ClockFrom(ctx).NewTimer(o.grace)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C():
+				stopHandling()
+			case <-finished:
+			}
+		}()
+
+		var (
+			wg       sync.WaitGroup
+			once     sync.Once
+			failure  error
+			inflight int64
+		)
+
+		supervisor.ObserveQueue(ctx, func() (int, int) {
+			return int(atomic.LoadInt64(&inflight)), o.concurrency
+		})
+
+		supervisor.Ready(ctx)
+		for i := 0; i < o.concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for {
+					var msg M
+					err := supervisor.Retry(fetchCtx, func(ctx context.Context) (err error) {
+						msg, err = c.Fetch(ctx)
+						return err
+					}, o.fetchRetry)
+
+					if fetchCtx.Err() != nil {
+						// The message was fetched as the loop stopped, so
+						// it's returned to the broker rather than handled.
+						if err == nil {
+							if err := c.Nack(handleCtx, msg); err != nil {
+								supervisor.Log(fmt.Sprintf("adapters: failed to nack message: %v", err))
+							}
+						}
+						return
+					} else if err != nil {
+						once.Do(func() { failure = err })
+						stopFetching()
+						return
+					}
+
+					atomic.AddInt64(&inflight, 1)
+					consume(handleCtx, c, msg, handle)
+					atomic.AddInt64(&inflight, -1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if failure != nil {
+			supervisor.ReportError(ctx, failure)
+		}
+	}
+}
+
+// consume handles a single message, acknowledging or rejecting it.
+func consume[M any](ctx context.Context, c Consumer[M], msg M, handle func(context.Context, M) error) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%w: %v", supervisor.ErrWorkerPanicked, r)
+			}
+		}()
+
+		return handle(ctx, msg)
+	}()
+
+	if err != nil {
+		if err := c.Nack(ctx, msg); err != nil {
+			supervisor.Log(fmt.Sprintf("adapters: failed to nack message: %v", err))
+		}
+		return
+	}
+
+	if err := c.Ack(ctx, msg); err != nil {
+		supervisor.Log(fmt.Sprintf("adapters: failed to ack message: %v", err))
+	}
+}
+
+// detached is a context carrying its parent's values, but not its
+// cancellation.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
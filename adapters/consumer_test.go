@@ -0,0 +1,147 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+// queue is a Consumer of ints, whose Fetch fails whilst failing is set.
+type queue struct {
+	messages chan int
+
+	mu      sync.Mutex
+	failing int
+	acked   []int
+	nacked  []int
+}
+
+func (q *queue) Fetch(ctx context.Context) (int, error) {
+	q.mu.Lock()
+	if q.failing > 0 {
+		q.failing--
+		q.mu.Unlock()
+		return 0, errors.New("unavailable")
+	}
+	q.mu.Unlock()
+
+	select {
+	case msg := <-q.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (q *queue) Ack(ctx context.Context, msg int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.acked = append(q.acked, msg)
+	return nil
+}
+
+func (q *queue) Nack(ctx context.Context, msg int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nacked = append(q.nacked, msg)
+	return nil
+}
+
+func Test_ConsumerLoopMustSettleMessagesAndDrainOnStop(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	q := &queue{messages: make(chan int, 10), failing: 2}
+	for i := 1; i <= 4; i++ {
+		q.messages <- i
+	}
+
+	started := make(chan struct{}, 10)
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{
+			Name: "consumer",
+			Worker: ConsumerLoop[int](q, func(ctx context.Context, msg int) error {
+				switch msg {
+				case 2:
+					return errors.New("failed")
+				case 3:
+					panic("testing")
+				case 5:
+					started <- struct{}{}
+					<-time.After(time.Millisecond * 50)
+				}
+				return nil
+			}, Concurrency(2), FetchRetry(supervisor.RetryPolicy{
+				Attempts: 3,
+				Backoff:  supervisor.Backoff{Initial: time.Millisecond},
+			})),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	q.messages <- 5
+	<-started
+	s.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.acked) != 3 || len(q.nacked) != 2 {
+		t.Error("expected handled messages to be acked, and failed messages nacked", q.acked, q.nacked)
+	}
+
+	if infos := s.WorkerInfo("consumer"); len(infos) != 1 || infos[0].Restarts != 0 {
+		t.Error("expected fetch failures to be retried without restarting", infos)
+	}
+}
+
+// lateQueue is a Consumer whose Fetch returns a message only once its
+// context is cancelled, as a client may should it be mid-delivery.
+type lateQueue struct {
+	queue
+}
+
+func (q *lateQueue) Fetch(ctx context.Context) (int, error) {
+	<-ctx.Done()
+	return 1, nil
+}
+
+func Test_ConsumerLoopMustNackMessagesFetchedUponStop(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	q := &lateQueue{}
+	handled := make(chan int, 1)
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{
+			Name: "consumer",
+			Worker: ConsumerLoop[int](q, func(ctx context.Context, msg int) error {
+				handled <- msg
+				return nil
+			}),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 20)
+	s.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 20)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(handled) != 0 || len(q.acked) != 0 || len(q.nacked) != 1 {
+		t.Error("expected the message fetched upon stopping to be nacked", q.acked, q.nacked)
+	}
+}
@@ -0,0 +1,138 @@
+// Package adapters provides Supervisables for the standard library's
+// long-running services, so they can be supervised without hand-writing the
+// plumbing between their lifecycles and a worker's context.
+package adapters
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// DefaultGracePeriod is how long a server is given to shut down gracefully,
+// should no GracePeriod be given.
+const DefaultGracePeriod = 10 * time.Second
+
+// Option configures an adapter.
+type Option func(*options)
+
+type options struct {
+	grace       time.Duration
+	maxConns    int
+	concurrency int
+	fetchRetry  supervisor.RetryPolicy
+}
+
+// GracePeriod sets how long a server - or the connections of an
+// AcceptLoop, or the messages being handled by a ConsumerLoop - is given to
+// shut down gracefully once the worker is stopped, before its remaining
+// connections are closed or handlers' contexts cancelled.
+func GracePeriod(d time.Duration) Option {
+	return func(o *options) {
+		o.grace = d
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		grace:       DefaultGracePeriod,
+		maxConns:    DefaultMaxConnections,
+		concurrency: 1,
+		fetchRetry:  DefaultFetchRetry,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// ErrServerShutDown is reported by a worker returned by HTTPServer should
+// it be run again once its http.Server has been shut down; see
+// HTTPServerFunc.
+var ErrServerShutDown = errors.New("adapters: http.Server has already been shut down")
+
+// HTTPServer returns a Supervisable which listens on the server's Addr and
+// serves it - over TLS should it have a TLSConfig - reporting itself ready
+// once listening. Upon the worker being stopped the server is shut down
+// gracefully via Shutdown, and closed should the GracePeriod elapse first.
+//
+// An http.Server can't be served once it's been shut down, so the worker
+// can only be run once: should it be restarted - such as by RestartWorkers,
+// alongside a sibling, or upon being resumed - it reports ErrServerShutDown
+// instead. Use HTTPServerFunc for a worker which can be restarted.
+func HTTPServer(srv *http.Server, opts ...Option) supervisor.Supervisable {
+	// Serving the server sets its TLSConfig, so whether it's served over
+	// TLS is determined beforehand.
+	useTLS := srv.TLSConfig != nil
+	return serveHTTP(func() (*http.Server, bool) { return srv, useTLS }, opts)
+}
+
+// HTTPServerFunc returns a Supervisable which serves as HTTPServer does, but
+// with a fresh http.Server created by newServer upon each run, allowing the
+// worker to be restarted. Any error returned by the server is reported as
+// the reason for the exit, upon which the server is served again.
+func HTTPServerFunc(newServer func() *http.Server, opts ...Option) supervisor.Supervisable {
+	return serveHTTP(func() (*http.Server, bool) {
+		srv := newServer()
+		return srv, srv.TLSConfig != nil
+	}, opts)
+}
+
+// serveHTTP serves the http.Server returned by newServer upon each run,
+// along with whether it should be served over TLS.
+func serveHTTP(newServer func() (*http.Server, bool), opts []Option) supervisor.Supervisable {
+	o := newOptions(opts)
+
+	return func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		srv, useTLS := newServer()
+		addr := srv.Addr
+		if addr == "" {
+			addr = ":http"
+			if useTLS {
+				addr = ":https"
+			}
+		}
+
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			supervisor.ReportError(ctx, err)
+			return
+		}
+
+		errs := make(chan error, 1)
+		go func() {
+			if useTLS {
+				errs <- srv.ServeTLS(ln, "", "")
+				return
+			}
+
+			errs <- srv.Serve(ln)
+		}()
+
+		supervisor.Ready(ctx)
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), o.grace)
+			defer cancel()
+
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				srv.Close()
+			}
+			<-errs
+		case err := <-errs:
+			// The worker's context is still live, so the server wasn't shut
+			// down by this run; it was shut down by a previous one.
+			if errors.Is(err, http.ErrServerClosed) {
+				err = ErrServerShutDown
+			}
+			supervisor.ReportError(ctx, err)
+		}
+	}
+}
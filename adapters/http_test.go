@@ -0,0 +1,120 @@
+package adapters
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func freeAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	return ln.Addr().String()
+}
+
+func Test_HTTPServerMustServeUntilStopped(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	addr := freeAddr(t)
+	inflight := make(chan struct{})
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(inflight)
+			<-time.After(time.Millisecond * 100)
+		}
+		w.Write([]byte("ok"))
+	})}
+
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{Name: "http", Worker: HTTPServer(srv, GracePeriod(time.Second)), Significant: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	if !s.Ready() {
+		t.Error("expected the server to report itself ready once listening")
+	}
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	slow := make(chan error, 1)
+	go func() {
+		resp, err := client.Get("http://" + addr + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		slow <- err
+	}()
+
+	<-inflight
+	s.Shutdown(context.Background())
+
+	if err := <-slow; err != nil {
+		t.Error("expected the in-flight request to complete during shutdown", err)
+	}
+
+	if _, err := client.Get("http://" + addr + "/"); err == nil {
+		t.Error("expected the server to have stopped listening")
+	}
+
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_HTTPServerFuncMustServeAgainAfterRestart(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	addr := freeAddr(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{
+			{Name: "func", Worker: HTTPServerFunc(func() *http.Server {
+				return &http.Server{Addr: addr, Handler: handler}
+			}, GracePeriod(time.Second))},
+			{Name: "reused", Worker: HTTPServer(&http.Server{Addr: freeAddr(t), Handler: handler}, GracePeriod(time.Second))},
+		},
+		Policy: supervisor.RestartPolicy{Backoff: supervisor.Backoff{Initial: time.Hour}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	s.RestartWorkers("func", "reused")
+	<-time.After(time.Millisecond * 50)
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatal("expected the server to be served again after a restart", err)
+	}
+	resp.Body.Close()
+
+	exits := s.History("reused", 0)
+	if len(exits) != 1 || exits[0].Reason != ErrServerShutDown {
+		t.Error("expected the reused server to report it had been shut down", exits)
+	}
+
+	s.Shutdown(context.Background())
+	<-time.After(time.Millisecond * 50)
+}
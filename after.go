@@ -0,0 +1,127 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DelayedWorker is a worker scheduled to start after a delay; see
+// Supervisor.After.
+type DelayedWorker struct {
+	name   string
+	cancel chan struct{}
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	ran     bool
+}
+
+// Name returns the name the worker is started under, by which it can be
+// found via ListWorkers and History once started.
+func (d *DelayedWorker) Name() string {
+	return d.name
+}
+
+// Cancel prevents the worker from starting, returning false should it have
+// already started - or been cancelled.
+func (d *DelayedWorker) Cancel() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.started || d.stopped {
+		return false
+	}
+
+	d.stopped = true
+	close(d.cancel)
+	return true
+}
+
+func (d *DelayedWorker) start() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopped {
+		return false
+	}
+
+	d.started = true
+	return true
+}
+
+// once returns whether the worker is yet to run, marking it as having run.
+func (d *DelayedWorker) once() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ran := d.ran
+	d.ran = true
+	return !ran
+}
+
+// After starts the Supervisable in the Supervisor's default group once the
+// delay has elapsed - unless cancelled beforehand, or the Supervisor is
+// stopped in the meantime. It's run exactly once: upon exiting - whether it
+// failed or not - it's removed from the Supervisor rather than restarted.
+// This is useful for delayed cleanup, or escalating should some condition
+// not have been met in time.
+//
+//	escalation := s.After(time.Minute, pageOnCall)
+//	// ... upon the condition being met:
+//	escalation.Cancel()
+func (s *Supervisor) After(delay time.Duration, fn Supervisable) *DelayedWorker {
+	s.mu.Lock()
+	d := &DelayedWorker{
+		name:   fmt.Sprintf("after-%d", s.delayed),
+		cancel: make(chan struct{}),
+	}
+	s.delayed++
+	stopping := s.stopping
+	s.mu.Unlock()
+
+	go func() {
+		timer := ClockFrom(s.parent).NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C():
+			if d.start() {
+				s.mu.Lock()
+				g := s.groups[0]
+				s.mu.Unlock()
+
+				s.scaleWorkers(g, WorkerSpec{Name: d.name, Worker: s.runOnce(d, fn), temporary: true}, 1)
+			}
+		case <-d.cancel:
+		case <-stopping:
+		}
+	}()
+
+	return d
+}
+
+// runOnce wraps the delayed worker so that it's removed once it exits.
+// Should it be started again before the removal takes effect - such as by
+// a sibling's failure restarting the group - it waits to be stopped instead
+// of running again.
+func (s *Supervisor) runOnce(d *DelayedWorker, fn Supervisable) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+
+		if !d.once() {
+			<-ctx.Done()
+			return
+		}
+
+		// The worker can't remove itself, as removal waits for it to exit,
+		// so its removal is left to another goroutine.
+		defer func() { go s.removeWorkers(d.name, 0) }()
+
+		exited := make(chan struct{})
+		fn(ctx, exited)
+		<-exited
+	}
+}
@@ -0,0 +1,125 @@
+package supervisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_SupervisorMustStartWorkerAfterDelay(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	delayed := &mockSupervisable{}
+	d := s.After(time.Millisecond*50, generateSupervisable(delayed))
+
+	cancelled := &mockSupervisable{}
+	c := s.After(time.Millisecond*50, generateSupervisable(cancelled))
+	if !c.Cancel() {
+		t.Error("expected a pending worker to be cancelled")
+	}
+
+	if len(s.WorkerInfo(d.Name())) != 0 {
+		t.Error("expected the worker not to have started before its delay")
+	}
+
+	<-time.After(time.Millisecond * 80)
+	if infos := s.WorkerInfo(d.Name()); len(infos) != 1 || !infos[0].Running {
+		t.Error("expected the worker to have started after its delay", infos)
+	}
+
+	if d.Cancel() {
+		t.Error("expected a started worker to not be cancellable")
+	}
+
+	if len(s.WorkerInfo(c.Name())) != 0 || cancelled.nCalls != 0 {
+		t.Error("expected the cancelled worker to have never started")
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 100)
+}
+
+func Test_SupervisorMustRunDelayedWorkerOnce(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	var succeeded, failed int32
+	ok := s.After(time.Millisecond*10, func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+		atomic.AddInt32(&succeeded, 1)
+	})
+	failing := s.After(time.Millisecond*10, func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+		atomic.AddInt32(&failed, 1)
+		panic("failed")
+	})
+
+	<-time.After(time.Millisecond * 100)
+	if n, m := atomic.LoadInt32(&succeeded), atomic.LoadInt32(&failed); n != 1 || m != 1 {
+		t.Error("expected each delayed worker to run exactly once", n, m)
+	}
+
+	if len(s.WorkerInfo(ok.Name())) != 0 || len(s.WorkerInfo(failing.Name())) != 0 {
+		t.Error("expected the delayed workers to be removed once they exited")
+	}
+
+	s.Stop()
+	s.Wait()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_DelayedWorkersMustNotExhaustTheRestartBudget(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "idle", Worker: func(ctx context.Context, done chan struct{}) {
+			defer close(done)
+			<-ctx.Done()
+		}}},
+		Policy: RestartPolicy{MaxRestarts: 1, Period: time.Minute, Escalation: StopSupervisor},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := s.Events(ctx)
+	s.Run()
+
+	var runs int32
+	for i := 0; i < 3; i++ {
+		s.After(time.Millisecond*10, func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			atomic.AddInt32(&runs, 1)
+		})
+	}
+
+	failures := 0
+	for e := range drain(events, time.Millisecond*100) {
+		if e.Type == WorkerFailed {
+			failures++
+		}
+	}
+
+	if n := atomic.LoadInt32(&runs); n != 3 || s.HasStopped() || failures != 0 {
+		t.Error("expected successful delayed workers to not count as failures", n, failures)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 100)
+}
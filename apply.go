@@ -0,0 +1,318 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrNotConfigured is returned by ApplyConfig for a Supervisor which wasn't
+// built via NewSupervisorFromConfig.
+var ErrNotConfigured = errors.New("supervisor: not built from a config")
+
+// ApplyConfig reconfigures a running Supervisor to match the given Config.
+// The desired tree is compared against the Config the Supervisor is
+// currently running with: new workers and groups are started, those which
+// no longer exist are stopped, and changes to a worker's count are applied
+// by starting or stopping instances. A worker whose definition has changed
+// in any other way is replaced, whilst nested Supervisors are reconfigured
+// recursively.
+//
+// The Config is validated, and all workers are constructed, before any
+// changes are made; should either fail then the running Supervisor is left
+// untouched.
+func (s *Supervisor) ApplyConfig(cfg *Config) error {
+	plan, err := s.planApply(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range plan {
+		change(s)
+	}
+
+	return nil
+}
+
+// planApply validates the Config and plans the changes required to apply
+// it, including those to any nested Supervisors, finishing by recording the
+// Config as the one the Supervisor is running with.
+func (s *Supervisor) planApply(cfg *Config) ([]configChange, error) {
+	s.mu.Lock()
+	current := s.config
+	ctx := s.parent
+	s.mu.Unlock()
+
+	if current == nil {
+		return nil, ErrNotConfigured
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planConfig(ctx, current, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(plan, func(s *Supervisor) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.config = cfg
+		s.shutdownTimeout = time.Duration(cfg.ShutdownTimeout)
+	}), nil
+}
+
+// configChange is a single modification to a running Supervisor.
+type configChange func(*Supervisor)
+
+// groupConfigs returns every group described by a Config, including the
+// default group, keyed by name.
+func groupConfigs(cfg *Config) map[string]GroupConfig {
+	groups := map[string]GroupConfig{
+		"": {Policy: cfg.Policy, Workers: cfg.Workers},
+	}
+
+	for _, g := range cfg.Groups {
+		groups[g.Name] = g
+	}
+
+	return groups
+}
+
+// planConfig determines the changes required to move from one Config to
+// another, constructing any new workers in the process. Removals are
+// planned before every other change, as workers are found by name across
+// groups: a worker which has moved to another group must be removed from
+// its old group before it's added to the new one.
+func (s *Supervisor) planConfig(ctx context.Context, current, desired *Config) ([]configChange, error) {
+	plan, removals := []configChange{}, []configChange{}
+	currentGroups := groupConfigs(current)
+
+	for name, g := range groupConfigs(desired) {
+		name, policy := name, g.Policy.RestartPolicy()
+		plan = append(plan, func(s *Supervisor) {
+			s.ensureGroup(name).setPolicy(policy)
+		})
+
+		currentWorkers := map[string]WorkerConfig{}
+		for _, wc := range currentGroups[name].Workers {
+			currentWorkers[wc.Name] = wc
+		}
+
+		for _, wc := range g.Workers {
+			cur, exists := currentWorkers[wc.Name]
+			delete(currentWorkers, wc.Name)
+
+			change, err := s.planWorker(ctx, name, cur, exists, wc)
+			if err != nil {
+				return nil, err
+			}
+
+			if change != nil {
+				plan = append(plan, change)
+			}
+		}
+
+		for removed := range currentWorkers {
+			removed := removed
+			removals = append(removals, func(s *Supervisor) {
+				s.removeWorkers(removed, 0)
+			})
+		}
+
+		delete(currentGroups, name)
+	}
+
+	for removed := range currentGroups {
+		removed := removed
+		removals = append(removals, func(s *Supervisor) {
+			s.removeGroup(removed)
+		})
+	}
+
+	return append(removals, plan...), nil
+}
+
+// planWorker determines the change required to move a single worker from
+// its current config to the desired one.
+func (s *Supervisor) planWorker(ctx context.Context, group string, current WorkerConfig, exists bool, desired WorkerConfig) (configChange, error) {
+	count := instanceCount(desired.Count)
+	unchanged := exists && sameDefinition(current, desired)
+
+	// A nested Supervisor is reconfigured in place, its changes planned
+	// along with those of its parent. Its instances share the one
+	// Supervisor, so a change to its count replaces it instead.
+	if unchanged && desired.Supervisor != nil && instanceCount(current.Count) == count {
+		if child := s.childSupervisor(desired.Name); child != nil {
+			plan, err := child.planApply(desired.Supervisor)
+			if err != nil {
+				return nil, err
+			}
+
+			return func(*Supervisor) {
+				for _, change := range plan {
+					change(child)
+				}
+			}, nil
+		}
+	}
+
+	if unchanged && desired.Supervisor == nil {
+		if instanceCount(current.Count) == count {
+			return nil, nil
+		}
+
+		spec, err := desired.spec(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(s *Supervisor) {
+			s.scaleWorkers(s.ensureGroup(group), spec, count)
+		}, nil
+	}
+
+	spec, err := desired.spec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(s *Supervisor) {
+		if exists {
+			s.removeWorkers(desired.Name, 0)
+		}
+		s.scaleWorkers(s.ensureGroup(group), spec, count)
+	}, nil
+}
+
+// sameDefinition compares two WorkerConfigs, disregarding their count and
+// the contents of any nested Supervisor.
+func sameDefinition(a, b WorkerConfig) bool {
+	a.Count, b.Count = 0, 0
+	if a.Supervisor != nil && b.Supervisor != nil {
+		a.Supervisor, b.Supervisor = nil, nil
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func instanceCount(count int) int {
+	if count < 1 {
+		return 1
+	}
+
+	return count
+}
+
+// ensureGroup returns the named group, creating - and if necessary,
+// starting - it should it not exist.
+func (s *Supervisor) ensureGroup(name string) *group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, g := range s.groups {
+		if g.name == name {
+			return g
+		}
+	}
+
+	g := newGroup(name, RestartPolicy{})
+	s.groups = append(s.groups, g)
+	if s.groups[0].context() != nil && s.ctx.Err() == nil {
+		g.start(s.ctx)
+	}
+
+	return g
+}
+
+// scaleWorkers adds or removes instances of a worker so that there are
+// exactly count instances, starting any new instances if the group is
+// running.
+func (s *Supervisor) scaleWorkers(g *group, spec WorkerSpec, count int) {
+	existing := len(s.findWorkers(spec.Name))
+	if existing > count {
+		s.removeWorkers(spec.Name, count)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := newInstances(spec, existing, count, s.historySize, g)
+	s.addWorkersLocked(added)
+	if g.isRunning() {
+		s.startWorkersLocked(added)
+	}
+}
+
+// removeWorkers stops and removes every instance of the named worker with
+// an index of at least "from", waiting for each to exit.
+func (s *Supervisor) removeWorkers(name string, from int) {
+	s.mu.Lock()
+	removed := []*worker{}
+	set := map[*worker]bool{}
+	for _, w := range s.byName[name] {
+		if w.instance >= from {
+			removed = append(removed, w)
+			set[w] = true
+		}
+	}
+
+	s.removeWorkersLocked(set)
+	groups := map[*group]bool{}
+	for _, w := range removed {
+		if !groups[w.group] {
+			groups[w.group] = true
+			w.group.removeWorkers(set)
+		}
+	}
+	s.mu.Unlock()
+
+	stopWorkers(context.Background(), removed)
+}
+
+// removeGroup stops and removes the named group along with its workers.
+func (s *Supervisor) removeGroup(name string) {
+	s.mu.Lock()
+	var removed *group
+	for _, g := range s.groups {
+		if g.name == name {
+			removed = g
+		}
+	}
+	s.mu.Unlock()
+
+	if removed == nil {
+		return
+	}
+
+	for _, w := range s.groupWorkers(name) {
+		s.removeWorkers(w.name, 0)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := s.groups[:0:0]
+	for _, g := range s.groups {
+		if g != removed {
+			groups = append(groups, g)
+		}
+	}
+	s.groups = groups
+}
+
+// childSupervisor returns the nested Supervisor run by the named worker.
+func (s *Supervisor) childSupervisor(name string) *Supervisor {
+	for _, w := range s.findWorkers(name) {
+		if w.child != nil {
+			return w.child
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package supervisor
+
+import (
+	"context"
+	"sync"
+)
+
+// bulkBatchSize is the number of worker instances acted upon by each
+// goroutine when starting, or stopping, a large set of instances at once.
+const bulkBatchSize = 1024
+
+// startWorkersLocked launches the run loops of the worker instances. Sets of
+// more than bulkBatchSize instances are started in batches, in parallel, so
+// that the latency of starting them scales sub-linearly with their number.
+func (s *Supervisor) startWorkersLocked(workers []*worker) {
+	inBatches(len(workers), func(from, to int) {
+		for _, w := range workers[from:to] {
+			s.startWorkerLocked(w)
+		}
+	})
+}
+
+// stopWorkers cancels the run loops of the worker instances, in batches as
+// with startWorkersLocked, and then waits for every one of them to exit; it
+// returns the context's error should it be cancelled first. As the run loops
+// exit concurrently, waiting upon them in turn takes only as long as the
+// slowest.
+func stopWorkers(ctx context.Context, workers []*worker) error {
+	exits := make([]chan struct{}, len(workers))
+	inBatches(len(workers), func(from, to int) {
+		for i := from; i < to; i++ {
+			exits[i] = workers[i].stop()
+		}
+	})
+
+	for _, exited := range exits {
+		select {
+		case <-exited:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// inBatches calls fn with the bounds of each batch of up to bulkBatchSize
+// elements amongst n, returning once every call has; should there be more
+// than a single batch then each is handled upon its own goroutine.
+func inBatches(n int, fn func(from, to int)) {
+	if n <= bulkBatchSize {
+		fn(0, n)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for from := 0; from < n; from += bulkBatchSize {
+		to := from + bulkBatchSize
+		if to > n {
+			to = n
+		}
+
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			fn(from, to)
+		}(from, to)
+	}
+
+	wg.Wait()
+}
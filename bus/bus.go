@@ -0,0 +1,215 @@
+// Package bus provides a lightweight, typed, in-process event bus whose
+// dispatch loop runs as a worker of a Supervisor.
+//
+// Events are delivered to the handlers subscribed to their type, one event
+// at a time and in the order they were published. Should a handler panic
+// then the dispatch loop is restarted by the Supervisor, resuming with the
+// next handler of the same event - so a faulty handler never stops events
+// from reaching the rest of the application.
+//
+//	b, err := bus.New(s, bus.Name("events"))
+//
+//	unsubscribe := bus.Subscribe(b, func(ctx context.Context, e UserCreated) {
+//		welcome(ctx, e.Email)
+//	})
+//	defer unsubscribe()
+//
+//	bus.Publish(b, UserCreated{Email: "ada@example.com"})
+package bus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrClosed is returned when publishing to a closed Bus.
+var ErrClosed = errors.New("bus: closed")
+
+// Option configures a Bus.
+type Option func(*options)
+
+type options struct {
+	name   string
+	buffer int
+}
+
+// Name names the Bus' worker within the Supervisor; it defaults to "bus".
+func Name(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// Buffer sets the number of events which may be queued before Publish
+// blocks; it defaults to 64.
+func Buffer(n int) Option {
+	return func(o *options) {
+		o.buffer = n
+	}
+}
+
+// Bus is a supervised, typed event bus; see Publish and Subscribe.
+type Bus struct {
+	events  chan event
+	closing chan struct{}
+
+	mu       sync.Mutex
+	closed   bool
+	handlers map[reflect.Type][]*handler
+
+	// pending is the event being dispatched, which is only accessed by the
+	// dispatch loop and persists across its restarts.
+	pending *delivery
+}
+
+// event is an event along with the type it was published as.
+type event struct {
+	typ   reflect.Type
+	value interface{}
+}
+
+type handler struct {
+	fn func(ctx context.Context, value interface{})
+}
+
+// delivery is the progress of dispatching an event to its handlers.
+type delivery struct {
+	event    event
+	handlers []*handler
+	next     int
+}
+
+// New adds a Bus' dispatch loop to the Supervisor, which is started should
+// the Supervisor be running.
+func New(s *supervisor.Supervisor, opts ...Option) (*Bus, error) {
+	o := options{name: "bus", buffer: 64}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	b := &Bus{
+		events:   make(chan event, o.buffer),
+		closing:  make(chan struct{}),
+		handlers: map[reflect.Type][]*handler{},
+	}
+
+	if err := s.AddWorker(supervisor.WorkerSpec{Name: o.name, Worker: b.dispatch}); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Publish queues an event for the handlers subscribed to type T, blocking
+// whilst the Bus' buffer is full; it returns ErrClosed should the Bus be
+// closed. Note that an event is delivered according to the type it's
+// published as, rather than its dynamic type.
+func Publish[T any](b *Bus, e T) error {
+	select {
+	case <-b.closing:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case b.events <- event{typ: typeOf[T](), value: e}:
+		return nil
+	case <-b.closing:
+		return ErrClosed
+	}
+}
+
+// Subscribe subscribes a handler to the events published as type T,
+// returning a function which unsubscribes it. A handler may be called with
+// an event which was being dispatched as it was unsubscribed.
+func Subscribe[T any](b *Bus, fn func(ctx context.Context, e T)) (unsubscribe func()) {
+	h := &handler{fn: func(ctx context.Context, value interface{}) {
+		fn(ctx, value.(T))
+	}}
+
+	typ := typeOf[T]()
+	b.mu.Lock()
+	b.handlers[typ] = append(b.handlers[typ], h)
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.unsubscribe(typ, h) })
+	}
+}
+
+// Close stops the Bus from accepting further events; events already queued
+// are still dispatched.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.closing)
+	}
+}
+
+func (b *Bus) unsubscribe(typ reflect.Type, h *handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	handlers := b.handlers[typ]
+	for i, existing := range handlers {
+		if existing == h {
+			b.handlers[typ] = append(handlers[:i:i], handlers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *Bus) subscribers(typ reflect.Type) []*handler {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.handlers[typ]
+}
+
+func (b *Bus) dispatch(ctx context.Context, done chan struct{}) {
+	defer supervisor.Recover(ctx, done)
+
+	supervisor.ObserveQueue(ctx, b.depth)
+	supervisor.Ready(ctx)
+	for {
+		if b.pending == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-b.events:
+				b.pending = &delivery{event: e, handlers: b.subscribers(e.typ)}
+			}
+		}
+
+		b.deliver(ctx)
+		b.pending = nil
+	}
+}
+
+// deliver calls each remaining handler of the pending event. The progress
+// is recorded before each handler is called, so should one panic then the
+// restarted dispatch loop resumes with the next.
+func (b *Bus) deliver(ctx context.Context) {
+	d := b.pending
+	for d.next < len(d.handlers) {
+		h := d.handlers[d.next]
+		d.next++
+		h.fn(ctx, d.event.value)
+	}
+}
+
+func (b *Bus) depth() (int, int) {
+	return len(b.events), cap(b.events)
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,74 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func Test_BusMustDeliverEventsDespitePanickingHandlers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	b, err := New(s, Name("events"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu       sync.Mutex
+		received []int
+		strings  int
+	)
+
+	Subscribe(b, func(ctx context.Context, e int) {
+		if e == 2 {
+			panic("faulty handler")
+		}
+	})
+	Subscribe(b, func(ctx context.Context, e int) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, e)
+	})
+	unsubscribe := Subscribe(b, func(ctx context.Context, e string) {
+		mu.Lock()
+		defer mu.Unlock()
+		strings++
+	})
+
+	for i := 1; i <= 3; i++ {
+		Publish(b, i)
+	}
+	unsubscribe()
+	Publish(b, "ignored")
+	<-time.After(time.Millisecond * 50)
+
+	mu.Lock()
+	if len(received) != 3 || received[1] != 2 || strings != 0 {
+		t.Error("expected events to flow past a panicking handler", received, strings)
+	}
+	mu.Unlock()
+
+	if info := s.WorkerInfo("events"); len(info) != 1 || info[0].Restarts != 1 {
+		t.Error("expected the dispatch loop to be restarted by the supervisor", info)
+	}
+
+	b.Close()
+	if err := Publish(b, 4); !errors.Is(err, ErrClosed) {
+		t.Error("expected a closed bus to reject events", err)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,337 @@
+package supervisor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of time for a Supervisor, and for the workers and
+// helpers run under it - such as restart delays, Retry's backoff, and the
+// runs of a Schedule. It allows tests to control the passage of time via a
+// FakeClock, rather than waiting on it.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer returns a Timer which fires once the duration has elapsed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event created by a Clock, as with time.Timer.
+type Timer interface {
+	// C returns the channel upon which the time is delivered once the Timer
+	// fires.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, returning false should it have
+	// already fired or been stopped.
+	Stop() bool
+	// Reset changes the Timer to fire once the duration has elapsed,
+	// discarding any time yet to be received from C; it returns false
+	// should the Timer have already fired or been stopped. It allows a loop
+	// to reuse a single Timer, rather than allocating one per iteration.
+	Reset(d time.Duration) bool
+}
+
+// RealClock is the Clock backed by the time package; it's used unless
+// another is given.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+func (t realTimer) Reset(d time.Duration) bool {
+	active := t.Timer.Stop()
+	if !active {
+		select {
+		case <-t.Timer.C:
+		default:
+		}
+	}
+
+	t.Timer.Reset(d)
+	return active
+}
+
+// rearm resets the Timer to fire once the duration has elapsed, or creates
+// one from the Clock should it be nil; it allows loops which wait upon a
+// Timer to reuse it across iterations.
+func rearm(t Timer, clock Clock, d time.Duration) Timer {
+	if t == nil {
+		return clock.NewTimer(d)
+	}
+
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc calls fn in its own goroutine once the duration has elapsed on
+// the Clock, as with time.AfterFunc. The returned function cancels the
+// call, returning false should fn have already been called, or the call
+// already cancelled.
+func AfterFunc(clock Clock, d time.Duration, fn func()) func() bool {
+	if _, ok := clock.(realClock); ok {
+		return time.AfterFunc(d, fn).Stop
+	}
+
+	const (
+		pending = iota
+		fired
+		cancelled
+	)
+
+	var state int32
+	timer, stop := clock.NewTimer(d), make(chan struct{})
+	go func() {
+		select {
+		case <-timer.C():
+			if atomic.CompareAndSwapInt32(&state, pending, fired) {
+				fn()
+			}
+		case <-stop:
+		}
+	}()
+
+	return func() bool {
+		if !atomic.CompareAndSwapInt32(&state, pending, cancelled) {
+			return false
+		}
+
+		timer.Stop()
+		close(stop)
+		return true
+	}
+}
+
+// WithTimeout returns a copy of the context which is cancelled once the
+// duration has elapsed upon the context's Clock - see ClockFrom - as with
+// context.WithTimeout. Its Err is context.DeadlineExceeded should it be
+// cancelled by the timeout.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	clock := ClockFrom(ctx)
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeout(ctx, d)
+	}
+
+	inner, cancel := context.WithCancel(ctx)
+	timeout := &timeoutCtx{Context: inner, deadline: clock.Now().Add(d)}
+	stop := AfterFunc(clock, d, func() {
+		if inner.Err() == nil {
+			atomic.StoreInt32(&timeout.expired, 1)
+			cancel()
+		}
+	})
+
+	return timeout, func() {
+		stop()
+		cancel()
+	}
+}
+
+// timeoutCtx is a context cancelled by a timeout upon a Clock other than
+// RealClock.
+type timeoutCtx struct {
+	context.Context
+	deadline time.Time
+	expired  int32
+}
+
+func (c *timeoutCtx) Deadline() (time.Time, bool) {
+	if deadline, ok := c.Context.Deadline(); ok && deadline.Before(c.deadline) {
+		return deadline, true
+	}
+
+	return c.deadline, true
+}
+
+func (c *timeoutCtx) Err() error {
+	err := c.Context.Err()
+	if err == context.Canceled && atomic.LoadInt32(&c.expired) == 1 {
+		return context.DeadlineExceeded
+	}
+
+	return err
+}
+
+type clockKey struct{}
+
+// withClock returns a context carrying the Clock, should it not be nil.
+func withClock(ctx context.Context, clock Clock) context.Context {
+	if clock == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// ClockFrom returns the Clock of the Supervisor running the worker, given
+// the worker's context; it returns RealClock should the context not belong
+// to a worker, or its Supervisor not have been given a Clock. Workers which
+// measure time should use it, so they can be tested with a FakeClock.
+func ClockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+
+	return RealClock
+}
+
+// FakeClock is a Clock whose time only passes upon Advance, allowing the
+// time-dependent behaviour of workers to be tested deterministically.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{}
+}
+
+// NewFakeClock returns a FakeClock whose current time is now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the FakeClock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer returns a Timer which fires once the FakeClock has been advanced
+// by the duration; should it not be positive then the Timer has already
+// fired.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+
+	c.timers = append(c.timers, t)
+	c.notifyLocked()
+	return t
+}
+
+// Advance moves the FakeClock's time forward by the duration, firing every
+// Timer which falls due in the order they do so.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].at.Before(c.timers[j].at)
+	})
+
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+
+		t.c <- t.at
+	}
+
+	c.timers = pending
+	c.notifyLocked()
+}
+
+// Timers returns the number of Timers which are yet to fire.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// BlockUntil blocks until at least n Timers are yet to fire, or the context
+// is cancelled; it allows a test to wait for a worker to begin waiting upon
+// the FakeClock before advancing it.
+func (c *FakeClock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.mu.Lock()
+		count, changed := len(c.timers), c.changed
+		c.mu.Unlock()
+
+		if count >= n {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// notifyLocked wakes any callers of BlockUntil.
+func (c *FakeClock) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.stopLocked()
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.stopLocked()
+	select {
+	case <-t.c:
+	default:
+	}
+
+	t.at = t.clock.now.Add(d)
+	if d <= 0 {
+		t.c <- t.clock.now
+		return active
+	}
+
+	t.clock.timers = append(t.clock.timers, t)
+	t.clock.notifyLocked()
+	return active
+}
+
+func (t *fakeTimer) stopLocked() bool {
+	for i, pending := range t.clock.timers {
+		if pending == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			t.clock.notifyLocked()
+			return true
+		}
+	}
+
+	return false
+}
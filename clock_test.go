@@ -0,0 +1,255 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_FakeClockMustFireTimersInOrder(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	first, second := clock.NewTimer(time.Minute), clock.NewTimer(time.Second)
+	stopped := clock.NewTimer(time.Second)
+
+	if !stopped.Stop() || clock.Timers() != 2 {
+		t.Error("expected the stopped timer to be removed", clock.Timers())
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case at := <-second.C():
+		if !at.Equal(time.Unix(1, 0)) {
+			t.Error("unexpected time", at)
+		}
+	default:
+		t.Error("expected the due timer to fire")
+	}
+
+	select {
+	case <-first.C():
+		t.Error("expected the later timer not to fire")
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok := <-first.C(); !ok || first.Stop() {
+		t.Error("expected the later timer to have fired")
+	}
+}
+
+func Test_TimerMustBeReusableViaReset(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	timer := clock.NewTimer(time.Second)
+
+	clock.Advance(time.Second)
+	if timer.Reset(time.Minute) {
+		t.Error("expected the fired timer to be inactive")
+	}
+
+	select {
+	case <-timer.C():
+		t.Fatal("expected the undelivered time to be discarded upon reset")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	if !timer.Reset(time.Second) || clock.Timers() != 1 {
+		t.Error("expected the pending timer to be rearmed in place", clock.Timers())
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case <-timer.C():
+	default:
+		t.Error("expected the rearmed timer to fire")
+	}
+
+	wall := RealClock.NewTimer(time.Hour)
+	if !wall.Reset(time.Millisecond) {
+		t.Error("expected the pending real timer to be active")
+	}
+	<-wall.C()
+}
+
+func Test_SupervisorMustUseClockForSchedules(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	var runs int32
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{
+			Name: "periodic",
+			Worker: Periodic(time.Hour, func(ctx context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			}),
+		}},
+		Clock: clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 1; i <= 3; i++ {
+		if err := clock.BlockUntil(ctx, 1); err != nil {
+			t.Fatal("expected the worker to wait upon the clock", err)
+		}
+
+		clock.Advance(time.Hour)
+		for atomic.LoadInt32(&runs) != int32(i) && ctx.Err() == nil {
+			<-time.After(time.Millisecond)
+		}
+	}
+
+	if n := atomic.LoadInt32(&runs); n != 3 {
+		t.Error("expected a run for each hour the clock advanced", n)
+	}
+
+	if infos := s.WorkerInfo("periodic"); len(infos) != 1 || infos[0].Uptime != 3*time.Hour {
+		t.Error("expected uptime to be measured by the clock", infos)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_RetryMustBackOffUponTheClock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx := withClock(context.Background(), clock)
+
+	result := make(chan error, 1)
+	attempts := 0
+	go func() {
+		result <- Retry(ctx, func(context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errTest
+			}
+			return nil
+		}, RetryPolicy{Attempts: 3, Backoff: Backoff{Initial: time.Minute, Multiplier: 2}})
+	}()
+
+	wait, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clock.BlockUntil(wait, 1)
+	clock.Advance(time.Minute)
+	clock.BlockUntil(wait, 1)
+	clock.Advance(2 * time.Minute)
+
+	if err := <-result; err != nil || attempts != 3 {
+		t.Error("expected the retries to follow the clock", attempts, err)
+	}
+}
+
+func Test_AfterFuncMustFireUponTheClock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	fired := make(chan struct{}, 2)
+	AfterFunc(clock, time.Minute, func() { fired <- struct{}{} })
+	cancel := AfterFunc(clock, time.Minute, func() { fired <- struct{}{} })
+
+	if !cancel() || cancel() {
+		t.Error("expected the call to only be cancelled once")
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case <-fired:
+		t.Fatal("expected the call to wait for the duration to elapse")
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	clock.Advance(time.Minute)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("expected the call once the duration elapsed")
+	}
+
+	select {
+	case <-fired:
+		t.Error("expected the cancelled call to not be made")
+	case <-time.After(time.Millisecond * 20):
+	}
+}
+
+func Test_WithTimeoutMustExpireUponTheClock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx, cancel := WithTimeout(withClock(context.Background(), clock), time.Minute)
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); !ok || !deadline.Equal(time.Unix(60, 0)) {
+		t.Error("expected the deadline to be upon the clock", deadline)
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case <-ctx.Done():
+		t.Fatal("expected the context to wait for the duration to elapse")
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	clock.Advance(time.Minute)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the context to be cancelled once the duration elapsed")
+	}
+
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Error("expected the deadline to have been exceeded", ctx.Err())
+	}
+
+	cancelled, stop := WithTimeout(withClock(context.Background(), clock), time.Minute)
+	stop()
+	if cancelled.Err() != context.Canceled {
+		t.Error("expected a cancelled context to not report its deadline", cancelled.Err())
+	}
+}
+
+// The benchmarks below compare allocating a Timer for each wait, as the
+// restart delays, Retry and the job runner previously did, with rearming a
+// single Timer via Reset.
+
+func BenchmarkTimerPerIteration(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		timer := RealClock.NewTimer(time.Nanosecond)
+		<-timer.C()
+	}
+}
+
+func BenchmarkTimerRearmed(b *testing.B) {
+	b.ReportAllocs()
+
+	var timer Timer
+	for i := 0; i < b.N; i++ {
+		timer = rearm(timer, RealClock, time.Nanosecond)
+		<-timer.C()
+	}
+}
+
+func BenchmarkRetryBackoff(b *testing.B) {
+	b.ReportAllocs()
+
+	const attempts = 16
+	errFail := errors.New("fail")
+	policy := RetryPolicy{Attempts: attempts, Backoff: Backoff{Initial: time.Nanosecond, Max: time.Nanosecond}}
+	for i := 0; i < b.N; i++ {
+		Retry(context.Background(), func(context.Context) error { return errFail }, policy)
+	}
+}
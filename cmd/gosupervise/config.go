@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	supervisor "go.fergus.london/go-supervise"
+	"gopkg.in/yaml.v3"
+)
+
+// config describes the processes to supervise, and how eagerly they should
+// be restarted; it's loaded from YAML or JSON via -config.
+//
+//	shutdown_timeout: 10s
+//	policy:
+//	  max_restarts: 5
+//	  period: 1m
+//	  backoff: {initial: 1s, max: 30s, multiplier: 2}
+//	processes:
+//	  - name: web
+//	    command: ["/usr/local/bin/web", "-port", "8080"]
+//	    log: /var/log/web.log
+//	    grace_period: 5s
+//	    dir: /srv/web
+//	    env: ["PORT=8080"]
+//	    umask: "027"
+//	    user: www-data
+//	    rlimits: {nofile: 4096}
+type config struct {
+	// ShutdownTimeout bounds the time processes are given to exit.
+	ShutdownTimeout supervisor.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	// Policy is applied to each process individually.
+	Policy supervisor.PolicyConfig `json:"policy" yaml:"policy"`
+	// Processes are the commands to supervise.
+	Processes []processConfig `json:"processes" yaml:"processes"`
+}
+
+// processConfig describes a single supervised command.
+type processConfig struct {
+	// Name identifies the process; it defaults to the command's basename.
+	Name string `json:"name" yaml:"name"`
+	// Command is the executable, followed by its arguments.
+	Command []string `json:"command" yaml:"command"`
+	// Log is a file to append the process' stdout and stderr to; by
+	// default they're logged by gosupervise, prefixed with Name.
+	Log string `json:"log" yaml:"log"`
+	// GracePeriod is the time the process is given to exit after SIGTERM,
+	// before being killed.
+	GracePeriod supervisor.Duration `json:"grace_period" yaml:"grace_period"`
+	// PTY runs the process under a pseudo-terminal.
+	PTY bool `json:"pty" yaml:"pty"`
+	// Env holds additional environment variables, in the form "KEY=value".
+	Env []string `json:"env" yaml:"env"`
+	// Dir is the working directory of the process.
+	Dir string `json:"dir" yaml:"dir"`
+	// Umask is the octal file mode creation mask of the process.
+	Umask string `json:"umask" yaml:"umask"`
+	// User and Group are the credentials to run the process with.
+	User  string `json:"user" yaml:"user"`
+	Group string `json:"group" yaml:"group"`
+	// Rlimits maps resource names - such as "nofile" - to the limit applied
+	// to the process, as both its soft and hard limit.
+	Rlimits map[string]uint64 `json:"rlimits" yaml:"rlimits"`
+}
+
+var errNoProcesses = errors.New("gosupervise: no processes to supervise")
+
+func loadConfig(r io.Reader) (*config, error) {
+	cfg := &config{}
+	if err := yaml.NewDecoder(r).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("gosupervise: unable to decode config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func loadConfigFile(path string) (*config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return loadConfig(f)
+}
+
+// validate fills in default process names, and rejects processes without
+// a command.
+func (cfg *config) validate() error {
+	if len(cfg.Processes) == 0 {
+		return errNoProcesses
+	}
+
+	for i, p := range cfg.Processes {
+		if len(p.Command) == 0 {
+			return fmt.Errorf("gosupervise: process %d has no command", i)
+		}
+
+		if p.Name == "" {
+			cfg.Processes[i].Name = filepath.Base(p.Command[0])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+/*
+Command gosupervise supervises one or more external processes, restarting
+them whenever they exit - in the spirit of daemontools' supervise, built
+upon the restart engine of go-supervise.
+
+A single process can be supervised by giving its command after any flags:
+
+	$ gosupervise -max-restarts 5 -period 1m -log /var/log/web.log -- /usr/local/bin/web -port 8080
+
+Alternatively, several processes may be described in a YAML or JSON file;
+see the config type for its format.
+
+	$ gosupervise -config /etc/gosupervise.yml
+
+Each process has its own restart budget. A process which exits - whether
+successfully or not - is restarted after a backoff, until it exceeds its
+budget; at which point it's left stopped. gosupervise exits once every
+process has stopped, or upon receipt of SIGINT or SIGTERM, at which point
+its processes are sent SIGTERM - and killed should they not exit within
+their grace period.
+
+Output of each process is logged alongside that of gosupervise, with each
+line prefixed by the name of the process, unless a log file is given for
+it. Log files are reopened upon receipt of SIGHUP, so may be rotated by
+tools such as logrotate.
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.fergus.london/go-supervise/procsupervisor"
+)
+
+// errGaveUp is returned once every process has exceeded its restart budget.
+var errGaveUp = errors.New("gosupervise: every process has exceeded its restart budget")
+
+func main() {
+	supervisor.WithLogger(stdLogger{})
+
+	cfg, err := parseArgs(os.Args[1:], flag.CommandLine)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := supervise(context.Background(), cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseArgs builds the config from the command line, loading it from a
+// file should -config be given.
+func parseArgs(args []string, fs *flag.FlagSet) (*config, error) {
+	var (
+		configPath = fs.String("config", "", "load processes from a YAML or JSON `file`")
+		name       = fs.String("name", "", "name of the process; defaults to the command's basename")
+		logPath    = fs.String("log", "", "append the process' output to `file`")
+		maxRestart = fs.Int("max-restarts", 0, "restarts permitted within -period; zero is unlimited")
+		period     = fs.Duration("period", 0, "window in which -max-restarts is counted")
+		backoff    = fs.Duration("backoff", time.Second, "initial delay before restarting")
+		backoffMax = fs.Duration("backoff-max", time.Minute, "maximum delay before restarting")
+		multiplier = fs.Float64("backoff-multiplier", 2, "growth of the delay between consecutive restarts")
+		grace      = fs.Duration("grace-period", procsupervisor.DefaultGracePeriod, "time the process is given to exit after SIGTERM")
+		usePTY     = fs.Bool("pty", false, "run the process under a pseudo-terminal (Linux only)")
+		timeout    = fs.Duration("shutdown-timeout", time.Minute, "time processes are given to exit")
+		dir        = fs.String("dir", "", "working `directory` of the process")
+		umask      = fs.String("umask", "", "octal file mode creation `mask` of the process")
+		username   = fs.String("user", "", "`user` to run the process as")
+		group      = fs.String("group", "", "`group` to run the process as")
+		env        = stringList{}
+		rlimits    = rlimitList{}
+	)
+	fs.Var(&env, "env", "set an environment variable as `KEY=value`; may be repeated")
+	fs.Var(&rlimits, "rlimit", "set a resource limit as `name=value`, such as nofile=4096; may be repeated")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configPath != "" {
+		if fs.NArg() > 0 {
+			return nil, errors.New("gosupervise: a command can't be given alongside -config")
+		}
+
+		cfg, err := loadConfigFile(*configPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return cfg, cfg.validate()
+	}
+
+	cfg := &config{
+		ShutdownTimeout: supervisor.Duration(*timeout),
+		Policy: supervisor.PolicyConfig{
+			MaxRestarts: *maxRestart,
+			Period:      supervisor.Duration(*period),
+			Backoff: supervisor.BackoffConfig{
+				Initial:    supervisor.Duration(*backoff),
+				Max:        supervisor.Duration(*backoffMax),
+				Multiplier: *multiplier,
+			},
+		},
+	}
+
+	if fs.NArg() > 0 {
+		cfg.Processes = []processConfig{{
+			Name:        *name,
+			Command:     fs.Args(),
+			Log:         *logPath,
+			GracePeriod: supervisor.Duration(*grace),
+			PTY:         *usePTY,
+			Env:         env,
+			Dir:         *dir,
+			Umask:       *umask,
+			User:        *username,
+			Group:       *group,
+			Rlimits:     rlimits,
+		}}
+	}
+
+	return cfg, cfg.validate()
+}
+
+// supervise runs the processes until they've all stopped, or a signal is
+// received.
+func supervise(ctx context.Context, cfg *config) error {
+	procs := make([]*procsupervisor.Process, len(cfg.Processes))
+	groups := make([]supervisor.Group, len(cfg.Processes))
+	logs := []*procsupervisor.LogFile{}
+	for i, p := range cfg.Processes {
+		var out io.Writer = procsupervisor.LoggerWriter(p.Name, stdLogger{})
+		if p.Log != "" {
+			f, err := procsupervisor.OpenLogFile(p.Log)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			logs = append(logs, f)
+			out = f
+		}
+
+		proc, err := newProcess(p, out)
+		if err != nil {
+			return err
+		}
+
+		procs[i] = proc
+		groups[i] = supervisor.Group{
+			Name:    p.Name,
+			Workers: []supervisor.WorkerSpec{procs[i].WorkerSpec()},
+			Policy:  cfg.Policy.RestartPolicy(),
+		}
+	}
+
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Context:         ctx,
+		Groups:          groups,
+		ShutdownTimeout: time.Duration(cfg.ShutdownTimeout),
+		Signals: map[os.Signal]supervisor.SignalAction{
+			syscall.SIGINT:  supervisor.ShutdownAction(),
+			syscall.SIGTERM: supervisor.ShutdownAction(),
+			syscall.SIGHUP:  reopenLogs(logs),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	quit := make(chan struct{})
+	logging := logEvents(procs, quit)
+	defer func() {
+		close(quit)
+		logging.Wait()
+	}()
+
+	s.Run()
+	s.Wait()
+	s.Stop()
+
+	var sig *supervisor.SignalError
+	if errors.As(s.Cause(), &sig) || ctx.Err() != nil {
+		return nil
+	}
+
+	return errGaveUp
+}
+
+// reopenLogs reopens each of the log files, allowing them to be rotated by
+// tools such as logrotate.
+func reopenLogs(logs []*procsupervisor.LogFile) supervisor.SignalAction {
+	return func(s *supervisor.Supervisor, sig os.Signal) {
+		for _, l := range logs {
+			if err := l.Reopen(); err != nil {
+				log.Println("unable to reopen log:", err)
+			}
+		}
+	}
+}
+
+// stringList is a flag which may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// rlimitList is a repeatable flag of resource limits, given as name=value.
+type rlimitList map[string]uint64
+
+func (l rlimitList) String() string {
+	limits := make([]string, 0, len(l))
+	for name, limit := range l {
+		limits = append(limits, fmt.Sprintf("%s=%d", name, limit))
+	}
+
+	return strings.Join(limits, ",")
+}
+
+func (l rlimitList) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+
+	limit, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	l[parts[0]] = limit
+	return nil
+}
+
+// stdLogger adapts the standard library's logger to supervisor.Logger.
+type stdLogger struct{}
+
+func (stdLogger) Println(msg string) {
+	log.Println(msg)
+}
@@ -0,0 +1,118 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_ArgsMustDescribeSingleProcess(t *testing.T) {
+	fs := flag.NewFlagSet("gosupervise", flag.ContinueOnError)
+	cfg, err := parseArgs([]string{"-max-restarts", "3", "-period", "1m", "--", "/bin/echo", "hello"}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Processes) != 1 || cfg.Processes[0].Name != "echo" {
+		t.Fatal("expected a single process named after its command", cfg.Processes)
+	}
+
+	if cfg.Policy.MaxRestarts != 3 || time.Duration(cfg.Policy.Period) != time.Minute {
+		t.Error("expected the restart policy to be taken from flags", cfg.Policy)
+	}
+}
+
+func Test_ArgsMustDescribeProcessEnvironment(t *testing.T) {
+	fs := flag.NewFlagSet("gosupervise", flag.ContinueOnError)
+	cfg, err := parseArgs([]string{
+		"-env", "A=1", "-env", "B=2", "-dir", "/tmp", "-umask", "027",
+		"-rlimit", "nofile=1024", "--", "/bin/true",
+	}, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := cfg.Processes[0]
+	if len(p.Env) != 2 || p.Dir != "/tmp" || p.Umask != "027" || p.Rlimits["nofile"] != 1024 {
+		t.Error("expected the environment to be taken from flags", p)
+	}
+
+	// Resource limits are only supported on Linux.
+	p.Rlimits = nil
+	if _, err := newProcess(p, nil); err != nil {
+		t.Error("expected the process to be valid", err)
+	}
+
+	p.Umask = "999"
+	if _, err := newProcess(p, nil); err == nil {
+		t.Error("expected an invalid umask to be rejected")
+	}
+}
+
+func Test_ArgsMustRequireProcesses(t *testing.T) {
+	fs := flag.NewFlagSet("gosupervise", flag.ContinueOnError)
+	if _, err := parseArgs([]string{}, fs); err != errNoProcesses {
+		t.Error("expected an error when there's nothing to supervise", err)
+	}
+}
+
+func Test_ConfigMustDescribeMultipleProcesses(t *testing.T) {
+	cfg, err := loadConfig(strings.NewReader(`
+processes:
+  - name: web
+    command: ["/bin/sleep", "10"]
+  - command: ["/bin/true"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.Processes) != 2 || cfg.Processes[0].Name != "web" || cfg.Processes[1].Name != "true" {
+		t.Error("unexpected processes", cfg.Processes)
+	}
+}
+
+func Test_SuperviseMustGiveUpOnceBudgetExhausted(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	dir, err := ioutil.TempDir("", "gosupervise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := filepath.Join(dir, "out.log")
+	cfg := &config{Processes: []processConfig{{
+		Name:    "failing",
+		Command: []string{"/bin/sh", "-c", "echo ran; exit 1"},
+		Log:     logPath,
+	}}}
+	cfg.Policy.MaxRestarts = 2
+
+	if err := supervise(context.Background(), cfg); err != errGaveUp {
+		t.Fatal("expected supervision to end once the budget was exhausted", err)
+	}
+
+	out, err := ioutil.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runs := strings.Count(string(out), "ran"); runs != 3 {
+		t.Error("expected the process to run until its budget was exhausted", runs)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.fergus.london/go-supervise/procsupervisor"
+)
+
+// newProcess returns the Process described by a processConfig, with its
+// output written to out.
+func newProcess(p processConfig, out io.Writer) (*procsupervisor.Process, error) {
+	spec := procsupervisor.Spec{
+		Name:        p.Name,
+		Command:     p.Command[0],
+		Args:        p.Command[1:],
+		GracePeriod: time.Duration(p.GracePeriod),
+		Stdout:      out,
+		Stderr:      out,
+		PTY:         p.PTY,
+		Env:         p.Env,
+		Dir:         p.Dir,
+		User:        p.User,
+		Group:       p.Group,
+	}
+
+	if p.Umask != "" {
+		mask, err := strconv.ParseUint(p.Umask, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("gosupervise: invalid umask %q for %s", p.Umask, p.Name)
+		}
+
+		umask := os.FileMode(mask)
+		spec.Umask = &umask
+	}
+
+	for name, limit := range p.Rlimits {
+		resource, ok := procsupervisor.RlimitResource(name)
+		if !ok {
+			return nil, fmt.Errorf("gosupervise: unknown resource limit %q for %s", name, p.Name)
+		}
+
+		spec.Rlimits = append(spec.Rlimits, procsupervisor.Rlimit{Resource: resource, Soft: limit, Hard: limit})
+	}
+
+	return procsupervisor.New(spec), nil
+}
+
+// logEvents logs the events of each process until quit is closed; the
+// returned WaitGroup is done once logging has stopped.
+func logEvents(procs []*procsupervisor.Process, quit chan struct{}) *sync.WaitGroup {
+	wg := &sync.WaitGroup{}
+	for _, p := range procs {
+		wg.Add(1)
+		go func(events <-chan procsupervisor.Event) {
+			defer wg.Done()
+
+			for {
+				select {
+				case e := <-events:
+					log.Println(describe(e))
+				case <-quit:
+					return
+				}
+			}
+		}(p.Events())
+	}
+
+	return wg
+}
+
+func describe(e procsupervisor.Event) string {
+	switch {
+	case e.Type == procsupervisor.Started:
+		return fmt.Sprintf("%s started with pid %d", e.Name, e.PID)
+	case e.PID == 0:
+		return fmt.Sprintf("%s failed to start: %s", e.Name, e.Err)
+	default:
+		return fmt.Sprintf("%s (pid %d) exited with code %d", e.Name, e.PID, e.ExitCode)
+	}
+}
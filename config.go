@@ -0,0 +1,292 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownFactory is returned when a Config refers to a worker factory
+// which hasn't been registered.
+var ErrUnknownFactory = errors.New("supervisor: unknown worker factory")
+
+// Factory constructs a Supervisable from its configuration; factories are
+// registered by name via RegisterFactory, and referenced by Configs.
+type Factory func(WorkerConfig) (Supervisable, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// RegisterFactory makes a Factory available to Configs under the given
+// name; registering a second Factory with the same name replaces the first.
+func RegisterFactory(name string, f Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	factories[name] = f
+}
+
+func lookupFactory(name string) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	f, ok := factories[name]
+	return f, ok
+}
+
+// Config is a declarative description of a Supervisor, allowing worker
+// counts and restart policies to be tuned without recompiling. It may be
+// loaded from YAML or JSON via LoadConfig.
+type Config struct {
+	// ShutdownTimeout bounds any graceful Shutdown initiated by the
+	// Supervisor itself.
+	ShutdownTimeout Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	// Policy is the RestartPolicy applied to Workers.
+	Policy PolicyConfig `json:"policy" yaml:"policy"`
+	// Workers are the workers in the default group.
+	Workers []WorkerConfig `json:"workers" yaml:"workers"`
+	// Groups are additional named groups of workers.
+	Groups []GroupConfig `json:"groups" yaml:"groups"`
+}
+
+// GroupConfig is the declarative form of a Group.
+type GroupConfig struct {
+	Name    string         `json:"name" yaml:"name"`
+	Policy  PolicyConfig   `json:"policy" yaml:"policy"`
+	Workers []WorkerConfig `json:"workers" yaml:"workers"`
+}
+
+// PolicyConfig is the declarative form of a RestartPolicy.
+type PolicyConfig struct {
+	Strategy    Strategy      `json:"strategy" yaml:"strategy"`
+	MaxRestarts int           `json:"max_restarts" yaml:"max_restarts"`
+	Period      Duration      `json:"period" yaml:"period"`
+	Backoff     BackoffConfig `json:"backoff" yaml:"backoff"`
+	Escalation  Escalation    `json:"escalation" yaml:"escalation"`
+}
+
+// BackoffConfig is the declarative form of a Backoff.
+type BackoffConfig struct {
+	Initial    Duration `json:"initial" yaml:"initial"`
+	Max        Duration `json:"max" yaml:"max"`
+	Multiplier float64  `json:"multiplier" yaml:"multiplier"`
+}
+
+// WorkerConfig is the declarative form of a WorkerSpec. The worker itself
+// is either built by a registered Factory, or - where Supervisor is given -
+// is a nested Supervisor.
+type WorkerConfig struct {
+	// Name identifies the worker.
+	Name string `json:"name" yaml:"name"`
+	// Factory is the name of the registered Factory; it defaults to Name.
+	Factory string `json:"factory" yaml:"factory"`
+	// Count is the number of instances to run.
+	Count int `json:"count" yaml:"count"`
+	// Significant marks the worker as significant; see WorkerSpec.
+	Significant bool `json:"significant" yaml:"significant"`
+	// ShutdownClass orders the worker's shutdown; see WorkerSpec.
+	ShutdownClass int `json:"shutdown_class" yaml:"shutdown_class"`
+	// Args are arbitrary parameters made available to the Factory.
+	Args map[string]string `json:"args" yaml:"args"`
+	// Supervisor describes a nested Supervisor to run as this worker.
+	Supervisor *Config `json:"supervisor" yaml:"supervisor"`
+}
+
+// LoadConfig reads a Config from YAML; as YAML is a superset of JSON, a
+// JSON document may also be given.
+func LoadConfig(r io.Reader) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.NewDecoder(r).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("supervisor: unable to decode config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// NewSupervisorFromConfig builds a Supervisor - and any nested Supervisors -
+// from the given Config.
+func NewSupervisorFromConfig(ctx context.Context, cfg *Config) (*Supervisor, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	opts, err := cfg.options(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := NewSupervisorWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.config = cfg
+	return s, nil
+}
+
+func (cfg *Config) options(ctx context.Context) (*Options, error) {
+	specs, err := workerSpecs(ctx, cfg.Workers)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]Group, len(cfg.Groups))
+	for i, g := range cfg.Groups {
+		workers, err := workerSpecs(ctx, g.Workers)
+		if err != nil {
+			return nil, err
+		}
+
+		groups[i] = Group{Name: g.Name, Workers: workers, Policy: g.Policy.RestartPolicy()}
+	}
+
+	return &Options{
+		Context:         ctx,
+		Specs:           specs,
+		Policy:          cfg.Policy.RestartPolicy(),
+		Groups:          groups,
+		ShutdownTimeout: time.Duration(cfg.ShutdownTimeout),
+	}, nil
+}
+
+func workerSpecs(ctx context.Context, workers []WorkerConfig) ([]WorkerSpec, error) {
+	specs := make([]WorkerSpec, len(workers))
+	for i, wc := range workers {
+		spec, err := wc.spec(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		specs[i] = spec
+	}
+
+	return specs, nil
+}
+
+func (wc WorkerConfig) spec(ctx context.Context) (WorkerSpec, error) {
+	spec := WorkerSpec{
+		Name:          wc.Name,
+		Count:         wc.Count,
+		Significant:   wc.Significant,
+		ShutdownClass: wc.ShutdownClass,
+	}
+
+	if wc.Supervisor != nil {
+		child, err := NewSupervisorFromConfig(ctx, wc.Supervisor)
+		if err != nil {
+			return spec, err
+		}
+
+		spec.Child = child
+		return spec, nil
+	}
+
+	name := wc.Factory
+	if name == "" {
+		name = wc.Name
+	}
+
+	factory, ok := lookupFactory(name)
+	if !ok {
+		return spec, fmt.Errorf("%w: %q", ErrUnknownFactory, name)
+	}
+
+	worker, err := factory(wc)
+	spec.Worker = worker
+	return spec, err
+}
+
+// RestartPolicy converts the PolicyConfig to the RestartPolicy it describes.
+func (pc PolicyConfig) RestartPolicy() RestartPolicy {
+	return RestartPolicy{
+		MaxRestarts: pc.MaxRestarts,
+		Period:      time.Duration(pc.Period),
+		Backoff: Backoff{
+			Initial:    time.Duration(pc.Backoff.Initial),
+			Max:        time.Duration(pc.Backoff.Max),
+			Multiplier: pc.Backoff.Multiplier,
+		},
+		Escalation: pc.Escalation,
+		Strategy:   pc.Strategy,
+	}
+}
+
+// Duration is a time.Duration which is represented in configuration as a
+// string, such as "1m30s".
+type Duration time.Duration
+
+// UnmarshalText satisfies encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText satisfies encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+var strategyNames = map[Strategy]string{
+	OneForOne:  "one_for_one",
+	OneForAll:  "one_for_all",
+	RestForOne: "rest_for_one",
+}
+
+func (s Strategy) String() string {
+	return strategyNames[s]
+}
+
+// UnmarshalText satisfies encoding.TextUnmarshaler.
+func (s *Strategy) UnmarshalText(text []byte) error {
+	for strategy, name := range strategyNames {
+		if name == string(text) {
+			*s = strategy
+			return nil
+		}
+	}
+
+	return fmt.Errorf("supervisor: unknown strategy %q", text)
+}
+
+// MarshalText satisfies encoding.TextMarshaler.
+func (s Strategy) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+var escalationNames = map[Escalation]string{
+	StopGroup:      "stop_group",
+	StopSupervisor: "stop_supervisor",
+}
+
+func (e Escalation) String() string {
+	return escalationNames[e]
+}
+
+// UnmarshalText satisfies encoding.TextUnmarshaler.
+func (e *Escalation) UnmarshalText(text []byte) error {
+	for escalation, name := range escalationNames {
+		if name == string(text) {
+			*e = escalation
+			return nil
+		}
+	}
+
+	return fmt.Errorf("supervisor: unknown escalation %q", text)
+}
+
+// MarshalText satisfies encoding.TextMarshaler.
+func (e Escalation) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
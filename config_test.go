@@ -0,0 +1,305 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+const testConfig = `
+shutdown_timeout: 5s
+workers:
+  - name: counter
+    count: 2
+    args:
+      label: primary
+  - name: nested
+    supervisor:
+      workers:
+        - name: inner
+          factory: counter
+groups:
+  - name: background
+    policy:
+      strategy: one_for_all
+      max_restarts: 5
+      period: 1m
+      backoff:
+        initial: 100ms
+        multiplier: 2
+      escalation: stop_supervisor
+    workers:
+      - name: flusher
+        factory: counter
+        shutdown_class: 1
+`
+
+func Test_ConfigMustBuildSupervisionTree(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	starts := int32(0)
+	labels := []string{}
+	RegisterFactory("counter", func(wc WorkerConfig) (Supervisable, error) {
+		labels = append(labels, wc.Args["label"])
+		return func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			atomic.AddInt32(&starts, 1)
+			<-ctx.Done()
+		}, nil
+	})
+
+	cfg, err := LoadConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal("unable to load config", err)
+	}
+
+	if cfg.Groups[0].Policy.Strategy != OneForAll || cfg.Groups[0].Policy.Escalation != StopSupervisor ||
+		time.Duration(cfg.Groups[0].Policy.Backoff.Initial) != 100*time.Millisecond {
+		t.Error("policy not decoded correctly", cfg.Groups[0].Policy)
+	}
+
+	s, err := NewSupervisorFromConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatal("unable to build supervisor", err)
+	}
+
+	if len(labels) != 3 || labels[0] != "primary" {
+		t.Error("factories not invoked with their config", labels)
+	}
+
+	s.Run()
+	<-time.After(time.Millisecond * 50)
+
+	if n := len(s.ListWorkers()); n != 4 {
+		t.Error("unexpected number of workers", n)
+	}
+
+	if n := atomic.LoadInt32(&starts); n != 4 {
+		t.Error("nested supervisor not started", n)
+	}
+
+	s.Shutdown(context.Background())
+	s.Wait()
+}
+
+func Test_ConfigMustRejectUnknownFactories(t *testing.T) {
+	cfg, err := LoadConfig(strings.NewReader(`{"workers": [{"name": "missing"}]}`))
+	if err != nil {
+		t.Fatal("unable to load JSON config", err)
+	}
+
+	if _, err := NewSupervisorFromConfig(context.Background(), cfg); !errors.Is(err, ErrUnknownFactory) {
+		t.Error("expected ErrUnknownFactory", err)
+	}
+}
+
+func Test_ApplyConfigMustReconcileRunningSupervisor(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	starts := int32(0)
+	RegisterFactory("worker", func(WorkerConfig) (Supervisable, error) {
+		return func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			atomic.AddInt32(&starts, 1)
+			<-ctx.Done()
+		}, nil
+	})
+
+	initial, _ := LoadConfig(strings.NewReader(`
+workers:
+  - {name: scaled, factory: worker, count: 3}
+  - {name: removed, factory: worker}
+  - {name: replaced, factory: worker, args: {version: "1"}}
+`))
+
+	s, err := NewSupervisorFromConfig(context.Background(), initial)
+	if err != nil {
+		t.Fatal("unable to build supervisor", err)
+	}
+	s.Run()
+	<-time.After(time.Millisecond * 20)
+
+	desired, _ := LoadConfig(strings.NewReader(`
+workers:
+  - {name: scaled, factory: worker, count: 1}
+  - {name: replaced, factory: worker, args: {version: "2"}}
+  - {name: added, factory: worker}
+groups:
+  - name: extra
+    workers:
+      - {name: grouped, factory: worker, count: 2}
+`))
+
+	if err := s.ApplyConfig(desired); err != nil {
+		t.Fatal("unable to apply config", err)
+	}
+	<-time.After(time.Millisecond * 20)
+
+	counts := map[string]int{}
+	for _, info := range s.ListWorkers() {
+		if !info.Running {
+			t.Error("worker not running after applying config", info.Name)
+		}
+		counts[info.Name]++
+	}
+
+	if len(counts) != 4 || counts["scaled"] != 1 || counts["replaced"] != 1 ||
+		counts["added"] != 1 || counts["grouped"] != 2 {
+		t.Error("running workers do not match the applied config", counts)
+	}
+
+	// 5 initial instances, 1 replacement, 1 addition and 2 in the new group.
+	if n := atomic.LoadInt32(&starts); n != 9 {
+		t.Error("unexpected number of worker starts", n)
+	}
+
+	s.Shutdown(context.Background())
+	s.Wait()
+}
+
+func Test_ApplyConfigMustMoveWorkersBetweenGroups(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	RegisterFactory("mover", func(WorkerConfig) (Supervisable, error) {
+		return func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			<-ctx.Done()
+		}, nil
+	})
+
+	ungrouped, _ := LoadConfig(strings.NewReader(`
+workers:
+  - {name: moved, factory: mover}
+  - {name: other, factory: mover}
+groups:
+  - name: extra
+    workers:
+      - {name: stays, factory: mover}
+`))
+	grouped, _ := LoadConfig(strings.NewReader(`
+workers:
+  - {name: other, factory: mover}
+groups:
+  - name: extra
+    workers:
+      - {name: stays, factory: mover}
+      - {name: moved, factory: mover}
+`))
+
+	s, err := NewSupervisorFromConfig(context.Background(), ungrouped)
+	if err != nil {
+		t.Fatal("unable to build supervisor", err)
+	}
+	s.Run()
+
+	// The order in which changes are planned varies between runs, so the
+	// worker is moved back and forth repeatedly.
+	for i := 0; i < 20; i++ {
+		cfg, group := grouped, "extra"
+		if i%2 == 1 {
+			cfg, group = ungrouped, ""
+		}
+
+		if err := s.ApplyConfig(cfg); err != nil {
+			t.Fatal("unable to apply config", err)
+		}
+
+		moved := []WorkerStatus{}
+		for _, status := range s.Status() {
+			if status.Name == "moved" {
+				moved = append(moved, status)
+			}
+		}
+
+		if len(moved) != 1 || moved[0].Group != group {
+			t.Fatal("expected the worker to have moved group", i, moved)
+		}
+	}
+
+	s.Shutdown(context.Background())
+	s.Wait()
+}
+
+func Test_ApplyConfigMustLeaveSupervisorUntouchedOnError(t *testing.T) {
+	s := NewSimpleSupervisor(context.Background(), nil)
+	if err := s.ApplyConfig(&Config{}); err != ErrNotConfigured {
+		t.Error("expected ErrNotConfigured", err)
+	}
+}
+
+func Test_ApplyConfigMustPlanNestedSupervisorsUpFront(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	RegisterFactory("nested", func(WorkerConfig) (Supervisable, error) {
+		return func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			<-ctx.Done()
+		}, nil
+	})
+
+	initial, _ := LoadConfig(strings.NewReader(`
+workers:
+  - {name: removed, factory: nested}
+  - name: tree
+    supervisor:
+      workers:
+        - {name: inner, factory: nested}
+`))
+
+	s, err := NewSupervisorFromConfig(context.Background(), initial)
+	if err != nil {
+		t.Fatal("unable to build supervisor", err)
+	}
+	s.Run()
+	<-time.After(time.Millisecond * 20)
+
+	invalid, _ := LoadConfig(strings.NewReader(`
+shutdown_timeout: 3s
+workers:
+  - name: tree
+    supervisor:
+      workers:
+        - {name: inner, factory: missing}
+`))
+
+	if err := s.ApplyConfig(invalid); !errors.Is(err, ErrUnknownFactory) {
+		t.Error("expected the nested Supervisor's error to be returned", err)
+	}
+
+	if len(s.WorkerInfo("removed")) != 1 {
+		t.Error("expected the Supervisor to be untouched upon an invalid nested config")
+	}
+
+	desired, _ := LoadConfig(strings.NewReader(`
+shutdown_timeout: 3s
+workers:
+  - name: tree
+    supervisor:
+      workers:
+        - {name: inner, factory: nested, count: 2}
+`))
+
+	if err := s.ApplyConfig(desired); err != nil {
+		t.Fatal("unable to apply config", err)
+	}
+	<-time.After(time.Millisecond * 20)
+
+	if child := s.childSupervisor("tree"); child == nil || len(child.WorkerInfo("inner")) != 2 || len(s.WorkerInfo("removed")) != 0 {
+		t.Error("expected the nested Supervisor to be reconfigured", s.ListWorkers())
+	}
+
+	s.mu.Lock()
+	timeout := s.shutdownTimeout
+	s.mu.Unlock()
+	if timeout != 3*time.Second {
+		t.Error("expected the shutdown timeout to be applied", timeout)
+	}
+
+	s.Shutdown(context.Background())
+	s.Wait()
+}
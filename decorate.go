@@ -0,0 +1,32 @@
+package supervisor
+
+import "context"
+
+// ContextDecorator derives the context given to a run of a worker, given
+// the statistics of the instance about to run; it must return a context
+// derived from the one it's given.
+type ContextDecorator func(ctx context.Context, w WorkerInfo) context.Context
+
+// WithContextDecorator sets a function applied to the context of each worker
+// run, allowing values - such as loggers, tenant IDs or feature flags - to
+// be injected into every worker's context centrally, rather than captured
+// by each Supervisable. The decorator is called as each run begins,
+// including upon restarts; a nil decorator removes any set previously.
+func (s *Supervisor) WithContextDecorator(fn ContextDecorator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateHooksLocked(func(h *runHooks) {
+		h.decorate = fn
+	})
+}
+
+// decorated applies the Supervisor's ContextDecorator, if any, to the
+// context of the worker's run.
+func (h *runHooks) decorated(w *worker, ctx context.Context) context.Context {
+	if h.decorate == nil {
+		return ctx
+	}
+
+	return h.decorate(ctx, w.info())
+}
@@ -0,0 +1,59 @@
+package supervisor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type tenantKey struct{}
+
+func Test_ContextDecoratorMustApplyToEveryRun(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var (
+		mu      sync.Mutex
+		tenants []string
+	)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{
+			Name: "tenant",
+			Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+
+				mu.Lock()
+				tenants = append(tenants, ctx.Value(tenantKey{}).(string))
+				n := len(tenants)
+				mu.Unlock()
+
+				if n == 1 {
+					ReportError(ctx, errTest)
+					return
+				}
+				<-ctx.Done()
+			},
+		}},
+		ContextDecorator: func(ctx context.Context, w WorkerInfo) context.Context {
+			return context.WithValue(ctx, tenantKey{}, w.Name)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(tenants) != 2 || tenants[0] != "tenant" || tenants[1] != "tenant" {
+		t.Error("expected the decorated context to be given to each run", tenants)
+	}
+}
@@ -0,0 +1,81 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FromErrgroup adapts a set of errgroup-shaped functions - which take no
+// context and return an error - to a single Supervisable, easing a gradual
+// migration from golang.org/x/sync/errgroup. Upon each run build is given
+// the context the functions should observe, as with errgroup.WithContext,
+// and the functions it returns are run concurrently:
+//
+//	s.AddWorker(supervisor.WorkerSpec{
+//		Name: "ingest",
+//		Worker: supervisor.FromErrgroup(func(ctx context.Context) []func() error {
+//			return []func() error{
+//				func() error { return consume(ctx, queue) },
+//				func() error { return flush(ctx, store) },
+//			}
+//		}),
+//	})
+//
+// As with errgroup, the first function to return an error - or to panic -
+// cancels the context; once every function has returned that error is
+// reported as the reason for the exit, and so the functions are restarted
+// together. The context is also cancelled upon the worker being stopped.
+func FromErrgroup(build func(ctx context.Context) []func() error) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var (
+			wg    sync.WaitGroup
+			once  sync.Once
+			first error
+		)
+
+		fail := func(err error) {
+			once.Do(func() {
+				first = err
+				cancel()
+			})
+		}
+
+		Ready(ctx)
+		for _, fn := range build(ctx) {
+			wg.Add(1)
+			go func(fn func() error) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						fail(fmt.Errorf("%w: %v", ErrWorkerPanicked, r))
+					}
+				}()
+
+				if err := fn(); err != nil {
+					fail(err)
+				}
+			}(fn)
+		}
+		wg.Wait()
+
+		if first != nil {
+			ReportError(ctx, first)
+		}
+	}
+}
+
+// FromErrgroupFuncs adapts errgroup-shaped functions which have no need of
+// a context to a Supervisable; see FromErrgroup. As they can't observe the
+// worker being stopped, the functions should return of their own accord -
+// the worker exits once they have.
+func FromErrgroupFuncs(fns ...func() error) Supervisable {
+	return FromErrgroup(func(context.Context) []func() error {
+		return fns
+	})
+}
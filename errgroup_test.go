@@ -0,0 +1,72 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_FromErrgroupMustCancelSiblingsAndRestartTogether(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var runs, cancelled int32
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{
+			Name: "group",
+			Worker: FromErrgroup(func(ctx context.Context) []func() error {
+				return []func() error{
+					func() error {
+						if atomic.AddInt32(&runs, 1) == 1 {
+							return errTest
+						}
+						<-ctx.Done()
+						return nil
+					},
+					func() error {
+						<-ctx.Done()
+						atomic.AddInt32(&cancelled, 1)
+						return nil
+					},
+				}
+			}),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	if atomic.LoadInt32(&runs) != 2 || atomic.LoadInt32(&cancelled) != 1 {
+		t.Error("expected the error to cancel its sibling, and restart both", runs, cancelled)
+	}
+
+	history := s.History("group", 0)
+	if len(history) != 1 || history[0].Reason != errTest {
+		t.Error("expected the first error to be reported", history)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+
+	if atomic.LoadInt32(&cancelled) != 2 {
+		t.Error("expected stopping the worker to cancel the functions")
+	}
+}
+
+func Test_FromErrgroupFuncsMustReportPanics(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	fn := ToFunc(FromErrgroupFuncs(
+		func() error { return nil },
+		func() error { panic("testing") },
+	))
+
+	if err := fn(context.Background()); !errors.Is(err, ErrWorkerPanicked) {
+		t.Error("expected the panic to be reported", err)
+	}
+}
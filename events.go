@@ -0,0 +1,205 @@
+package supervisor
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// eventBufferSize is the number of events retained for each subscriber to
+// Events; it must be a power of two.
+const eventBufferSize = 256
+
+// EventType distinguishes the events published via Events.
+type EventType int
+
+const (
+	// WorkerStarted is published as each run of a worker instance begins,
+	// including upon restarts.
+	WorkerStarted EventType = iota + 1
+	// WorkerFailed is published once a worker instance has exited
+	// unexpectedly, before it's restarted.
+	WorkerFailed
+	// WorkerStopped is published once a worker instance has stopped, and
+	// won't be restarted.
+	WorkerStopped
+	// WorkerLeaked is published for each worker goroutine found to have
+	// leaked upon Shutdown; see WithLeakDiagnostics.
+	WorkerLeaked
+)
+
+// Event describes a change in the state of a worker instance.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType
+	// Time is when the event occurred.
+	Time time.Time
+	// Name is the name of the worker.
+	Name string
+	// Instance is the instance of the worker.
+	Instance int
+	// Exit is the exit of a WorkerFailed instance.
+	Exit Exit
+	// Stack is the stack trace of a WorkerLeaked goroutine.
+	Stack string
+}
+
+// Events returns a stream of the events of the Supervisor's workers, until
+// the context is cancelled, upon which the stream is closed.
+//
+// Each stream buffers the most recent events, discarding the oldest should
+// it not be read from quickly enough; a slow reader never blocks, or slows,
+// the workers being restarted.
+func (s *Supervisor) Events(ctx context.Context) <-chan Event {
+	sub := &subscription{ring: newEventRing(eventBufferSize), notify: make(chan struct{}, 1)}
+
+	s.mu.Lock()
+	subs, _ := s.subscribers.Load().([]*subscription)
+	s.subscribers.Store(append(append([]*subscription{}, subs...), sub))
+	s.mu.Unlock()
+
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		defer s.unsubscribe(sub)
+
+		sub.pump(ctx, out)
+	}()
+
+	return out
+}
+
+func (s *Supervisor) unsubscribe(sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, _ := s.subscribers.Load().([]*subscription)
+	kept := make([]*subscription, 0, len(subs))
+	for _, candidate := range subs {
+		if candidate != sub {
+			kept = append(kept, candidate)
+		}
+	}
+
+	s.subscribers.Store(kept)
+}
+
+// publish delivers the event to every subscriber to Events, without
+// blocking; it's a no-op should there be none.
+func (s *Supervisor) publish(e Event) {
+	subs, _ := s.subscribers.Load().([]*subscription)
+	if len(subs) == 0 {
+		return
+	}
+
+	e.Time = ClockFrom(s.parent).Now()
+	for _, sub := range subs {
+		sub.ring.push(e)
+		select {
+		case sub.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// subscription is a single stream returned by Events.
+type subscription struct {
+	ring   *eventRing
+	notify chan struct{}
+}
+
+// pump delivers the subscription's events to the stream, waiting for more
+// whenever its ring is empty.
+func (sub *subscription) pump(ctx context.Context, out chan<- Event) {
+	for {
+		e, ok := sub.ring.pop()
+		if !ok {
+			select {
+			case <-sub.notify:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case out <- e:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// eventRing is a bounded, lock-free, multi-producer multi-consumer queue of
+// Events; once full, pushing an event discards the oldest. Each slot carries
+// a sequence number denoting whether it's ready to be written to, or read
+// from, for the current lap of the ring.
+type eventRing struct {
+	head    uint64
+	tail    uint64
+	dropped uint64
+	mask    uint64
+	slots   []eventSlot
+}
+
+type eventSlot struct {
+	seq   uint64
+	event Event
+}
+
+func newEventRing(size int) *eventRing {
+	r := &eventRing{mask: uint64(size - 1), slots: make([]eventSlot, size)}
+	for i := range r.slots {
+		r.slots[i].seq = uint64(i)
+	}
+
+	return r
+}
+
+// push adds the event to the ring, discarding the oldest should it be full.
+func (r *eventRing) push(e Event) {
+	for {
+		tail := atomic.LoadUint64(&r.tail)
+		slot := &r.slots[tail&r.mask]
+
+		switch seq := atomic.LoadUint64(&slot.seq); {
+		case seq == tail:
+			if atomic.CompareAndSwapUint64(&r.tail, tail, tail+1) {
+				slot.event = e
+				atomic.StoreUint64(&slot.seq, tail+1)
+				return
+			}
+		case seq < tail:
+			// The slot still holds the event from the previous lap, so the
+			// ring is full.
+			if _, ok := r.pop(); ok {
+				atomic.AddUint64(&r.dropped, 1)
+			} else {
+				// A reader is part way through freeing the slot.
+				runtime.Gosched()
+			}
+		}
+	}
+}
+
+// pop removes the oldest event from the ring, returning false should it be
+// empty.
+func (r *eventRing) pop() (Event, bool) {
+	for {
+		head := atomic.LoadUint64(&r.head)
+		slot := &r.slots[head&r.mask]
+
+		switch seq := atomic.LoadUint64(&slot.seq); {
+		case seq == head+1:
+			if atomic.CompareAndSwapUint64(&r.head, head, head+1) {
+				e := slot.event
+				slot.event = Event{}
+				atomic.StoreUint64(&slot.seq, head+r.mask+1)
+				return e, true
+			}
+		case seq < head+1:
+			return Event{}, false
+		}
+	}
+}
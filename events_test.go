@@ -0,0 +1,143 @@
+package supervisor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_EventRingMustDropOldestWhenFull(t *testing.T) {
+	r := newEventRing(4)
+	for i := 0; i < 10; i++ {
+		r.push(Event{Instance: i})
+	}
+
+	for want := 6; want < 10; want++ {
+		if e, ok := r.pop(); !ok || e.Instance != want {
+			t.Error("expected the most recent events, oldest first", want, e.Instance, ok)
+		}
+	}
+
+	if _, ok := r.pop(); ok {
+		t.Error("expected the ring to be empty")
+	}
+
+	if r.dropped != 6 {
+		t.Error("expected the oldest events to be dropped", r.dropped)
+	}
+}
+
+func Test_EventRingMustSupportConcurrentProducers(t *testing.T) {
+	const producers, events = 4, 1000
+
+	r := newEventRing(64)
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < events; i++ {
+				r.push(Event{Name: "producer", Instance: p})
+			}
+		}(p)
+	}
+
+	received := 0
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	for {
+		if _, ok := r.pop(); ok {
+			received++
+			continue
+		}
+
+		select {
+		case <-stopped:
+			for _, ok := r.pop(); ok; _, ok = r.pop() {
+				received++
+			}
+
+			if total := received + int(r.dropped); total != producers*events {
+				t.Error("expected every event to be either received or dropped", received, r.dropped)
+			}
+			return
+		default:
+		}
+	}
+}
+
+func Test_EventsMustNotBlockRestartsWhenUnread(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{Specs: []WorkerSpec{{
+		Name: "crashy",
+		Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			ReportError(ctx, errTest)
+		},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := s.Events(ctx)
+	s.Run()
+
+	deadline := time.After(time.Second * 5)
+	for s.WorkerInfo("crashy")[0].Restarts < eventBufferSize*4 {
+		select {
+		case <-deadline:
+			t.Fatal("expected restarts to continue whilst the events are unread")
+		default:
+		}
+	}
+
+	s.Stop()
+	s.Wait()
+
+	received := 0
+	var last Event
+	for e := range drain(events, time.Millisecond*50) {
+		received++
+		last = e
+	}
+
+	if received == 0 || received > eventBufferSize+1 {
+		t.Error("expected only the most recent events to be buffered", received)
+	}
+
+	if last.Type != WorkerStopped || last.Name != "crashy" {
+		t.Error("expected the most recent event to be retained", last)
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("expected the stream to be closed once its context is cancelled")
+	}
+}
+
+// drain receives from the stream until it's idle for the duration.
+func drain(events <-chan Event, idle time.Duration) <-chan Event {
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case e := <-events:
+				out <- e
+			case <-time.After(idle):
+				return
+			}
+		}
+	}()
+
+	return out
+}
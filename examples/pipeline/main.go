@@ -84,17 +84,23 @@ func main() {
 	}
 
 	wg := &sync.WaitGroup{}
-	s := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
 		Workers: supervisorWorkers,
 		Waiter:  wg,
 	})
+	if err != nil {
+		panic(err)
+	}
 	s.Run()
 
 	go func() {
 		counter := 0
+		ticker := time.NewTicker(time.Millisecond * 100)
+		defer ticker.Stop()
+
 		for {
 			select {
-			case <-time.After(time.Millisecond * 100):
+			case <-ticker.C:
 				fmt.Println("[Example] Dispatching counter", counter)
 				ioChans[0] <- counter
 				counter++
@@ -46,12 +46,15 @@ func generateSupervisable(shouldPanic bool) supervisor.Supervisable {
 			close(completed)
 		}()
 
+		ticker := time.NewTicker(time.Millisecond * 250)
+		defer ticker.Stop()
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
 
-			case <-time.After(time.Millisecond * 250):
+			case <-ticker.C:
 				counter++
 				fmt.Println("Got new count", counter)
 
@@ -0,0 +1,164 @@
+// Package fswatch provides a supervised watcher of files and directories,
+// which emits their change events to a channel or an actor; it's commonly
+// used to reload configuration once it changes.
+//
+//	events := make(chan fswatch.Event)
+//	s.AddWorker(supervisor.WorkerSpec{
+//		Name:   "config-watcher",
+//		Worker: fswatch.Watch([]string{"/etc/app"}, fswatch.ToChannel(events), fswatch.Debounce(100*time.Millisecond)),
+//	})
+//
+// Watches are established upon each run of the worker, so should the
+// watcher fail - or a Sink panic - then they're re-established once it's
+// restarted. Editors often replace a file rather than writing to it, which
+// ends a watch upon the file itself; watching its directory is more robust.
+package fswatch
+
+import (
+	"context"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	supervisor "go.fergus.london/go-supervise"
+	"go.fergus.london/go-supervise/actor"
+)
+
+// Event is a change to a watched file, or to a file within a watched
+// directory.
+type Event = fsnotify.Event
+
+// Sink receives the events of a watcher; returning an error fails the
+// worker, so it's restarted.
+type Sink func(ctx context.Context, e Event) error
+
+// ToChannel returns a Sink sending each event to the channel, blocking
+// until it's received or the worker is stopped.
+func ToChannel(ch chan<- Event) Sink {
+	return func(ctx context.Context, e Event) error {
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+		}
+
+		return nil
+	}
+}
+
+// ToActor returns a Sink sending each event to the actor, via actor.Send.
+func ToActor(ref *actor.ActorRef) Sink {
+	return func(ctx context.Context, e Event) error {
+		return actor.Send(ctx, ref, e)
+	}
+}
+
+// Option configures a watcher.
+type Option func(*options)
+
+type options struct {
+	debounce time.Duration
+}
+
+// Debounce coalesces the events for each path into one, carrying each of
+// their Ops, which is emitted once no further events have occurred for the
+// duration; a single save can otherwise cause several events.
+func Debounce(d time.Duration) Option {
+	return func(o *options) {
+		o.debounce = d
+	}
+}
+
+// Watch returns a Supervisable watching the paths, passing their events to
+// the Sink; it reports itself ready once every watch is established. An
+// error from the watcher, or from establishing a watch, is reported as the
+// reason for the exit.
+func Watch(paths []string, sink Sink, opts ...Option) supervisor.Supervisable {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		w, err := fsnotify.NewWatcher()
+		if err != nil {
+			supervisor.ReportError(ctx, err)
+			return
+		}
+		defer w.Close()
+
+		for _, path := range paths {
+			if err := w.Add(path); err != nil {
+				supervisor.ReportError(ctx, err)
+				return
+			}
+		}
+
+		pending := map[string]Event{}
+		var (
+			timer *time.Timer
+			fire  <-chan time.Time
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		emit := func(e Event) bool {
+			if err := sink(ctx, e); err != nil {
+				supervisor.ReportError(ctx, err)
+				return false
+			}
+
+			return true
+		}
+
+		supervisor.Ready(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-w.Errors:
+				if ok {
+					supervisor.ReportError(ctx, err)
+				}
+				return
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+
+				if o.debounce <= 0 {
+					if !emit(e) {
+						return
+					}
+					continue
+				}
+
+				e.Op |= pending[e.Name].Op
+				pending[e.Name] = e
+				if timer == nil {
+					timer = time.NewTimer(o.debounce)
+				} else {
+					if !timer.Stop() {
+						select {
+						case <-timer.C:
+						default:
+						}
+					}
+					timer.Reset(o.debounce)
+				}
+				fire = timer.C
+			case <-fire:
+				fire = nil
+				for path, e := range pending {
+					delete(pending, path)
+					if !emit(e) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package fswatch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func Test_WatchMustEmitEventsAndReestablishAfterFailure(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	dir := t.TempDir()
+	events := make(chan Event, 10)
+	forward := ToChannel(events)
+
+	failed := false
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{
+			Name: "watcher",
+			Worker: Watch([]string{dir}, func(ctx context.Context, e Event) error {
+				if !failed {
+					failed = true
+					return errors.New("failed")
+				}
+				return forward(ctx, e)
+			}, Debounce(time.Millisecond*20)),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	path := filepath.Join(dir, "config.yaml")
+	write := func() {
+		if err := os.WriteFile(path, []byte("key: value"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	<-time.After(time.Millisecond * 50)
+	write()
+	<-time.After(time.Millisecond * 100)
+
+	if infos := s.WorkerInfo("watcher"); len(infos) != 1 || infos[0].Restarts != 1 {
+		t.Fatal("expected the failing sink to restart the watcher", infos)
+	}
+
+	write()
+	write()
+	write()
+
+	select {
+	case e := <-events:
+		if e.Name != path || !e.Has(fsnotify.Write) {
+			t.Error("unexpected event", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the watch to be re-established after the restart")
+	}
+
+	<-time.After(time.Millisecond * 50)
+	if len(events) != 0 {
+		t.Error("expected the writes to be debounced", len(events))
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
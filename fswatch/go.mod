@@ -0,0 +1,18 @@
+module go.fergus.london/go-supervise/fswatch
+
+go 1.18
+
+require (
+	github.com/fsnotify/fsnotify v1.7.0
+	go.fergus.london/go-supervise v0.1.0
+	go.uber.org/goleak v1.3.0
+)
+
+require (
+	golang.org/x/sys v0.7.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The root module is replaced whilst developing within this repository;
+// the replacement is ignored by modules which depend upon this one.
+replace go.fergus.london/go-supervise => ../
@@ -1,6 +1,6 @@
 module go.fergus.london/go-supervise
 
-go 1.15
+go 1.18
 
 require (
 	github.com/mattn/go-runewidth v0.0.12 // indirect
@@ -10,6 +10,7 @@ require (
 	go.uber.org/goleak v1.1.10
 	golang.org/x/lint v0.0.0-20201208152925-83fdc39ff7b5 // indirect
 	golang.org/x/net v0.0.0-20210423184538-5f58ad60dda6 // indirect
+	golang.org/x/sys v0.7.0
 	golang.org/x/tools v0.1.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
@@ -0,0 +1,298 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxRestartRecords is the minimum number of restarts retained by a group
+// when evaluating its RestartPolicy.
+const maxRestartRecords = 64
+
+// ErrRestartBudgetExhausted is the Cause of a Supervisor which was stopped
+// as a group exceeded its restart budget.
+var ErrRestartBudgetExhausted = errors.New("supervisor: restart budget exhausted")
+
+// Strategy determines which workers in a group are restarted when one of
+// them fails, mirroring the restart strategies of Erlang/OTP.
+type Strategy int
+
+const (
+	// OneForOne only restarts the worker which failed.
+	OneForOne Strategy = iota
+	// OneForAll restarts every worker in the group when any one of them
+	// fails.
+	OneForAll
+	// RestForOne restarts the worker which failed, along with any workers
+	// declared after it within the group.
+	RestForOne
+)
+
+// Escalation determines what happens once a group has exhausted its
+// restart budget.
+type Escalation int
+
+const (
+	// StopGroup stops every worker in the group, leaving the rest of the
+	// Supervisor running.
+	StopGroup Escalation = iota
+	// StopSupervisor stops the whole Supervisor.
+	StopSupervisor
+)
+
+// RestartPolicy describes how eagerly the workers in a group are restarted.
+type RestartPolicy struct {
+	// MaxRestarts is the number of restarts permitted within Period before
+	// escalating; zero means restarts are unlimited.
+	MaxRestarts int
+	// Period is the sliding window in which MaxRestarts is counted.
+	Period time.Duration
+	// Backoff determines the delay before each restart, based upon the
+	// number of restarts within the current Period.
+	Backoff Backoff
+	// Escalation is the action taken when MaxRestarts is exceeded.
+	Escalation Escalation
+	// Strategy determines which workers are restarted upon a failure.
+	Strategy Strategy
+}
+
+// Group is a named partition of workers - a bulkhead - with its own restart
+// budget; a group which is repeatedly failing can exhaust its budget without
+// affecting workers in other groups.
+type Group struct {
+	// Name identifies the group.
+	Name string
+	// Workers are the workers belonging to the group.
+	Workers []WorkerSpec
+	// Policy is the restart policy applied to the group.
+	Policy RestartPolicy
+}
+
+// group is the runtime state of a Group.
+type group struct {
+	name    string
+	policy  RestartPolicy
+	workers []*worker
+
+	mu       sync.Mutex
+	restarts []time.Time
+	ctx      context.Context
+	stop     context.CancelFunc
+	usage    groupUsage
+	// quarantined is set once the group has been stopped for exceeding its
+	// restart budget, until it's next started.
+	quarantined bool
+}
+
+func newGroup(name string, policy RestartPolicy) *group {
+	return &group{name: name, policy: policy}
+}
+
+// start derives a new context for the group's workers.
+func (g *group) start(ctx context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.ctx, g.stop = context.WithCancel(ctx)
+	g.restarts = nil
+	g.quarantined = false
+}
+
+func (g *group) isQuarantined() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.quarantined
+}
+
+// context returns the context of the group's workers, or nil if the group
+// hasn't been started.
+func (g *group) context() context.Context {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.ctx
+}
+
+// isRunning returns whether the group has been started, and not yet
+// stopped.
+func (g *group) isRunning() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.ctx != nil && g.ctx.Err() == nil
+}
+
+func (g *group) setPolicy(policy RestartPolicy) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.policy = policy
+}
+
+func (g *group) addWorkers(workers []*worker) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.workers = append(g.workers, workers...)
+}
+
+func (g *group) removeWorkers(removed map[*worker]bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.workers = removeWorkers(g.workers, removed)
+}
+
+// restart records a restart, and returns the delay before it should occur;
+// if the group's budget has been exhausted then ok is false.
+func (g *group) restart(now time.Time) (delay time.Duration, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	recent := g.restarts[:0]
+	for _, t := range g.restarts {
+		if g.policy.Period == 0 || now.Sub(t) < g.policy.Period {
+			recent = append(recent, t)
+		}
+	}
+	g.restarts = append(recent, now)
+
+	// Without a Period every restart would be retained indefinitely; only
+	// as many as are required to evaluate the policy are kept.
+	limit := maxRestartRecords
+	if g.policy.MaxRestarts >= limit {
+		limit = g.policy.MaxRestarts + 1
+	}
+	if len(g.restarts) > limit {
+		g.restarts = append(g.restarts[:0], g.restarts[len(g.restarts)-limit:]...)
+	}
+
+	if g.policy.MaxRestarts > 0 && len(g.restarts) > g.policy.MaxRestarts {
+		return 0, false
+	}
+
+	return g.policy.Backoff.Duration(len(g.restarts) - 1), true
+}
+
+// escalate applies the group's Escalation once its budget is exhausted.
+func (g *group) escalate(s *Supervisor) {
+	g.mu.Lock()
+	escalation := g.policy.Escalation
+	g.mu.Unlock()
+
+	switch escalation {
+	case StopSupervisor:
+		log(fmt.Sprintf("group %s exceeded its restart budget, stopping supervisor", g.name))
+		s.setCause(ErrRestartBudgetExhausted)
+		s.Stop()
+	default:
+		log(fmt.Sprintf("group %s exceeded its restart budget, stopping group", g.name))
+		g.mu.Lock()
+		g.quarantined = true
+		g.stop()
+		g.mu.Unlock()
+	}
+}
+
+// siblings returns the other workers in the group which should be restarted
+// alongside the failed worker, according to the group's Strategy.
+func (g *group) siblings(failed *worker) []*worker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	found := []*worker{}
+	switch g.policy.Strategy {
+	case OneForAll:
+		for _, w := range g.workers {
+			if w != failed {
+				found = append(found, w)
+			}
+		}
+	case RestForOne:
+		for i, w := range g.workers {
+			if w == failed {
+				found = append(found, g.workers[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// AddGroup adds a Group to the Supervisor, starting its workers should the
+// Supervisor be running. The Group is validated as it would be by
+// NewSupervisorWithOptions, and neither it nor its workers may share a name
+// with those already present.
+func (s *Supervisor) AddGroup(grp Group) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, g := range s.groups {
+		if g.name == grp.Name {
+			return fmt.Errorf("%w: group %q", ErrDuplicateName, grp.Name)
+		}
+	}
+
+	if err := validatePolicy(grp.Name, grp.Policy); err != nil {
+		return err
+	}
+
+	if err := s.takenLocked(grp.Workers).addSpecs(grp.Workers); err != nil {
+		return err
+	}
+
+	g := newGroup(grp.Name, grp.Policy)
+	running := s.groups[0].context() != nil && s.ctx.Err() == nil
+	if running {
+		g.start(s.ctx)
+	}
+
+	workers := newWorkers(grp.Workers, s.historySize, g)
+	s.groups = append(s.groups, g)
+	s.addWorkersLocked(workers)
+	if running {
+		s.startWorkersLocked(workers)
+	}
+
+	return nil
+}
+
+// AddWorker adds a worker to the Supervisor's default group, starting its
+// instances should the Supervisor be running. The WorkerSpec is validated as
+// it would be by NewSupervisorWithOptions, and mustn't share a name with an
+// existing worker.
+func (s *Supervisor) AddWorker(spec WorkerSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	specs := []WorkerSpec{spec}
+	if err := s.takenLocked(specs).addSpecs(specs); err != nil {
+		return err
+	}
+
+	workers := newWorkers(specs, s.historySize, s.groups[0])
+	s.addWorkersLocked(workers)
+	if s.groups[0].isRunning() {
+		s.startWorkersLocked(workers)
+	}
+
+	return nil
+}
+
+// RemoveWorker stops every instance of the named worker, waiting for each
+// to exit, and removes the worker from the Supervisor; it returns false
+// should there be no such worker. It mustn't be called by the worker being
+// removed, as it would wait upon itself.
+func (s *Supervisor) RemoveWorker(name string) bool {
+	if len(s.findWorkers(name)) == 0 {
+		return false
+	}
+
+	s.removeWorkers(name, 0)
+	return true
+}
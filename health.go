@@ -0,0 +1,77 @@
+package supervisor
+
+import "context"
+
+type workerKey struct{}
+
+// Ready is called by a worker once it has initialised and is able to carry
+// out its work; readiness is reset each time the worker is restarted. See
+// Supervisor.Ready.
+func Ready(ctx context.Context) {
+	if w, ok := ctx.Value(workerKey{}).(*worker); ok {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.ready = true
+	}
+}
+
+// Heartbeat is called periodically by a worker to indicate that it's still
+// making progress; the time of the last heartbeat is available via
+// ListWorkers, allowing stalled workers to be detected.
+func Heartbeat(ctx context.Context) {
+	if w, ok := ctx.Value(workerKey{}).(*worker); ok {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.lastHeartbeat = ClockFrom(ctx).Now()
+	}
+}
+
+// ObserveQueue is called by a worker which consumes from a queue, such as a
+// buffered channel, with a function returning the queue's current depth and
+// capacity; the function is called whenever the worker's statistics are
+// requested, populating QueueDepth and QueueCapacity. As with readiness, the
+// function is forgotten each time the worker is restarted.
+func ObserveQueue(ctx context.Context, fn func() (depth, capacity int)) {
+	if w, ok := ctx.Value(workerKey{}).(*worker); ok {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.queue = fn
+	}
+}
+
+// Ready returns whether every worker instance is running and has reported
+// itself as ready.
+func (s *Supervisor) Ready() bool {
+	for _, info := range s.ListWorkers() {
+		if !info.Running || !info.Ready {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Stopping returns a channel which is closed once the Supervisor begins to
+// stop, either via Stop or Shutdown.
+func (s *Supervisor) Stopping() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stopping
+}
+
+// markStopping closes the stopping channel, if it's not already closed.
+func (s *Supervisor) markStopping() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.markStoppingLocked()
+}
+
+func (s *Supervisor) markStoppingLocked() {
+	select {
+	case <-s.stopping:
+	default:
+		close(s.stopping)
+	}
+}
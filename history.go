@@ -0,0 +1,146 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is the number of exits retained for each worker
+// instance when no size is specified.
+const DefaultHistorySize = 100
+
+// Exit records a single occasion on which a worker instance exited and was
+// subsequently restarted.
+type Exit struct {
+	// Time is when the exit was observed by the Supervisor.
+	Time time.Time
+	// Reason is the recovered panic value, or the error reported via
+	// ReportError; it's nil if the worker simply returned.
+	Reason interface{}
+	// Panicked denotes whether Reason is a recovered panic value.
+	Panicked bool
+	// StackDigest is a short hash of the stack trace at the time of a panic,
+	// allowing identical failures to be grouped together.
+	StackDigest string
+}
+
+// exitHistory is a fixed size ring buffer of Exits. Its entries grow as
+// exits are added, so instances which never exit - typically the majority
+// of a large worker set - don't allocate a buffer at all.
+type exitHistory struct {
+	entries []Exit
+	size    int
+	next    int
+	full    bool
+}
+
+func newExitHistory(size int) *exitHistory {
+	if size < 1 {
+		size = DefaultHistorySize
+	}
+
+	return &exitHistory{size: size}
+}
+
+func (h *exitHistory) add(e Exit) {
+	if !h.full {
+		h.entries = append(h.entries, e)
+		h.full = len(h.entries) == h.size
+		return
+	}
+
+	h.entries[h.next] = e
+	h.next = (h.next + 1) % h.size
+}
+
+// list returns the recorded Exits, oldest first.
+func (h *exitHistory) list() []Exit {
+	if !h.full {
+		return append([]Exit{}, h.entries...)
+	}
+
+	return append(append([]Exit{}, h.entries[h.next:]...), h.entries[:h.next]...)
+}
+
+// last returns the most recent Exit, or nil should there be none.
+func (h *exitHistory) last() *Exit {
+	if len(h.entries) == 0 {
+		return nil
+	}
+
+	i := len(h.entries) - 1
+	if h.full {
+		i = (h.next + h.size - 1) % h.size
+	}
+
+	exit := h.entries[i]
+	return &exit
+}
+
+type exitReportKey struct{}
+
+// exitReport is carried by the context of each run, allowing the worker to
+// explain why it exited.
+type exitReport struct {
+	mu    sync.Mutex
+	exit  Exit
+	clock Clock
+}
+
+func withExitReport(ctx context.Context) (context.Context, *exitReport) {
+	report := &exitReport{clock: ClockFrom(ctx)}
+	return context.WithValue(ctx, exitReportKey{}, report), report
+}
+
+func (r *exitReport) set(reason interface{}, panicked bool, stack []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exit.Reason = reason
+	r.exit.Panicked = panicked
+	if stack != nil {
+		h := fnv.New64a()
+		h.Write(stack)
+		r.exit.StackDigest = fmt.Sprintf("%016x", h.Sum64())
+	}
+}
+
+func (r *exitReport) get() Exit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exit := r.exit
+	exit.Time = r.clock.Now()
+	return exit
+}
+
+// Recover is a convenience for satisfying the requirements of Supervisable;
+// when deferred by a worker it recovers any panic, records the panic value
+// as the reason for the exit, and closes the done channel.
+//
+//	func(ctx context.Context, done chan struct{}) {
+//		defer supervisor.Recover(ctx, done)
+//		// ...
+//	}
+func Recover(ctx context.Context, done chan struct{}) {
+	if r := recover(); r != nil {
+		if report, ok := ctx.Value(exitReportKey{}).(*exitReport); ok {
+			report.set(r, true, debug.Stack())
+		}
+	}
+
+	close(done)
+}
+
+// ReportError records err as the reason for the worker's current run
+// exiting; it's available via the Supervisor's History should the worker
+// then be restarted.
+func ReportError(ctx context.Context, err error) {
+	if report, ok := ctx.Value(exitReportKey{}).(*exitReport); ok {
+		report.set(err, false, nil)
+	}
+}
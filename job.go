@@ -0,0 +1,251 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job is a unit of work which is run repeatedly, such as by a Schedule;
+// unlike a Supervisable it's expected to return once its work is complete.
+type Job func(context.Context) error
+
+// Overlap determines what happens when a Job is due to run whilst a
+// previous run is still in progress.
+type Overlap int
+
+const (
+	// SkipOverlap skips the run.
+	SkipOverlap Overlap = iota
+	// QueueOverlap runs the Job again as soon as the previous run completes;
+	// at most one run is queued, however many become due in the meantime.
+	QueueOverlap
+	// AllowOverlap runs the Job concurrently with the previous run.
+	AllowOverlap
+)
+
+// JobOption configures how a Job is run.
+type JobOption func(*jobOptions)
+
+type jobOptions struct {
+	name    string
+	timeout time.Duration
+	overlap Overlap
+	anchor  time.Time
+}
+
+// JobName names the Job; the name identifies it in statistics and logging
+// output.
+func JobName(name string) JobOption {
+	return func(o *jobOptions) {
+		o.name = name
+	}
+}
+
+// JobTimeout cancels the context given to each run of the Job once the
+// duration has elapsed.
+func JobTimeout(d time.Duration) JobOption {
+	return func(o *jobOptions) {
+		o.timeout = d
+	}
+}
+
+// OnOverlap sets the Overlap policy of the Job; it defaults to SkipOverlap.
+func OnOverlap(overlap Overlap) JobOption {
+	return func(o *jobOptions) {
+		o.overlap = overlap
+	}
+}
+
+// AnchorAt aligns the runs of a Periodic Job to the given time, such that
+// they occur at anchor + n*interval. By default runs are aligned to when
+// the Supervisable is started, so each restart shifts the schedule; given
+// an anchor, the schedule is unaffected by restarts.
+func AnchorAt(anchor time.Time) JobOption {
+	return func(o *jobOptions) {
+		o.anchor = anchor
+	}
+}
+
+// JobInfo contains the statistics for a Job.
+type JobInfo struct {
+	// Name is the name of the Job.
+	Name string
+	// Schedule describes when the Job runs.
+	Schedule string
+	// Next is when the Job is next due to run.
+	Next time.Time
+	// LastRun is when the most recent run of the Job began.
+	LastRun time.Time
+	// LastDuration is how long the most recent completed run took.
+	LastDuration time.Duration
+	// LastError is the error returned by the most recent completed run.
+	LastError error
+	// Runs is the number of runs which have completed.
+	Runs int
+	// Failures is the number of runs which returned an error or panicked.
+	Failures int
+	// Skipped is the number of runs skipped due to the Overlap policy.
+	Skipped int
+	// Running is the number of runs currently in progress.
+	Running int
+}
+
+// jobRunner runs a Job according to its options, and records the
+// statistics for it.
+type jobRunner struct {
+	fn   Job
+	opts jobOptions
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	pending bool
+	info    JobInfo
+}
+
+func newJobRunner(fn Job, schedule string, opts []JobOption) *jobRunner {
+	r := &jobRunner{fn: fn}
+	for _, opt := range opts {
+		opt(&r.opts)
+	}
+
+	r.info = JobInfo{Name: r.opts.name, Schedule: schedule}
+	return r
+}
+
+// run returns a Supervisable which triggers the Job at each time returned
+// by next, waiting for any runs in progress upon exiting.
+func (r *jobRunner) run(next func(time.Time) time.Time) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+		defer r.wait()
+
+		clock := ClockFrom(ctx)
+		var timer Timer
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		Ready(ctx)
+		for {
+			now := clock.Now()
+			at := next(now)
+			if at.IsZero() {
+				<-ctx.Done()
+				return
+			}
+
+			r.setNext(at)
+			timer = rearm(timer, clock, at.Sub(now))
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C():
+				r.trigger(ctx)
+			}
+		}
+	}
+}
+
+// trigger starts a run of the Job, subject to its Overlap policy.
+func (r *jobRunner) trigger(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.info.Running > 0 {
+		switch r.opts.overlap {
+		case SkipOverlap:
+			r.info.Skipped++
+			return
+		case QueueOverlap:
+			r.pending = true
+			return
+		}
+	}
+
+	r.startLocked(ctx)
+}
+
+func (r *jobRunner) startLocked(ctx context.Context) {
+	clock := ClockFrom(ctx)
+	r.info.Running++
+	r.info.LastRun = clock.Now()
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		for {
+			started := clock.Now()
+			err := r.call(ctx)
+			if !r.finished(ctx, started, err) {
+				return
+			}
+		}
+	}()
+}
+
+// call runs the Job once, recovering from any panic.
+func (r *jobRunner) call(ctx context.Context) (err error) {
+	if r.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = WithTimeout(ctx, r.opts.timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if reason := recover(); reason != nil {
+			err = fmt.Errorf("supervisor: job panicked: %v", reason)
+		}
+	}()
+
+	return r.fn(ctx)
+}
+
+// finished records the completion of a run, returning whether a queued run
+// should now begin.
+func (r *jobRunner) finished(ctx context.Context, started time.Time, err error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.info.Runs++
+	clock := ClockFrom(ctx)
+	r.info.LastDuration = clock.Now().Sub(started)
+	r.info.LastError = err
+	if err != nil {
+		r.info.Failures++
+		log(fmt.Sprintf("job %s failed: %s", r.opts.name, err))
+	}
+
+	if r.pending && ctx.Err() == nil {
+		r.pending = false
+		r.info.LastRun = clock.Now()
+		return true
+	}
+
+	r.pending = false
+	r.info.Running--
+	return false
+}
+
+// wait blocks until every run in progress has completed.
+func (r *jobRunner) wait() {
+	r.wg.Wait()
+}
+
+func (r *jobRunner) setNext(next time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.info.Next = next
+}
+
+func (r *jobRunner) stats() JobInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.info
+}
@@ -0,0 +1,193 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// leakPollInterval is how often the goroutines of workers are checked for
+// having exited, whilst awaiting the leak diagnostics' grace period.
+const leakPollInterval = 10 * time.Millisecond
+
+// Leak describes a worker goroutine which was still running after Shutdown;
+// see WithLeakDiagnostics.
+type Leak struct {
+	// Name is the name of the worker.
+	Name string
+	// Instance is the instance of the worker.
+	Instance int
+	// Goroutine is the ID of the leaked goroutine.
+	Goroutine uint64
+	// Stack is the goroutine's stack trace, in the format of runtime.Stack.
+	Stack string
+}
+
+// LeakObserver may be implemented by Metrics to be notified of each worker
+// goroutine leaked upon Shutdown; see WithLeakDiagnostics. Leaks are also
+// published via Events.
+type LeakObserver interface {
+	WorkerLeaked(Leak)
+}
+
+// WithLeakDiagnostics enables the detection of leaked workers upon Shutdown,
+// catching workers which ignore the cancellation of their context in
+// production rather than only in tests. Once Shutdown completes, or its
+// context is cancelled, each worker's goroutine has up until the grace
+// period to exit; any still running are then reported - along with their
+// stacks - to the Metrics, should it implement LeakObserver, published via
+// Events, and logged.
+//
+// A grace period of zero disables the diagnostics, which is the default.
+// It takes effect for the runs of workers which begin after it's set.
+func (s *Supervisor) WithLeakDiagnostics(grace time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateHooksLocked(func(h *runHooks) {
+		h.leakGrace = grace
+	})
+}
+
+// Leaks returns the worker goroutines found to have leaked by the most
+// recent Shutdown, should leak diagnostics be enabled.
+func (s *Supervisor) Leaks() []Leak {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Leak(nil), s.leaks...)
+}
+
+// tracked runs a worker, recording the goroutine it runs upon should leak
+// diagnostics be enabled.
+func (s *Supervisor) tracked(w *worker, enabled bool, ctx context.Context, done chan struct{}) {
+	if !enabled {
+		w.fn(ctx, done)
+		return
+	}
+
+	id := goroutineID()
+	s.mu.Lock()
+	if s.goroutines == nil {
+		s.goroutines = make(map[uint64]*worker)
+	}
+	s.goroutines[id] = w
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.goroutines, id)
+	}()
+
+	w.fn(ctx, done)
+}
+
+// diagnoseLeaks waits up until the grace period for the goroutines of every
+// worker to exit, reporting those which don't.
+func (s *Supervisor) diagnoseLeaks() {
+	grace := s.runHooks().leakGrace
+	s.mu.Lock()
+	s.leaks = nil
+	s.mu.Unlock()
+
+	if grace <= 0 {
+		return
+	}
+
+	clock := ClockFrom(s.parent)
+	deadline := clock.Now().Add(grace)
+	remaining := s.trackedGoroutines()
+	if len(remaining) > 0 {
+		var poll Timer
+		for len(remaining) > 0 && clock.Now().Before(deadline) {
+			poll = rearm(poll, clock, leakPollInterval)
+			<-poll.C()
+			remaining = s.trackedGoroutines()
+		}
+		poll.Stop()
+	}
+
+	if len(remaining) == 0 {
+		return
+	}
+
+	stacks := goroutineStacks()
+	leaks := make([]Leak, 0, len(remaining))
+	for id, w := range remaining {
+		leaks = append(leaks, Leak{Name: w.name, Instance: w.instance, Goroutine: id, Stack: stacks[id]})
+	}
+
+	s.mu.Lock()
+	s.leaks = leaks
+	observer, _ := s.metrics.(LeakObserver)
+	s.mu.Unlock()
+
+	for _, leak := range leaks {
+		log(fmt.Sprintf("worker %s (instance %d) leaked upon shutdown:\n%s", leak.Name, leak.Instance, leak.Stack))
+		if observer != nil {
+			observer.WorkerLeaked(leak)
+		}
+		s.publish(Event{Type: WorkerLeaked, Name: leak.Name, Instance: leak.Instance, Stack: leak.Stack})
+	}
+}
+
+func (s *Supervisor) trackedGoroutines() map[uint64]*worker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remaining := make(map[uint64]*worker, len(s.goroutines))
+	for id, w := range s.goroutines {
+		remaining[id] = w
+	}
+
+	return remaining
+}
+
+// goroutineID returns the ID of the calling goroutine, as given in the
+// header of its stack trace.
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	id, _ := parseGoroutineHeader(buf)
+	return id
+}
+
+// goroutineStacks returns the stack trace of every goroutine, by ID.
+func goroutineStacks() map[uint64]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	stacks := map[uint64]string{}
+	for _, trace := range bytes.Split(buf, []byte("\n\n")) {
+		if id, ok := parseGoroutineHeader(trace); ok {
+			stacks[id] = string(trace)
+		}
+	}
+
+	return stacks
+}
+
+// parseGoroutineHeader parses the ID from a stack trace beginning with a
+// header such as "goroutine 42 [chan receive]:".
+func parseGoroutineHeader(trace []byte) (uint64, bool) {
+	trace = bytes.TrimPrefix(trace, []byte("goroutine "))
+	end := bytes.IndexByte(trace, ' ')
+	if end < 0 {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(string(trace[:end]), 10, 64)
+	return id, err == nil
+}
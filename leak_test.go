@@ -0,0 +1,144 @@
+package supervisor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type leakRecorder struct {
+	mu    sync.Mutex
+	leaks []Leak
+}
+
+func (r *leakRecorder) WorkerRestarted(WorkerInfo) {}
+
+func (r *leakRecorder) WorkerLeaked(leak Leak) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.leaks = append(r.leaks, leak)
+}
+
+func stubbornWorker(release chan struct{}) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		<-release
+	}
+}
+
+func Test_LeakDiagnosticsMustReportWorkersIgnoringCancellation(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	release := make(chan struct{})
+	recorder := &leakRecorder{}
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{Name: "stubborn", Worker: stubbornWorker(release)},
+			{Name: "polite", Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+				<-ctx.Done()
+			}},
+		},
+		Metrics:         recorder,
+		LeakGracePeriod: time.Millisecond * 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+	defer s.Wait()
+	defer close(release)
+
+	<-time.After(time.Millisecond * 20)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	if err := s.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Error("expected shutdown to time out", err)
+	}
+
+	leaks := s.Leaks()
+	if len(leaks) != 1 || leaks[0].Name != "stubborn" || leaks[0].Goroutine == 0 {
+		t.Fatal("expected only the stubborn worker to have leaked", leaks)
+	}
+
+	if !strings.Contains(leaks[0].Stack, "stubbornWorker") {
+		t.Error("expected the leak to carry the worker's stack", leaks[0].Stack)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	if len(recorder.leaks) != 1 || recorder.leaks[0].Goroutine != leaks[0].Goroutine {
+		t.Error("expected the leak to be reported to the Metrics", recorder.leaks)
+	}
+}
+
+func Test_LeakDiagnosticsMustNotReportCleanShutdown(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "polite", Count: 3, Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			<-ctx.Done()
+		}}},
+		LeakGracePeriod: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 20)
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Error(err)
+	}
+	s.Wait()
+
+	if leaks := s.Leaks(); len(leaks) != 0 {
+		t.Error("expected no leaks", leaks)
+	}
+}
+
+func Test_LeakDiagnosticsMustWaitUponTheClock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	release := make(chan struct{})
+	clock := NewFakeClock(time.Unix(0, 0))
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs:           []WorkerSpec{{Name: "stubborn", Worker: stubbornWorker(release)}},
+		Clock:           clock,
+		LeakGracePeriod: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+	defer s.Wait()
+	defer close(release)
+
+	<-time.After(time.Millisecond * 20)
+	shutdown, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() { result <- s.Shutdown(shutdown) }()
+
+	wait, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	if err := clock.BlockUntil(wait, 1); err != nil {
+		t.Fatal("expected the grace period to be waited upon the clock", err)
+	}
+
+	clock.Advance(time.Hour)
+	<-result
+	if leaks := s.Leaks(); len(leaks) != 1 {
+		t.Error("expected the leak to be reported once the grace period elapsed", leaks)
+	}
+}
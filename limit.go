@@ -0,0 +1,41 @@
+package supervisor
+
+import "context"
+
+// WithConcurrencyLimit bounds the number of worker runs which may execute
+// concurrently to n, regardless of the number of instances declared by the
+// Supervisor's workers; an instance which is due to start whilst the limit
+// is reached waits until another run exits. A limit of zero, or below,
+// removes any limit.
+//
+// The limit applies to every worker, including nested Supervisors and the
+// workers backing schedules, so should be at least the number of workers
+// which are expected to run indefinitely.
+func (s *Supervisor) WithConcurrencyLimit(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateHooksLocked(func(h *runHooks) {
+		h.limit = nil
+		if n > 0 {
+			h.limit = make(chan struct{}, n)
+		}
+	})
+}
+
+// acquireRun blocks until a worker may begin a run under the concurrency
+// limit, returning a function to release it once the run exits; it returns
+// false if the context is cancelled first.
+func (h *runHooks) acquireRun(ctx context.Context) (func(), bool) {
+	limit := h.limit
+	if limit == nil {
+		return func() {}, true
+	}
+
+	select {
+	case limit <- struct{}{}:
+		return func() { <-limit }, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
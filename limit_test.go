@@ -0,0 +1,65 @@
+package supervisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_SupervisorMustLimitConcurrentRuns(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var concurrent, peak, runs int32
+	s, err := NewSupervisorWithOptions(&Options{
+		ConcurrencyLimit: 2,
+		Specs: []WorkerSpec{{
+			Name:  "heavy",
+			Count: 5,
+			Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+
+				atomic.AddInt32(&runs, 1)
+				n := atomic.AddInt32(&concurrent, 1)
+				defer atomic.AddInt32(&concurrent, -1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+						break
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Millisecond * 10):
+				}
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Run()
+	<-time.After(time.Millisecond * 100)
+
+	running := 0
+	for _, info := range s.WorkerInfo("heavy") {
+		if info.Running {
+			running++
+		}
+	}
+
+	if running > 2 || atomic.LoadInt32(&peak) != 2 {
+		t.Error("expected at most two runs to execute concurrently", running, peak)
+	}
+
+	if atomic.LoadInt32(&runs) < 5 {
+		t.Error("expected waiting instances to run once others exited", runs)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
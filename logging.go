@@ -17,6 +17,12 @@ func WithLogger(l Logger) {
 	logger = l
 }
 
+// Log writes a message to the Logger set via WithLogger; it allows packages
+// built upon the Supervisor to share its logging.
+func Log(msg string) {
+	log(msg)
+}
+
 func log(msg string) {
 	if logger != nil {
 		logger.Println(msg)
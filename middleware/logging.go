@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// Logging writes a line to the given Logger whenever the named worker starts
+// and exits, including the duration of the run.
+func Logging(name string, l supervisor.Logger) Middleware {
+	return func(next supervisor.Supervisable) supervisor.Supervisable {
+		return func(ctx context.Context, done chan struct{}) {
+			defer close(done)
+
+			start := time.Now()
+			l.Println(fmt.Sprintf("worker %s: started", name))
+
+			run(ctx, next)
+
+			l.Println(fmt.Sprintf("worker %s: exited after %s", name, time.Since(start)))
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// Recorder receives measurements about individual worker runs; it's the
+// extension point for exporting run metrics to a monitoring system.
+type Recorder interface {
+	// RunStarted is called as a run of the named worker begins.
+	RunStarted(name string)
+	// RunFinished is called once the run has completed, with its duration.
+	RunFinished(name string, d time.Duration)
+}
+
+// Metrics reports the start and completion of each run of the named worker
+// to the given Recorder.
+func Metrics(name string, r Recorder) Middleware {
+	return func(next supervisor.Supervisable) supervisor.Supervisable {
+		return func(ctx context.Context, done chan struct{}) {
+			defer close(done)
+
+			start := time.Now()
+			r.RunStarted(name)
+
+			run(ctx, next)
+
+			r.RunFinished(name, time.Since(start))
+		}
+	}
+}
+
+// RunStats is a simple in-memory Recorder, useful where there's no external
+// metrics system or for exposing figures on a status endpoint.
+type RunStats struct {
+	mu      sync.Mutex
+	workers map[string]*WorkerRuns
+}
+
+// WorkerRuns contains the measurements recorded for a single worker.
+type WorkerRuns struct {
+	// Started is the number of runs which have begun.
+	Started int
+	// Finished is the number of runs which have completed.
+	Finished int
+	// TotalDuration is the cumulative duration of all completed runs.
+	TotalDuration time.Duration
+	// LastDuration is the duration of the most recently completed run.
+	LastDuration time.Duration
+}
+
+// RunStarted satisfies the Recorder interface.
+func (rs *RunStats) RunStarted(name string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.get(name).Started++
+}
+
+// RunFinished satisfies the Recorder interface.
+func (rs *RunStats) RunFinished(name string, d time.Duration) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	w := rs.get(name)
+	w.Finished++
+	w.TotalDuration += d
+	w.LastDuration = d
+}
+
+// Get returns a copy of the measurements recorded for the named worker.
+func (rs *RunStats) Get(name string) WorkerRuns {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return *rs.get(name)
+}
+
+func (rs *RunStats) get(name string) *WorkerRuns {
+	if rs.workers == nil {
+		rs.workers = make(map[string]*WorkerRuns)
+	}
+
+	w, ok := rs.workers[name]
+	if !ok {
+		w = &WorkerRuns{}
+		rs.workers[name] = w
+	}
+
+	return w
+}
@@ -0,0 +1,32 @@
+// Package middleware provides composable wrappers for Supervisables. A
+// Middleware decorates a worker with additional behaviour - such as time
+// limits - without the worker itself needing to be aware of it.
+package middleware
+
+import (
+	"context"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// Middleware wraps a Supervisable, returning a new Supervisable which must
+// honour the same requirements as the one it wraps.
+type Middleware func(supervisor.Supervisable) supervisor.Supervisable
+
+// Chain applies the given middlewares to a worker. The first middleware is
+// the outermost, so it observes each run before any of the others.
+func Chain(worker supervisor.Supervisable, mws ...Middleware) supervisor.Supervisable {
+	for i := len(mws) - 1; i >= 0; i-- {
+		worker = mws[i](worker)
+	}
+
+	return worker
+}
+
+// run invokes the wrapped worker and blocks until it signals completion via
+// its own channel.
+func run(ctx context.Context, next supervisor.Supervisable) {
+	isDone := make(chan struct{})
+	go next(ctx, isDone)
+	<-isDone
+}
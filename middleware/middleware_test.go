@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+type mockWorker struct {
+	mu     sync.Mutex
+	nCalls int
+}
+
+func (m *mockWorker) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nCalls
+}
+
+func (m *mockWorker) supervisable() supervisor.Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+
+		m.mu.Lock()
+		m.nCalls++
+		m.mu.Unlock()
+
+		<-ctx.Done()
+	}
+}
+
+func Test_ChainMustApplyMiddlewaresOutermostFirst(t *testing.T) {
+	order := []string{}
+	record := func(name string) Middleware {
+		return func(next supervisor.Supervisable) supervisor.Supervisable {
+			return func(ctx context.Context, done chan struct{}) {
+				order = append(order, name)
+				next(ctx, done)
+			}
+		}
+	}
+
+	worker := Chain(func(ctx context.Context, done chan struct{}) {
+		order = append(order, "worker")
+		close(done)
+	}, record("first"), record("second"))
+
+	done := make(chan struct{})
+	worker(context.Background(), done)
+	<-done
+
+	if len(order) != 3 || order[0] != "first" || order[1] != "second" || order[2] != "worker" {
+		t.Error("middlewares applied in unexpected order", order)
+	}
+}
+
+func Test_TimeoutMustCancelLongRunningWorker(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ms := &mockWorker{}
+	s := supervisor.NewSimpleSupervisor(context.Background(), Timeout(50*time.Millisecond)(ms.supervisable()))
+	s.Run()
+
+	<-time.After(time.Millisecond * 180)
+	s.Stop()
+	<-time.After(time.Millisecond * 100)
+
+	if !(ms.calls() >= 3) {
+		t.Error("worker was not restarted after exceeding its deadline", ms.calls())
+	}
+}
+
+func Test_TimeoutMustReportTheDeadlineAsAFailure(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ms := &mockWorker{}
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{
+			Name:        "bounded",
+			Worker:      Timeout(20 * time.Millisecond)(ms.supervisable()),
+			Significant: true,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 100)
+	if s.HasStopped() || ms.calls() < 2 {
+		t.Error("expected a significant worker to be restarted upon its deadline", ms.calls())
+	}
+
+	if exits := s.History("bounded", 0); len(exits) == 0 || exits[0].Reason != context.DeadlineExceeded {
+		t.Error("expected the deadline to be recorded as the reason for the exit", exits)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 100)
+}
+
+type tickLimiter struct {
+	interval time.Duration
+}
+
+func (l *tickLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(l.interval):
+		return nil
+	}
+}
+
+func Test_RateLimitMustThrottleRestarts(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	nCalls := 0
+	worker := func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+		nCalls++
+	}
+
+	s := supervisor.NewSimpleSupervisor(context.Background(), RateLimit(&tickLimiter{50 * time.Millisecond})(worker))
+	s.Run()
+
+	<-time.After(time.Millisecond * 175)
+	s.Stop()
+	<-time.After(time.Millisecond * 100)
+
+	if !(nCalls >= 2 && nCalls <= 4) {
+		t.Error("worker restarts were not throttled by the limiter", nCalls)
+	}
+}
+
+type mockLogger struct {
+	lines []string
+}
+
+func (l *mockLogger) Println(msg string) {
+	l.lines = append(l.lines, msg)
+}
+
+func Test_LoggingAndMetricsMustObserveEachRun(t *testing.T) {
+	l := &mockLogger{}
+	stats := &RunStats{}
+
+	worker := Chain(func(ctx context.Context, done chan struct{}) {
+		close(done)
+	}, Logging("test", l), Metrics("test", stats))
+
+	for i := 0; i < 2; i++ {
+		done := make(chan struct{})
+		worker(context.Background(), done)
+		<-done
+	}
+
+	if len(l.lines) != 4 {
+		t.Error("expected a log line for each start and exit", l.lines)
+	}
+
+	if runs := stats.Get("test"); runs.Started != 2 || runs.Finished != 2 {
+		t.Error("runs not recorded correctly", runs)
+	}
+}
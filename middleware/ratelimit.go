@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// Limiter gates each invocation of a worker. It's satisfied by
+// `*rate.Limiter` from golang.org/x/time/rate, although any implementation
+// which blocks until a run is permitted may be used.
+type Limiter interface {
+	// Wait blocks until the next run is permitted, or returns an error if
+	// the context is cancelled first.
+	Wait(context.Context) error
+}
+
+// RateLimit throttles how frequently a worker may be (re)started; this is
+// independent of any restart behaviour of the Supervisor, and protects
+// against workers which fail - or return - in a tight loop.
+func RateLimit(l Limiter) Middleware {
+	return func(next supervisor.Supervisable) supervisor.Supervisable {
+		return func(ctx context.Context, done chan struct{}) {
+			defer close(done)
+
+			if err := l.Wait(ctx); err != nil {
+				return
+			}
+
+			run(ctx, next)
+		}
+	}
+}
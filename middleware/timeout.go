@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// Timeout bounds each individual run of a worker; once the deadline has
+// passed the worker's context is cancelled. As the Supervisor's own context
+// remains active, the worker will then be restarted as having failed, with
+// context.DeadlineExceeded reported as the reason for its exit.
+func Timeout(d time.Duration) Middleware {
+	return func(next supervisor.Supervisable) supervisor.Supervisable {
+		return func(ctx context.Context, done chan struct{}) {
+			defer close(done)
+
+			runCtx, cancel := supervisor.WithTimeout(ctx, d)
+			defer cancel()
+
+			run(runCtx, next)
+			if ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded {
+				supervisor.ReportError(ctx, context.DeadlineExceeded)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package supervisor
+
+import "context"
+
+// AsSupervisable allows the Supervisor to be supervised by another, forming
+// a supervision tree. The Supervisor is run for as long as the parent's
+// context remains active, and then shut down gracefully; should it stop of
+// its own accord - such as by exhausting a restart budget - then this is
+// reported as a failure to the parent.
+func (s *Supervisor) AsSupervisable() Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		s.Run()
+		select {
+		case <-ctx.Done():
+			s.gracefulShutdown()
+		case <-s.Stopping():
+			s.Wait()
+			if cause := s.Cause(); cause != nil {
+				ReportError(ctx, cause)
+			}
+		}
+	}
+}
+
+// gracefulShutdown performs a Shutdown, bounded by the Supervisor's
+// ShutdownTimeout, and waits for every worker to stop.
+func (s *Supervisor) gracefulShutdown() error {
+	s.mu.Lock()
+	timeout := s.shutdownTimeout
+	s.mu.Unlock()
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := s.Shutdown(ctx)
+	s.Wait()
+	return err
+}
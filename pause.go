@@ -0,0 +1,54 @@
+package supervisor
+
+import "context"
+
+// Pause cancels the current run of every worker, and prevents any of them
+// from being started again until Resume is called. Unlike Stop, a paused
+// Supervisor is still considered to be running.
+func (s *Supervisor) Pause() {
+	s.mu.Lock()
+	if s.runHooks().resume == nil {
+		s.updateHooksLocked(func(h *runHooks) {
+			h.resume = make(chan struct{})
+		})
+	}
+	workers := append([]*worker{}, s.workers...)
+	s.mu.Unlock()
+
+	for _, w := range workers {
+		w.restart()
+	}
+}
+
+// Resume allows the workers of a paused Supervisor to start again.
+func (s *Supervisor) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resume := s.runHooks().resume; resume != nil {
+		close(resume)
+		s.updateHooksLocked(func(h *runHooks) {
+			h.resume = nil
+		})
+	}
+}
+
+// IsPaused returns whether the Supervisor has been paused.
+func (s *Supervisor) IsPaused() bool {
+	return s.runHooks().resume != nil
+}
+
+// awaitResume blocks whilst the Supervisor is paused, returning false if the
+// context is cancelled first.
+func (h *runHooks) awaitResume(ctx context.Context) bool {
+	if h.resume == nil {
+		return true
+	}
+
+	select {
+	case <-h.resume:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
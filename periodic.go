@@ -0,0 +1,51 @@
+package supervisor
+
+import (
+	"context"
+	"time"
+)
+
+// Periodic returns a Supervisable which runs fn every interval, starting
+// one interval after the Supervisable is started. Runs are scheduled
+// against absolute times rather than the completion of the previous run, so
+// slow runs don't cause the schedule to drift; use AnchorAt to also keep
+// the schedule fixed across restarts. Runs which become due whilst a
+// previous run is in progress are handled according to OnOverlap, and each
+// run is bounded by any JobTimeout.
+//
+//	s, _ := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+//		Specs: []supervisor.WorkerSpec{{
+//			Name:   "flush",
+//			Worker: supervisor.Periodic(time.Minute, flush, supervisor.OnOverlap(supervisor.QueueOverlap)),
+//		}},
+//	})
+//
+// Periodic panics if interval isn't positive.
+func Periodic(interval time.Duration, fn Job, opts ...JobOption) Supervisable {
+	if interval <= 0 {
+		panic("supervisor: non-positive interval for Periodic")
+	}
+
+	r := newJobRunner(fn, "every "+interval.String(), opts)
+	return func(ctx context.Context, done chan struct{}) {
+		anchor := r.opts.anchor
+		if anchor.IsZero() {
+			anchor = ClockFrom(ctx).Now()
+		}
+
+		r.run(func(now time.Time) time.Time {
+			return nextTick(anchor, interval, now)
+		})(ctx, done)
+	}
+}
+
+// nextTick returns the first time after now which falls on anchor plus a
+// multiple of interval; ticks which have already been missed are skipped.
+func nextTick(anchor time.Time, interval time.Duration, now time.Time) time.Time {
+	if now.Before(anchor) {
+		return anchor
+	}
+
+	elapsed := now.Sub(anchor)
+	return anchor.Add((elapsed/interval + 1) * interval)
+}
@@ -0,0 +1,73 @@
+package supervisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_NextTickMustNotDrift(t *testing.T) {
+	anchor := time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[time.Duration]time.Duration{
+		-time.Minute:                        0,
+		0:                                   time.Minute,
+		time.Second * 30:                    time.Minute,
+		time.Minute:                         2 * time.Minute,
+		time.Minute*5 + time.Millisecond*10: 6 * time.Minute,
+	}
+
+	for offset, expected := range cases {
+		if next := nextTick(anchor, time.Minute, anchor.Add(offset)); !next.Equal(anchor.Add(expected)) {
+			t.Errorf("%s after anchor: expected %s, got %s", offset, anchor.Add(expected), next)
+		}
+	}
+}
+
+func Test_PeriodicMustRunOnInterval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var runs int32
+	worker := Periodic(time.Millisecond*30, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go worker(ctx, done)
+
+	<-time.After(time.Millisecond * 100)
+	cancel()
+	<-done
+
+	if n := atomic.LoadInt32(&runs); n != 3 {
+		t.Error("expected a run every interval", n)
+	}
+}
+
+func Test_PeriodicMustSkipOverlappingRuns(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var runs int32
+	worker := Periodic(time.Millisecond*20, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		<-time.After(time.Millisecond * 50)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go worker(ctx, done)
+
+	<-time.After(time.Millisecond * 90)
+	cancel()
+	<-done
+
+	if n := atomic.LoadInt32(&runs); n != 2 {
+		t.Error("expected runs to be skipped whilst another was in progress", n)
+	}
+}
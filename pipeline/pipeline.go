@@ -0,0 +1,373 @@
+// Package pipeline builds supervised pipelines, in which each stage is run
+// as a worker of a Supervisor.
+//
+// Unlike wiring stages together by hand, the pipeline owns the channels
+// between its stages: a stage which panics, or fails, is restarted upon the
+// same channels, so neither its upstream nor downstream siblings are ever
+// left reading from - or writing to - a stage which no longer exists.
+//
+//	p, err := pipeline.New().
+//		Name("ingest").
+//		Stage(parse).
+//		Stage(enrich).
+//		Build(s)
+//
+//	p.In() <- raw
+//	result := <-p.Out()
+//
+// A stage may be fanned out to multiple instances with FanOut; each
+// instance is supervised - and restarted - independently of its siblings,
+// and their results are merged back onto the input of the following stage.
+//
+// By default the channels between stages are unbuffered, so a slow stage
+// blocks those upstream of it. Buffer bounds the channels instead, and
+// determines whether a stage blocks or sheds items once the buffer of the
+// stage after it is full; the depth of each buffer is available from Stats,
+// and from the Supervisor as the QueueDepth of each stage's workers.
+//
+// Delivery is at-most-once by default: an item held by an instance which
+// fails is lost. With AtLeastOnce the pipeline tracks the item each instance
+// holds, redelivering it to the next instance of the stage to start.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrSkip may be returned by a Func to drop the item it was given, without
+// the stage being considered to have failed.
+var ErrSkip = errors.New("pipeline: skip item")
+
+// ErrNoStages is returned by Build when the pipeline has no stages.
+var ErrNoStages = errors.New("pipeline: no stages")
+
+// Func processes a single item, returning the item to pass to the next
+// stage. Returning an error - other than ErrSkip - fails the stage, which
+// is then restarted according to the pipeline's RestartPolicy.
+type Func func(ctx context.Context, item interface{}) (interface{}, error)
+
+// Overflow determines what a stage does with its result when the buffer
+// of the following stage is full.
+type Overflow int
+
+const (
+	// Block waits for space in the buffer, propagating backpressure to the
+	// stages upstream.
+	Block Overflow = iota
+	// Shed drops the result, which is counted in the stage's StageInfo.
+	Shed
+)
+
+// Builder describes a pipeline prior to it being built.
+type Builder struct {
+	name        string
+	policy      supervisor.RestartPolicy
+	stages      []stageSpec
+	size        int
+	overflow    Overflow
+	atLeastOnce bool
+}
+
+// stageSpec describes a stage prior to the pipeline being built.
+type stageSpec struct {
+	fn    Func
+	count int
+}
+
+// New returns a Builder for an empty pipeline named "pipeline".
+func New() *Builder {
+	return &Builder{name: "pipeline"}
+}
+
+// Name names the pipeline; it's used as the name of the pipeline's group
+// within the Supervisor, and as the prefix for the names of its stages.
+func (b *Builder) Name(name string) *Builder {
+	b.name = name
+	return b
+}
+
+// Policy sets the RestartPolicy of the pipeline's group.
+func (b *Builder) Policy(policy supervisor.RestartPolicy) *Builder {
+	b.policy = policy
+	return b
+}
+
+// Buffer bounds every channel of the pipeline - including those returned by
+// In and Out - to size items, with overflow determining what each stage does
+// when the next is saturated. Items sent to In always block whilst its
+// buffer is full.
+//
+// Note that with Shed, and a size of zero, results are dropped whenever the
+// next stage isn't waiting for an item.
+func (b *Builder) Buffer(size int, overflow Overflow) *Builder {
+	b.size = size
+	b.overflow = overflow
+	return b
+}
+
+// AtLeastOnce enables at-least-once delivery, in which an item held by an
+// instance which fails - including a result it's waiting to send - is
+// redelivered to the next instance of the stage to start, typically the
+// restarted instance. Stages must therefore tolerate processing an item
+// more than once; an item which always causes a failure should be dropped
+// by returning ErrSkip, lest it exhaust the pipeline's restart budget.
+func (b *Builder) AtLeastOnce() *Builder {
+	b.atLeastOnce = true
+	return b
+}
+
+// Stage appends a stage to the pipeline.
+func (b *Builder) Stage(fn Func) *Builder {
+	return b.FanOut(1, fn)
+}
+
+// FanOut appends a stage to the pipeline which is run by n instances, each
+// of which reads items from the previous stage; their results are merged,
+// in the order they complete, onto the input of the next stage. Values of
+// n below 1 are treated as a single instance.
+//
+// Instances are supervised as a single worker with a Count of n, so with
+// the default OneForOne Strategy the failure of an instance - along with
+// the loss of the item it was processing - doesn't affect its siblings.
+func (b *Builder) FanOut(n int, fn Func) *Builder {
+	b.stages = append(b.stages, stageSpec{fn: fn, count: n})
+	return b
+}
+
+// Build adds the pipeline's stages to the Supervisor as a group, starting
+// them should the Supervisor be running.
+func (b *Builder) Build(s *supervisor.Supervisor) (*Pipeline, error) {
+	if len(b.stages) == 0 {
+		return nil, ErrNoStages
+	}
+
+	p := &Pipeline{in: make(chan interface{}, b.size)}
+	specs := make([]supervisor.WorkerSpec, len(b.stages))
+
+	in := p.in
+	for i, spec := range b.stages {
+		st := &stage{
+			name:     fmt.Sprintf("%s-stage-%d", b.name, i),
+			fn:       spec.fn,
+			in:       in,
+			out:      make(chan interface{}, b.size),
+			overflow: b.overflow,
+			replay:   b.atLeastOnce,
+		}
+
+		specs[i] = supervisor.WorkerSpec{Name: st.name, Worker: st.run, Count: spec.count}
+		p.stages = append(p.stages, st)
+		in = st.out
+	}
+	p.out = in
+
+	err := s.AddGroup(supervisor.Group{Name: b.name, Workers: specs, Policy: b.policy})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Pipeline is a running pipeline.
+type Pipeline struct {
+	in     chan interface{}
+	out    chan interface{}
+	stages []*stage
+}
+
+// StageInfo contains the statistics for a stage of a Pipeline.
+type StageInfo struct {
+	// Name is the name of the stage's worker.
+	Name string
+	// Depth is the number of items buffered for the stage.
+	Depth int
+	// Capacity is the size of the stage's buffer.
+	Capacity int
+	// Shed is the number of results dropped by the stage, due to the
+	// following stage being saturated.
+	Shed int
+	// Pending is the number of items awaiting redelivery; see AtLeastOnce.
+	Pending int
+	// Redelivered is the number of items which have been redelivered.
+	Redelivered int
+}
+
+// In returns the channel items are sent to the pipeline on. Closing it
+// closes the pipeline, with Out being closed once every item has passed
+// through.
+func (p *Pipeline) In() chan<- interface{} {
+	return p.in
+}
+
+// Out returns the channel on which items leave the pipeline.
+func (p *Pipeline) Out() <-chan interface{} {
+	return p.out
+}
+
+// Stats returns the statistics for each stage of the pipeline, in order.
+func (p *Pipeline) Stats() []StageInfo {
+	infos := make([]StageInfo, len(p.stages))
+	for i, st := range p.stages {
+		infos[i] = st.stats()
+	}
+
+	return infos
+}
+
+// stage is the runtime state of a single stage, which persists across
+// restarts of its instances.
+type stage struct {
+	name     string
+	fn       Func
+	in       chan interface{}
+	out      chan interface{}
+	overflow Overflow
+	replay   bool
+
+	// The output is closed once the input has been drained and no instance
+	// remains which could be holding an item, nor are any items pending
+	// redelivery; instances which start after that point can only observe
+	// the closed input.
+	mu          sync.Mutex
+	active      int
+	drained     bool
+	closed      bool
+	shed        int
+	pending     []interface{}
+	redelivered int
+}
+
+func (st *stage) run(ctx context.Context, done chan struct{}) {
+	defer supervisor.Recover(ctx, done)
+
+	st.enter()
+	if st.process(ctx) {
+		<-ctx.Done()
+	}
+}
+
+func (st *stage) enter() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.active++
+}
+
+// exit records an instance leaving the stage, along with any item it held,
+// closing the output should the input have been drained and every instance
+// have finished.
+func (st *stage) exit(drained bool, held interface{}, holding bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.active--
+	if holding && st.replay {
+		st.pending = append(st.pending, held)
+	}
+
+	st.drained = st.drained || drained
+	if st.drained && st.active == 0 && len(st.pending) == 0 && !st.closed {
+		st.closed = true
+		close(st.out)
+	}
+}
+
+// process passes items from the input to the output until the context is
+// cancelled, the stage fails, or the input is closed; it returns whether the
+// input was closed.
+func (st *stage) process(ctx context.Context) (drained bool) {
+	var held interface{}
+	holding := false
+	defer func() { st.exit(drained, held, holding) }()
+
+	supervisor.ObserveQueue(ctx, st.depth)
+	supervisor.Ready(ctx)
+	for {
+		item, ok := st.redeliver()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return false
+			case item, ok = <-st.in:
+				if !ok {
+					return true
+				}
+			}
+		}
+
+		held, holding = item, true
+		result, err := st.fn(ctx, item)
+		if err != nil && !errors.Is(err, ErrSkip) {
+			supervisor.ReportError(ctx, err)
+			return false
+		}
+
+		if err == nil && !st.send(ctx, result) {
+			return false
+		}
+
+		held, holding = nil, false
+	}
+}
+
+// redeliver returns the next item pending redelivery, if any.
+func (st *stage) redeliver() (interface{}, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if len(st.pending) == 0 {
+		return nil, false
+	}
+
+	item := st.pending[0]
+	st.pending = st.pending[1:]
+	st.redelivered++
+	return item, true
+}
+
+// send passes a result to the next stage, subject to the Overflow policy;
+// it returns false should the context be cancelled.
+func (st *stage) send(ctx context.Context, result interface{}) bool {
+	if st.overflow == Shed {
+		select {
+		case st.out <- result:
+		default:
+			st.mu.Lock()
+			st.shed++
+			st.mu.Unlock()
+		}
+
+		return true
+	}
+
+	select {
+	case st.out <- result:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (st *stage) depth() (int, int) {
+	return len(st.in), cap(st.in)
+}
+
+func (st *stage) stats() StageInfo {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return StageInfo{
+		Name:        st.name,
+		Depth:       len(st.in),
+		Capacity:    cap(st.in),
+		Shed:        st.shed,
+		Pending:     len(st.pending),
+		Redelivered: st.redelivered,
+	}
+}
@@ -0,0 +1,330 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func double(ctx context.Context, item interface{}) (interface{}, error) {
+	return item.(int) * 2, nil
+}
+
+func newSupervisor(t *testing.T) *supervisor.Supervisor {
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func receive(t *testing.T, p *Pipeline) interface{} {
+	select {
+	case item := <-p.Out():
+		return item
+	case <-time.After(time.Second):
+		t.Fatal("expected an item from the pipeline")
+		return nil
+	}
+}
+
+func Test_PipelineMustPassItemsThroughStages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	p, err := New().Stage(double).Stage(func(ctx context.Context, item interface{}) (interface{}, error) {
+		if item.(int) == 4 {
+			return nil, ErrSkip
+		}
+		return item.(int) + 1, nil
+	}).Build(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for _, item := range []int{1, 2, 3} {
+			p.In() <- item
+		}
+		close(p.In())
+	}()
+
+	if first, second := receive(t, p), receive(t, p); first != 3 || second != 7 {
+		t.Error("unexpected items from the pipeline", first, second)
+	}
+
+	if _, ok := <-p.Out(); ok {
+		t.Error("expected the output to be closed once the input was")
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PipelineMustRewireRestartedStages(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	var failures int32
+	p, err := New().Name("flaky").Stage(func(ctx context.Context, item interface{}) (interface{}, error) {
+		switch item {
+		case "panic":
+			atomic.AddInt32(&failures, 1)
+			panic("testing")
+		case "fail":
+			atomic.AddInt32(&failures, 1)
+			return nil, errors.New("testing")
+		}
+		return item, nil
+	}).Stage(func(ctx context.Context, item interface{}) (interface{}, error) {
+		return item.(string) + "!", nil
+	}).Build(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for _, item := range []string{"panic", "fail", "ok"} {
+			p.In() <- item
+		}
+	}()
+
+	if item := receive(t, p); item != "ok!" || atomic.LoadInt32(&failures) != 2 {
+		t.Error("expected the restarted stage to continue reading the pipeline", item)
+	}
+
+	if workers := s.WorkerInfo("flaky-stage-0"); len(workers) != 1 || workers[0].Restarts != 2 {
+		t.Error("expected the failing stage to have been restarted", workers)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PipelineMustRequireStages(t *testing.T) {
+	s := newSupervisor(t)
+	if _, err := New().Build(s); !errors.Is(err, ErrNoStages) {
+		t.Error("expected a pipeline without stages to be rejected", err)
+	}
+
+	if _, err := New().Stage(double).Build(s); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New().Stage(double).Build(s); !errors.Is(err, supervisor.ErrDuplicateName) {
+		t.Error("expected a second pipeline with the same name to be rejected", err)
+	}
+}
+
+func Test_FanOutMustProcessConcurrentlyAndMerge(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	var concurrent, peak, failed int32
+	p, err := New().Name("fan").FanOut(3, func(ctx context.Context, item interface{}) (interface{}, error) {
+		if item.(int) == 0 && atomic.CompareAndSwapInt32(&failed, 0, 1) {
+			panic("testing")
+		}
+
+		n := atomic.AddInt32(&concurrent, 1)
+		defer atomic.AddInt32(&concurrent, -1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+				break
+			}
+		}
+
+		<-time.After(time.Millisecond * 20)
+		return item.(int) * 2, nil
+	}).Stage(double).Build(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for i := 0; i < 10; i++ {
+			p.In() <- i
+		}
+		close(p.In())
+	}()
+
+	sum := 0
+	for item := range p.Out() {
+		sum += item.(int)
+	}
+
+	// The item given to the failing instance is lost.
+	if sum != 180 {
+		t.Error("expected every other item to be merged onto the output", sum)
+	}
+
+	if atomic.LoadInt32(&peak) != 3 {
+		t.Error("expected items to be processed by each instance concurrently", peak)
+	}
+
+	workers := s.WorkerInfo("fan-stage-0")
+	restarts := 0
+	for _, w := range workers {
+		restarts += w.Restarts
+	}
+
+	if len(workers) != 3 || restarts != 1 {
+		t.Error("expected only the failing instance to be restarted", workers)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_BufferMustPropagateBackpressure(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	gate := make(chan struct{})
+	p, err := New().Name("bp").Buffer(2, Block).Stage(double).Stage(func(ctx context.Context, item interface{}) (interface{}, error) {
+		<-gate
+		return item, nil
+	}).Build(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for i := 0; i < 10; i++ {
+			p.In() <- i
+		}
+		close(p.In())
+	}()
+
+	<-time.After(time.Millisecond * 50)
+	select {
+	case <-sent:
+		t.Error("expected the saturated pipeline to block its input")
+	default:
+	}
+
+	stats := p.Stats()
+	if len(stats) != 2 || stats[0].Depth != 2 || stats[1].Depth != 2 || stats[1].Capacity != 2 {
+		t.Error("expected every buffer to be full", stats)
+	}
+
+	if workers := s.WorkerInfo("bp-stage-1"); len(workers) != 1 || workers[0].QueueDepth != 2 || workers[0].QueueCapacity != 2 {
+		t.Error("expected the queue depth to be available from the supervisor", workers)
+	}
+
+	close(gate)
+	received := 0
+	for range p.Out() {
+		received++
+	}
+
+	if received != 10 {
+		t.Error("expected every item to pass through once the pipeline unblocked", received)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_BufferMustShedWhenSaturated(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	gate := make(chan struct{})
+	p, err := New().Buffer(1, Shed).Stage(double).Stage(func(ctx context.Context, item interface{}) (interface{}, error) {
+		<-gate
+		return item, nil
+	}).Build(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		p.In() <- i
+	}
+	close(p.In())
+
+	<-time.After(time.Millisecond * 50)
+	close(gate)
+
+	received := 0
+	for range p.Out() {
+		received++
+	}
+
+	shed := 0
+	for _, info := range p.Stats() {
+		shed += info.Shed
+	}
+
+	if shed < 3 || received+shed != 5 {
+		t.Error("expected items beyond the buffer to be shed", received, shed)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_AtLeastOnceMustRedeliverToRestartedStage(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	seen := map[interface{}]int{}
+	p, err := New().AtLeastOnce().Stage(func(ctx context.Context, item interface{}) (interface{}, error) {
+		seen[item]++
+		switch {
+		case item == 1 && seen[item] == 1:
+			panic("testing")
+		case item == 2 && seen[item] < 3:
+			return nil, errors.New("testing")
+		}
+		return item, nil
+	}).Stage(double).Build(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for i := 0; i < 4; i++ {
+			p.In() <- i
+		}
+		close(p.In())
+	}()
+
+	sum := 0
+	for item := range p.Out() {
+		sum += item.(int)
+	}
+
+	if sum != 12 {
+		t.Error("expected every item to be delivered despite failures", sum)
+	}
+
+	if stats := p.Stats(); stats[0].Redelivered != 3 || stats[0].Pending != 0 {
+		t.Error("expected the failed items to have been redelivered", stats)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,33 @@
+package pool
+
+// Future is the eventual result of a task submitted to a Pool. It's
+// resolved once the task has been handled - whether it returned, or
+// panicked and caused its worker to be restarted - or upon the task failing
+// to be submitted.
+type Future[R any] struct {
+	done   chan struct{}
+	result R
+	err    error
+}
+
+func newFuture[R any]() *Future[R] {
+	return &Future[R]{done: make(chan struct{})}
+}
+
+// Done returns a channel which is closed once the Future is resolved.
+func (f *Future[R]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result blocks until the Future is resolved, returning the task's result
+// and error. Should the task panic then the error wraps ErrPanicked; should
+// it fail to be submitted then the error is ErrFull or ErrClosed.
+func (f *Future[R]) Result() (R, error) {
+	<-f.done
+	return f.result, f.err
+}
+
+func (f *Future[R]) resolve(result R, err error) {
+	f.result, f.err = result, err
+	close(f.done)
+}
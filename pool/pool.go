@@ -0,0 +1,352 @@
+// Package pool provides a supervised worker pool, in which a fixed number
+// of workers handle tasks submitted to a bounded queue.
+//
+// Each worker is an instance of a worker within a Supervisor, so a task
+// which panics causes only the worker handling it to be restarted, subject
+// to the pool's RestartPolicy; the remaining workers continue to drain the
+// queue.
+//
+// By default the workers share a single queue, from which tasks are taken
+// in the order they were submitted; with Priorities they're instead taken in
+// order of the priority given to SubmitPriority. Alternatively, with
+// WorkStealing each worker has its own queue, taking tasks from those of
+// other workers once its own is empty; this reduces contention, and improves
+// tail latency when tasks vary greatly in duration.
+//
+// Submitting a task returns a Future, which is resolved with the task's
+// result once it has been handled.
+//
+//	p, err := pool.NewWithResults(s, 4, func(ctx context.Context, url string) ([]byte, error) {
+//		return fetch(ctx, url)
+//	}, pool.Name("fetchers"))
+//
+//	body, err := p.Submit(url).Result()
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+var (
+	// ErrClosed is returned when submitting a task to a closed Pool.
+	ErrClosed = errors.New("pool: closed")
+	// ErrFull is returned by TrySubmit when the queue is full.
+	ErrFull = errors.New("pool: queue full")
+	// ErrInvalidOptions is returned by New when given options which can't
+	// be used together.
+	ErrInvalidOptions = errors.New("pool: invalid options")
+	// ErrPanicked is wrapped by the error of a Future whose task panicked.
+	ErrPanicked = errors.New("pool: task panicked")
+)
+
+// Option configures a Pool.
+type Option func(*options)
+
+type options struct {
+	name      string
+	queueSize int
+	policy    supervisor.RestartPolicy
+	onError   func(error)
+	stealing  bool
+	priority  bool
+	aging     time.Duration
+}
+
+// Name names the Pool; it's used as the name of both the Pool's group and
+// its worker within the Supervisor. It defaults to "pool".
+func Name(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// QueueSize sets the number of tasks which may be queued before Submit
+// blocks; it defaults to the number of workers.
+func QueueSize(n int) Option {
+	return func(o *options) {
+		o.queueSize = n
+	}
+}
+
+// Policy sets the RestartPolicy of the Pool's group.
+func Policy(policy supervisor.RestartPolicy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// OnError is called with the error returned by any task which fails; unlike
+// a panic, a returned error doesn't cause the worker to be restarted.
+func OnError(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// WorkStealing gives each worker its own queue, with tasks being assigned to
+// the queues in turn; a worker whose queue is empty steals tasks from the
+// fullest queue of another worker. Tasks queued for a worker which is being
+// restarted are therefore handled by its siblings in the meantime.
+func WorkStealing() Option {
+	return func(o *options) {
+		o.stealing = true
+	}
+}
+
+// Priorities dispatches tasks in order of the priority they're submitted
+// with, highest first, rather than in the order they're submitted. To avoid
+// low priority tasks being starved by a steady stream of higher priority
+// tasks, a queued task's priority increases by one for every aging interval
+// it has waited; an aging interval of zero disables this.
+//
+// Priorities can't be used together with WorkStealing.
+func Priorities(aging time.Duration) Option {
+	return func(o *options) {
+		o.priority = true
+		o.aging = aging
+	}
+}
+
+// Stats contains the statistics for a Pool.
+type Stats struct {
+	// Queued is the number of tasks waiting to be handled.
+	Queued int
+	// Capacity is the size of the queue.
+	Capacity int
+	// Running is the number of tasks currently being handled.
+	Running int
+	// Completed is the number of tasks which were handled successfully.
+	Completed int
+	// Failed is the number of tasks which returned an error or panicked.
+	Failed int
+	// Stolen is the number of tasks which were stolen from the queue of
+	// another worker; see WorkStealing.
+	Stolen int
+}
+
+// Pool is a supervised pool of workers handling tasks of type T, each of
+// which produces a result of type R.
+type Pool[T, R any] struct {
+	handler func(context.Context, T) (R, error)
+	opts    options
+	queue   queue[job[T, R]]
+	closing chan struct{}
+	tasks   sync.WaitGroup
+
+	mu        sync.Mutex
+	closed    bool
+	running   int
+	completed int
+	failed    int
+}
+
+// job is a task along with the Future for its result.
+type job[T, R any] struct {
+	task   T
+	future *Future[R]
+}
+
+// New adds a Pool of n workers to the Supervisor, each of which calls the
+// handler with the tasks submitted to the Pool. The workers are started
+// should the Supervisor be running; values of n below 1 are treated as a
+// single worker.
+func New[T any](s *supervisor.Supervisor, n int, handler func(context.Context, T) error, opts ...Option) (*Pool[T, struct{}], error) {
+	return NewWithResults(s, n, func(ctx context.Context, task T) (struct{}, error) {
+		return struct{}{}, handler(ctx, task)
+	}, opts...)
+}
+
+// NewWithResults adds a Pool to the Supervisor as New does, but with a
+// handler which produces a result for each task; the result is available
+// from the Future returned upon submitting the task.
+func NewWithResults[T, R any](s *supervisor.Supervisor, n int, handler func(context.Context, T) (R, error), opts ...Option) (*Pool[T, R], error) {
+	if n < 1 {
+		n = 1
+	}
+
+	o := options{name: "pool", queueSize: n}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.stealing && o.priority {
+		return nil, fmt.Errorf("%w: priorities can't be used with work stealing", ErrInvalidOptions)
+	}
+
+	p := &Pool[T, R]{
+		handler: handler,
+		opts:    o,
+		queue:   newChanQueue[job[T, R]](o.queueSize),
+		closing: make(chan struct{}),
+	}
+
+	switch {
+	case o.stealing:
+		p.queue = newStealQueue[job[T, R]](n, o.queueSize)
+	case o.priority:
+		p.queue = newPriorityQueue[job[T, R]](s.Clock(), o.aging, o.queueSize)
+	}
+
+	err := s.AddGroup(supervisor.Group{
+		Name:    o.name,
+		Workers: []supervisor.WorkerSpec{{Name: o.name, Worker: p.work, Count: n}},
+		Policy:  o.policy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Submit queues a task, blocking whilst the queue is full, and returns the
+// Future for its result; should the Pool be closed the Future is resolved
+// with ErrClosed.
+func (p *Pool[T, R]) Submit(task T) *Future[R] {
+	return p.submit(task, 0, true)
+}
+
+// TrySubmit queues a task without blocking; should the queue be full the
+// Future is resolved with ErrFull, or ErrClosed should the Pool be closed.
+func (p *Pool[T, R]) TrySubmit(task T) *Future[R] {
+	return p.submit(task, 0, false)
+}
+
+// SubmitPriority queues a task with the given priority, blocking whilst the
+// queue is full; Submit queues tasks with a priority of zero. The priority
+// is ignored unless the Pool was created with Priorities.
+func (p *Pool[T, R]) SubmitPriority(task T, priority int) *Future[R] {
+	return p.submit(task, priority, true)
+}
+
+func (p *Pool[T, R]) submit(task T, priority int, block bool) *Future[R] {
+	future := newFuture[R]()
+	if err := p.accept(); err != nil {
+		future.resolve(*new(R), err)
+		return future
+	}
+
+	if err := p.queue.put(job[T, R]{task: task, future: future}, priority, block, p.closing); err != nil {
+		p.tasks.Done()
+		future.resolve(*new(R), err)
+	}
+
+	return future
+}
+
+func (p *Pool[T, R]) accept() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	p.tasks.Add(1)
+	return nil
+}
+
+// Close stops the Pool from accepting further tasks; tasks already queued
+// are still handled.
+func (p *Pool[T, R]) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+	}
+}
+
+// Wait blocks until every submitted task has been handled.
+func (p *Pool[T, R]) Wait() {
+	p.tasks.Wait()
+}
+
+// Stats returns the statistics for the Pool.
+func (p *Pool[T, R]) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	queued, capacity := p.queue.depth()
+	return Stats{
+		Queued:    queued,
+		Capacity:  capacity,
+		Running:   p.running,
+		Completed: p.completed,
+		Failed:    p.failed,
+		Stolen:    p.queue.stolen(),
+	}
+}
+
+func (p *Pool[T, R]) work(ctx context.Context, done chan struct{}) {
+	defer supervisor.Recover(ctx, done)
+
+	slot := p.queue.claim()
+	defer p.queue.release(slot)
+
+	supervisor.ObserveQueue(ctx, p.queue.depth)
+	supervisor.Ready(ctx)
+	for {
+		next, ok := p.queue.take(ctx, slot)
+		if !ok {
+			return
+		}
+
+		p.handle(ctx, next)
+	}
+}
+
+// handle calls the handler for a single task; should it panic, the failure
+// is recorded and the Future resolved before the panic continues on to
+// restart the worker.
+func (p *Pool[T, R]) handle(ctx context.Context, j job[T, R]) {
+	p.started()
+
+	var (
+		result R
+		err    error
+	)
+
+	defer func() {
+		if reason := recover(); reason != nil {
+			p.finished(j.future, result, fmt.Errorf("%w: %v", ErrPanicked, reason))
+			panic(reason)
+		}
+
+		p.finished(j.future, result, err)
+	}()
+
+	result, err = p.handler(ctx, j.task)
+}
+
+func (p *Pool[T, R]) started() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running++
+}
+
+func (p *Pool[T, R]) finished(future *Future[R], result R, err error) {
+	p.mu.Lock()
+	p.running--
+	if err != nil {
+		p.failed++
+	} else {
+		p.completed++
+	}
+	p.mu.Unlock()
+
+	if err != nil && p.opts.onError != nil {
+		p.opts.onError(err)
+	}
+
+	future.resolve(result, err)
+	p.tasks.Done()
+}
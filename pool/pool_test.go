@@ -0,0 +1,264 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func newSupervisor(t *testing.T) *supervisor.Supervisor {
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func Test_PoolMustHandleSubmittedTasks(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	var sum int32
+	p, err := New(s, 3, func(ctx context.Context, n int32) error {
+		atomic.AddInt32(&sum, n)
+		return nil
+	}, Name("adders"), QueueSize(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-time.After(time.Millisecond * 20)
+
+	for i := int32(1); i <= 10; i++ {
+		p.Submit(i)
+	}
+	p.Wait()
+
+	if atomic.LoadInt32(&sum) != 55 {
+		t.Error("expected every task to be handled", sum)
+	}
+
+	if stats := p.Stats(); stats.Completed != 10 || stats.Capacity != 5 {
+		t.Error("unexpected statistics", stats)
+	}
+
+	if workers := s.WorkerInfo("adders"); len(workers) != 3 || workers[0].QueueCapacity != 5 {
+		t.Error("expected the pool's workers to be supervised", workers)
+	}
+
+	p.Close()
+	if _, err := p.Submit(1).Result(); !errors.Is(err, ErrClosed) {
+		t.Error("expected a closed pool to reject tasks", err)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PoolMustRestartWorkersWhichPanic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	var errs int32
+	p, err := New(s, 2, func(ctx context.Context, task string) error {
+		switch task {
+		case "panic":
+			panic("testing")
+		case "fail":
+			return errors.New("testing")
+		}
+		return nil
+	}, OnError(func(error) { atomic.AddInt32(&errs, 1) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, task := range []string{"panic", "fail", "ok", "ok"} {
+		p.Submit(task)
+	}
+	p.Wait()
+	<-time.After(time.Millisecond * 50)
+
+	if stats := p.Stats(); stats.Completed != 2 || stats.Failed != 2 || atomic.LoadInt32(&errs) != 2 {
+		t.Error("expected failures to be recorded", stats, errs)
+	}
+
+	restarts := 0
+	for _, w := range s.WorkerInfo("pool") {
+		restarts += w.Restarts
+	}
+
+	if restarts != 1 {
+		t.Error("expected only the panicking worker to be restarted", restarts)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_TrySubmitMustNotBlock(t *testing.T) {
+	s := newSupervisor(t)
+	p, err := New(s, 1, func(ctx context.Context, task int) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-p.TrySubmit(1).Done():
+		t.Error("expected the task to be queued")
+	default:
+	}
+
+	if _, err := p.TrySubmit(2).Result(); !errors.Is(err, ErrFull) {
+		t.Error("expected a full queue to reject the task", err)
+	}
+}
+
+func Test_WorkStealingMustRebalanceQueuedTasks(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	gate := make(chan struct{})
+	var handled int32
+	p, err := New(s, 2, func(ctx context.Context, task int) error {
+		if task == 0 {
+			<-gate
+		}
+		atomic.AddInt32(&handled, 1)
+		return nil
+	}, WorkStealing(), QueueSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-time.After(time.Millisecond * 20)
+
+	for i := 0; i < 10; i++ {
+		p.Submit(i)
+	}
+
+	deadline := time.After(time.Second)
+	for atomic.LoadInt32(&handled) != 9 {
+		select {
+		case <-deadline:
+			t.Fatal("expected the idle worker to handle tasks queued for the blocked worker", handled)
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	if stats := p.Stats(); stats.Stolen == 0 || stats.Running != 1 {
+		t.Error("expected tasks to have been stolen", stats)
+	}
+
+	close(gate)
+	p.Wait()
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PrioritiesMustDispatchHighestFirst(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	gate := make(chan struct{})
+	order := make(chan string, 10)
+	p, err := New(s, 1, func(ctx context.Context, task string) error {
+		if task == "blocking" {
+			<-gate
+		}
+		order <- task
+		return nil
+	}, Priorities(time.Millisecond*10), QueueSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Submit("blocking")
+	<-time.After(time.Millisecond * 20)
+
+	p.SubmitPriority("starved", 0)
+	<-time.After(time.Millisecond * 60)
+	p.SubmitPriority("low", 1)
+	p.SubmitPriority("high", 10)
+	p.SubmitPriority("medium", 3)
+
+	close(gate)
+	p.Wait()
+	close(order)
+
+	expected := []string{"blocking", "high", "starved", "medium", "low"}
+	i := 0
+	for task := range order {
+		if task != expected[i] {
+			t.Errorf("expected task %d to be %s, got %s", i, expected[i], task)
+		}
+		i++
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PoolMustRejectConflictingOptions(t *testing.T) {
+	s := newSupervisor(t)
+	_, err := New(s, 2, func(ctx context.Context, task int) error { return nil }, WorkStealing(), Priorities(0))
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Error("expected work stealing and priorities to be rejected together", err)
+	}
+}
+
+func Test_FutureMustResolveWithResult(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newSupervisor(t)
+	s.Run()
+
+	p, err := NewWithResults(s, 1, func(ctx context.Context, n int) (string, error) {
+		switch n {
+		case 0:
+			panic("testing")
+		case 1:
+			return "", errors.New("testing")
+		}
+		return strings.Repeat("a", n), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	panicked, failed, succeeded := p.Submit(0), p.Submit(1), p.Submit(3)
+	if _, err := panicked.Result(); !errors.Is(err, ErrPanicked) {
+		t.Error("expected the panic to resolve the future", err)
+	}
+
+	if _, err := failed.Result(); err == nil || errors.Is(err, ErrPanicked) {
+		t.Error("expected the task's error", err)
+	}
+
+	select {
+	case <-succeeded.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the task to be handled by the restarted worker")
+	}
+
+	if result, err := succeeded.Result(); result != "aaa" || err != nil {
+		t.Error("expected the task's result", result, err)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
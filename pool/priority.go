@@ -0,0 +1,131 @@
+package pool
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// priorityQueue dispatches tasks in order of priority. To prevent the
+// starvation of low priority tasks, a task's priority increases by one for
+// each aging interval it spends queued; as every queued task ages at the
+// same rate, the order of two tasks never changes once they're both queued,
+// and so can be determined upon submission.
+type priorityQueue[T any] struct {
+	clock   supervisor.Clock
+	aging   time.Duration
+	created time.Time
+	space   chan struct{}
+	ready   chan struct{}
+
+	mu    sync.Mutex
+	tasks priorityHeap[T]
+	seq   uint64
+}
+
+type prioritised[T any] struct {
+	task T
+	rank float64
+	seq  uint64
+}
+
+func newPriorityQueue[T any](clock supervisor.Clock, aging time.Duration, size int) *priorityQueue[T] {
+	if size < 1 {
+		size = 1
+	}
+
+	return &priorityQueue[T]{
+		clock:   clock,
+		aging:   aging,
+		created: clock.Now(),
+		space:   make(chan struct{}, size),
+		ready:   make(chan struct{}, size),
+	}
+}
+
+func (q *priorityQueue[T]) put(task T, priority int, block bool, closing <-chan struct{}) error {
+	if err := acquire(q.space, block, closing); err != nil {
+		return err
+	}
+
+	// A task submitted later must wait an additional aging interval for
+	// each unit of priority it would otherwise gain.
+	rank := float64(priority)
+	if q.aging > 0 {
+		rank -= float64(q.clock.Now().Sub(q.created)) / float64(q.aging)
+	}
+
+	q.mu.Lock()
+	q.seq++
+	heap.Push(&q.tasks, prioritised[T]{task: task, rank: rank, seq: q.seq})
+	q.mu.Unlock()
+
+	q.ready <- struct{}{}
+	return nil
+}
+
+func (q *priorityQueue[T]) take(ctx context.Context, slot int) (T, bool) {
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	case <-q.ready:
+	}
+
+	q.mu.Lock()
+	next := heap.Pop(&q.tasks).(prioritised[T])
+	q.mu.Unlock()
+
+	<-q.space
+	return next.task, true
+}
+
+func (q *priorityQueue[T]) claim() int {
+	return 0
+}
+
+func (q *priorityQueue[T]) release(slot int) {}
+
+func (q *priorityQueue[T]) depth() (int, int) {
+	return len(q.ready), cap(q.space)
+}
+
+func (q *priorityQueue[T]) stolen() int {
+	return 0
+}
+
+// priorityHeap implements heap.Interface, ordering tasks by rank and then
+// by the order in which they were submitted.
+type priorityHeap[T any] []prioritised[T]
+
+func (h priorityHeap[T]) Len() int {
+	return len(h)
+}
+
+func (h priorityHeap[T]) Less(i, j int) bool {
+	if h[i].rank != h[j].rank {
+		return h[i].rank > h[j].rank
+	}
+
+	return h[i].seq < h[j].seq
+}
+
+func (h priorityHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *priorityHeap[T]) Push(x interface{}) {
+	*h = append(*h, x.(prioritised[T]))
+}
+
+func (h *priorityHeap[T]) Pop() interface{} {
+	old := *h
+	last := len(old) - 1
+	item := old[last]
+	old[last] = prioritised[T]{}
+	*h = old[:last]
+	return item
+}
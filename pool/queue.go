@@ -0,0 +1,270 @@
+package pool
+
+import (
+	"context"
+	"sync"
+)
+
+// queue holds the tasks submitted to a Pool until a worker takes them.
+type queue[T any] interface {
+	// put adds a task, blocking whilst the queue is full if block is set;
+	// it returns ErrFull or ErrClosed should the task not be added. The
+	// priority is ignored by queues which don't support it.
+	put(task T, priority int, block bool, closing <-chan struct{}) error
+	// take removes the next task for the worker holding the given slot,
+	// blocking until one is available or the context is cancelled.
+	take(ctx context.Context, slot int) (T, bool)
+	// claim reserves a slot for a worker, which is released upon the
+	// worker exiting.
+	claim() int
+	release(slot int)
+	depth() (int, int)
+	stolen() int
+}
+
+// chanQueue is a single queue shared by every worker.
+type chanQueue[T any] struct {
+	tasks chan T
+}
+
+func newChanQueue[T any](size int) *chanQueue[T] {
+	return &chanQueue[T]{tasks: make(chan T, size)}
+}
+
+func (q *chanQueue[T]) put(task T, priority int, block bool, closing <-chan struct{}) error {
+	if !block {
+		select {
+		case q.tasks <- task:
+			return nil
+		default:
+			return ErrFull
+		}
+	}
+
+	select {
+	case q.tasks <- task:
+		return nil
+	case <-closing:
+		return ErrClosed
+	}
+}
+
+func (q *chanQueue[T]) take(ctx context.Context, slot int) (T, bool) {
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	case task := <-q.tasks:
+		return task, true
+	}
+}
+
+func (q *chanQueue[T]) claim() int {
+	return 0
+}
+
+func (q *chanQueue[T]) release(slot int) {}
+
+func (q *chanQueue[T]) depth() (int, int) {
+	return len(q.tasks), cap(q.tasks)
+}
+
+func (q *chanQueue[T]) stolen() int {
+	return 0
+}
+
+// stealQueue gives each worker its own deque, to which tasks are assigned
+// in turn; a worker takes from the front of its own deque, and once that's
+// empty steals from the back of the fullest deque of another worker.
+//
+// Capacity is enforced by the space channel, whilst each token in the ready
+// channel represents a task present in one of the deques; a worker holding
+// a token is therefore guaranteed to find a task.
+type stealQueue[T any] struct {
+	deques []*deque[T]
+	space  chan struct{}
+	ready  chan struct{}
+
+	mu      sync.Mutex
+	next    int
+	claimed []bool
+	steals  int
+}
+
+type deque[T any] struct {
+	mu    sync.Mutex
+	tasks []T
+}
+
+func newStealQueue[T any](workers, size int) *stealQueue[T] {
+	if size < 1 {
+		size = 1
+	}
+
+	q := &stealQueue[T]{
+		deques:  make([]*deque[T], workers),
+		space:   make(chan struct{}, size),
+		ready:   make(chan struct{}, size),
+		claimed: make([]bool, workers),
+	}
+
+	for i := range q.deques {
+		q.deques[i] = &deque[T]{}
+	}
+
+	return q
+}
+
+func (q *stealQueue[T]) put(task T, priority int, block bool, closing <-chan struct{}) error {
+	if err := acquire(q.space, block, closing); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	d := q.deques[q.next]
+	q.next = (q.next + 1) % len(q.deques)
+	q.mu.Unlock()
+
+	d.mu.Lock()
+	d.tasks = append(d.tasks, task)
+	d.mu.Unlock()
+
+	q.ready <- struct{}{}
+	return nil
+}
+
+func (q *stealQueue[T]) take(ctx context.Context, slot int) (T, bool) {
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	case <-q.ready:
+	}
+
+	for {
+		if task, ok := q.deques[slot].popFront(); ok {
+			<-q.space
+			return task, true
+		}
+
+		if task, ok := q.steal(slot); ok {
+			<-q.space
+			return task, true
+		}
+	}
+}
+
+// steal takes a task from the back of the fullest deque other than the
+// worker's own.
+func (q *stealQueue[T]) steal(slot int) (T, bool) {
+	victim, most := -1, 0
+	for i, d := range q.deques {
+		if n := d.len(); i != slot && n > most {
+			victim, most = i, n
+		}
+	}
+
+	if victim < 0 {
+		var zero T
+		return zero, false
+	}
+
+	task, ok := q.deques[victim].popBack()
+	if ok {
+		q.mu.Lock()
+		q.steals++
+		q.mu.Unlock()
+	}
+
+	return task, ok
+}
+
+func (q *stealQueue[T]) claim() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, claimed := range q.claimed {
+		if !claimed {
+			q.claimed[i] = true
+			return i
+		}
+	}
+
+	return 0
+}
+
+func (q *stealQueue[T]) release(slot int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.claimed[slot] = false
+}
+
+func (q *stealQueue[T]) depth() (int, int) {
+	return len(q.ready), cap(q.space)
+}
+
+func (q *stealQueue[T]) stolen() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.steals
+}
+
+func (d *deque[T]) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.tasks)
+}
+
+func (d *deque[T]) popFront() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zero T
+	if len(d.tasks) == 0 {
+		return zero, false
+	}
+
+	task := d.tasks[0]
+	d.tasks[0] = zero
+	d.tasks = d.tasks[1:]
+	return task, true
+}
+
+func (d *deque[T]) popBack() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var zero T
+	if len(d.tasks) == 0 {
+		return zero, false
+	}
+
+	last := len(d.tasks) - 1
+	task := d.tasks[last]
+	d.tasks[last] = zero
+	d.tasks = d.tasks[:last]
+	return task, true
+}
+
+// acquire takes a token from the space channel, which bounds the number of
+// queued tasks.
+func acquire(space chan struct{}, block bool, closing <-chan struct{}) error {
+	if !block {
+		select {
+		case space <- struct{}{}:
+			return nil
+		default:
+			return ErrFull
+		}
+	}
+
+	select {
+	case space <- struct{}{}:
+		return nil
+	case <-closing:
+		return ErrClosed
+	}
+}
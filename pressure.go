@@ -0,0 +1,169 @@
+package supervisor
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// DefaultPressureInterval is the minimum time between samples of the
+// process's pressure when no interval is specified.
+const DefaultPressureInterval = time.Second
+
+// DefaultPressureBackoff is the additional delay applied to restarts whilst
+// the process is under pressure, when no Backoff is specified.
+var DefaultPressureBackoff = Backoff{Initial: 100 * time.Millisecond, Max: 10 * time.Second, Multiplier: 2}
+
+// PressurePolicy describes when the process is considered to be under
+// pressure, and how much restarts are slowed by whilst it is; see
+// Supervisor.WithPressureThrottling. Each threshold of zero is ignored.
+type PressurePolicy struct {
+	// MaxGCPause is the fraction of time - between 0 and 1 - spent paused
+	// for garbage collection since the previous sample, above which the
+	// process is under pressure.
+	MaxGCPause float64
+	// MaxGoroutines is the number of goroutines above which the process is
+	// under pressure.
+	MaxGoroutines int
+	// Check, if given, is called upon each sample and reports whether the
+	// process is under pressure by some other measure, such as its memory
+	// usage or the load of its host.
+	Check func() bool
+	// Interval is the minimum time between samples, which are taken as
+	// workers are restarted; it defaults to DefaultPressureInterval.
+	Interval time.Duration
+	// Backoff determines the additional delay before each restart, based
+	// upon the number of consecutive samples which found the process under
+	// pressure; it defaults to DefaultPressureBackoff.
+	Backoff Backoff
+}
+
+// WithPressureThrottling slows the restarting of workers whilst the process
+// is under pressure, as described by the policy, preventing a storm of
+// restarts from finishing off a service which is already struggling. The
+// delay is in addition to that of the group's RestartPolicy, and grows for
+// as long as the pressure persists.
+//
+// A nil policy disables throttling, which is the default. It takes effect
+// for restarts which occur after it's set.
+func (s *Supervisor) WithPressureThrottling(policy *PressurePolicy) {
+	var throttle *pressureThrottle
+	if policy != nil {
+		throttle = newPressureThrottle(*policy, ClockFrom(s.parent))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateHooksLocked(func(h *runHooks) {
+		h.throttle = throttle
+	})
+}
+
+// UnderPressure returns whether the most recent sample found the process to
+// be under pressure, should pressure throttling be enabled.
+func (s *Supervisor) UnderPressure() bool {
+	throttle := s.runHooks().throttle
+	if throttle == nil {
+		return false
+	}
+
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	return throttle.streak > 0
+}
+
+// pressureThrottle samples the process's pressure, shared by every worker
+// which is restarted.
+type pressureThrottle struct {
+	policy PressurePolicy
+
+	mu       sync.Mutex
+	sampled  time.Time
+	paused   time.Duration
+	streak   int
+	gcStats  debug.GCStats
+	lastWall time.Time
+}
+
+func newPressureThrottle(policy PressurePolicy, clock Clock) *pressureThrottle {
+	if policy.Interval <= 0 {
+		policy.Interval = DefaultPressureInterval
+	}
+
+	if policy.Backoff == (Backoff{}) {
+		policy.Backoff = DefaultPressureBackoff
+	}
+
+	t := &pressureThrottle{policy: policy, lastWall: clock.Now()}
+	debug.ReadGCStats(&t.gcStats)
+	t.paused = t.gcStats.PauseTotal
+	return t
+}
+
+// delay returns the additional delay before a restart, sampling the
+// process's pressure should the previous sample be older than the policy's
+// interval.
+func (t *pressureThrottle) delay(clock Clock) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := clock.Now()
+	if t.sampled.IsZero() || now.Sub(t.sampled) >= t.policy.Interval {
+		t.sampleLocked(now)
+	}
+
+	if t.streak == 0 {
+		return 0
+	}
+
+	return t.policy.Backoff.Duration(t.streak - 1)
+}
+
+func (t *pressureThrottle) sampleLocked(now time.Time) {
+	reason := t.pressureLocked(now)
+	t.sampled = now
+
+	switch {
+	case reason != "" && t.streak == 0:
+		log(fmt.Sprintf("process under pressure (%s), throttling restarts", reason))
+		t.streak++
+	case reason != "":
+		t.streak++
+	case t.streak > 0:
+		log("process no longer under pressure, restarts are no longer throttled")
+		t.streak = 0
+	}
+}
+
+// pressureLocked returns why the process is under pressure, or an empty
+// string should it not be.
+func (t *pressureThrottle) pressureLocked(now time.Time) string {
+	policy := t.policy
+	if policy.MaxGCPause > 0 {
+		debug.ReadGCStats(&t.gcStats)
+		paused, elapsed := t.gcStats.PauseTotal-t.paused, now.Sub(t.lastWall)
+		t.paused, t.lastWall = t.gcStats.PauseTotal, now
+
+		if elapsed > 0 {
+			if fraction := float64(paused) / float64(elapsed); fraction > policy.MaxGCPause {
+				return fmt.Sprintf("%.1f%% of time paused for GC", fraction*100)
+			}
+		}
+	}
+
+	if policy.MaxGoroutines > 0 {
+		if n := runtime.NumGoroutine(); n > policy.MaxGoroutines {
+			return fmt.Sprintf("%d goroutines", n)
+		}
+	}
+
+	if policy.Check != nil && policy.Check() {
+		return "reported by check"
+	}
+
+	return ""
+}
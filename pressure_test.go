@@ -0,0 +1,97 @@
+package supervisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func failingWorker(ctx context.Context, done chan struct{}) {
+	defer Recover(ctx, done)
+	panic("failed")
+}
+
+func restartsWithin(t *testing.T, d time.Duration, pressure *PressurePolicy) int {
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs:    []WorkerSpec{{Name: "failing", Worker: failingWorker}},
+		Pressure: pressure,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(d)
+	s.Stop()
+	s.Wait()
+
+	return s.WorkerInfo("failing")[0].Restarts
+}
+
+func Test_PressureThrottlingMustSlowRestartsUnderPressure(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var checks int32
+	throttled := restartsWithin(t, time.Millisecond*200, &PressurePolicy{
+		Check: func() bool {
+			atomic.AddInt32(&checks, 1)
+			return true
+		},
+		Interval: time.Millisecond,
+		Backoff:  Backoff{Initial: time.Millisecond * 50, Max: time.Millisecond * 50},
+	})
+
+	if throttled < 2 || throttled > 5 {
+		t.Error("expected restarts to be delayed whilst under pressure", throttled)
+	}
+
+	if atomic.LoadInt32(&checks) == 0 {
+		t.Error("expected the check to be called upon restarting")
+	}
+
+	relieved := restartsWithin(t, time.Millisecond*200, &PressurePolicy{
+		Check:    func() bool { return false },
+		Interval: time.Millisecond,
+	})
+
+	if relieved < 50 {
+		t.Error("expected restarts to be unaffected without pressure", relieved)
+	}
+
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_PressureThrottlingMustSampleGoroutinesAtMostOncePerInterval(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	var checks int
+	throttle := newPressureThrottle(PressurePolicy{
+		MaxGoroutines: 1,
+		Check: func() bool {
+			checks++
+			return false
+		},
+		Interval: time.Second,
+	}, clock)
+
+	if d := throttle.delay(clock); d != DefaultPressureBackoff.Initial {
+		t.Error("expected the goroutine count to exceed the threshold", d)
+	}
+
+	if d := throttle.delay(clock); d != DefaultPressureBackoff.Initial {
+		t.Error("expected the sample to be reused within the interval", d)
+	}
+
+	clock.Advance(time.Second)
+	if d := throttle.delay(clock); d != DefaultPressureBackoff.Duration(1) {
+		t.Error("expected the delay to grow whilst under pressure", d)
+	}
+
+	throttle.policy.MaxGoroutines = 0
+	clock.Advance(time.Second)
+	if d := throttle.delay(clock); d != 0 || checks != 1 {
+		t.Error("expected no delay once the pressure is relieved", d, checks)
+	}
+}
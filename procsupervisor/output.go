@@ -0,0 +1,152 @@
+package procsupervisor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// flusher is implemented by writers which buffer output; they're flushed
+// each time the process exits.
+type flusher interface {
+	Flush() error
+}
+
+// LineWriter splits the output of a process in to lines, passing each
+// complete line on as it's written. It's safe for concurrent use, so it may
+// be given as both the Stdout and Stderr of a Spec.
+type LineWriter struct {
+	mu   sync.Mutex
+	buf  []byte
+	emit func(line string) error
+}
+
+// PrefixWriter returns a LineWriter which writes each line to w prefixed
+// with the name of the process, allowing the output of several processes
+// to be multiplexed on to a single Writer.
+func PrefixWriter(name string, w io.Writer) *LineWriter {
+	return &LineWriter{emit: func(line string) error {
+		_, err := fmt.Fprintf(w, "[%s] %s\n", name, line)
+		return err
+	}}
+}
+
+// LoggerWriter returns a LineWriter which passes each line to the Logger
+// prefixed with the name of the process, so that process output ends up
+// alongside that of the Supervisor.
+func LoggerWriter(name string, l supervisor.Logger) *LineWriter {
+	return &LineWriter{emit: func(line string) error {
+		l.Println(fmt.Sprintf("[%s] %s", name, line))
+		return nil
+	}}
+}
+
+// Write satisfies io.Writer.
+func (lw *LineWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+
+		line := bytes.TrimSuffix(lw.buf[:i], []byte("\r"))
+		lw.buf = lw.buf[i+1:]
+		if err := lw.emit(string(line)); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush passes on any incomplete line which remains buffered.
+func (lw *LineWriter) Flush() error {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	if len(lw.buf) == 0 {
+		return nil
+	}
+
+	line := string(lw.buf)
+	lw.buf = nil
+	return lw.emit(line)
+}
+
+// LogFile is a file which process output is appended to, and which can be
+// rotated whilst the process is running.
+type LogFile struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenLogFile opens - creating if necessary - the file at path for
+// appending.
+func OpenLogFile(path string) (*LogFile, error) {
+	f, err := openAppend(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LogFile{path: path, f: f}, nil
+}
+
+func openAppend(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+}
+
+// Write satisfies io.Writer.
+func (l *LogFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f.Write(p)
+}
+
+// Rotate closes the file, invokes the hook - which may, for example, rename
+// or compress it - and then opens the path afresh. Output is held whilst
+// the file is being rotated. The file is reopened even if the hook fails,
+// in which case the hook's error is returned.
+func (l *LogFile) Rotate(hook func(path string) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+
+	var hookErr error
+	if hook != nil {
+		hookErr = hook(l.path)
+	}
+
+	f, err := openAppend(l.path)
+	if err != nil {
+		return err
+	}
+
+	l.f = f
+	return hookErr
+}
+
+// Reopen opens the path afresh, for use once the file has been rotated by
+// an external tool such as logrotate.
+func (l *LogFile) Reopen() error {
+	return l.Rotate(nil)
+}
+
+// Close closes the file.
+func (l *LogFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.f.Close()
+}
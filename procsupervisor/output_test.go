@@ -0,0 +1,75 @@
+package procsupervisor
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type mockLogger struct {
+	lines []string
+}
+
+func (m *mockLogger) Println(msg string) {
+	m.lines = append(m.lines, msg)
+}
+
+func Test_PrefixWriterMustPrefixCompleteLines(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := PrefixWriter("web", buf)
+
+	w.Write([]byte("first\r\nsec"))
+	w.Write([]byte("ond\nthi"))
+	if buf.String() != "[web] first\n[web] second\n" {
+		t.Error("expected only complete lines to be written", buf.String())
+	}
+
+	w.Flush()
+	if buf.String() != "[web] first\n[web] second\n[web] thi\n" {
+		t.Error("expected flushing to write the incomplete line", buf.String())
+	}
+}
+
+func Test_LoggerWriterMustPassLinesToLogger(t *testing.T) {
+	l := &mockLogger{}
+	w := LoggerWriter("web", l)
+
+	w.Write([]byte("hello\nworld\n"))
+	if len(l.lines) != 2 || l.lines[0] != "[web] hello" || l.lines[1] != "[web] world" {
+		t.Error("unexpected lines logged", l.lines)
+	}
+}
+
+func Test_LogFileMustReopenAfterRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procsupervisor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out.log")
+	f, err := OpenLogFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	f.Write([]byte("before\n"))
+	err = f.Rotate(func(path string) error {
+		return os.Rename(path, path+".1")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("after\n"))
+
+	if rotated, _ := ioutil.ReadFile(path + ".1"); string(rotated) != "before\n" {
+		t.Error("expected the rotated file to hold the earlier output", string(rotated))
+	}
+
+	if current, _ := ioutil.ReadFile(path); string(current) != "after\n" {
+		t.Error("expected output to continue in a fresh file", string(current))
+	}
+}
@@ -0,0 +1,231 @@
+// Package procsupervisor runs external processes as Supervisables, allowing
+// a Supervisor to restart them according to its RestartPolicy in the same
+// manner as any other worker.
+//
+// Cancelling a process' context - such as when the Supervisor is stopped -
+// asks it to terminate via SIGTERM, and only kills it should it still be
+// running once its grace period has elapsed. Each start and exit of the
+// process, along with its exit code, is published via Events.
+package procsupervisor
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// DefaultGracePeriod is the time a process is given to exit after SIGTERM.
+const DefaultGracePeriod = 10 * time.Second
+
+// eventBuffer is the number of events retained for a slow reader.
+const eventBuffer = 64
+
+// Spec describes an external process.
+type Spec struct {
+	// Name identifies the process within its Supervisor.
+	Name string
+	// Command is the executable to run; see exec.Command.
+	Command string
+	// Args are the arguments passed to Command.
+	Args []string
+	// GracePeriod is the time the process is given to exit once asked to
+	// terminate; it defaults to DefaultGracePeriod.
+	GracePeriod time.Duration
+	// Stdout and Stderr receive the output of the process; where nil it's
+	// discarded. See PrefixWriter and LoggerWriter for capturing output
+	// line by line, and LogFile for writing it to a rotatable file.
+	Stdout io.Writer
+	Stderr io.Writer
+	// PTY runs the process under a pseudo-terminal, for programs which
+	// behave differently when not attached to a terminal - such as by
+	// buffering their output, or omitting colour. The terminal combines the
+	// process' output, which is written to Stdout alone. It's only
+	// supported on Linux; elsewhere the process fails to start with
+	// ErrPTYUnsupported.
+	PTY bool
+	// Env holds additional environment variables, in the form "KEY=value",
+	// which override those inherited from the current process.
+	Env []string
+	// Dir is the working directory of the process; it defaults to that of
+	// the current process.
+	Dir string
+	// Umask, where given, is the file mode creation mask of the process.
+	Umask *os.FileMode
+	// User and Group, where given, are the name or ID of the user and group
+	// to run the process as; Group defaults to the user's primary group.
+	// Switching credentials typically requires running as root, and isn't
+	// supported on Windows.
+	User  string
+	Group string
+	// Rlimits are resource limits applied to the process; they're only
+	// supported on Linux.
+	Rlimits []Rlimit
+}
+
+// EventType distinguishes the events published by a Process.
+type EventType int
+
+const (
+	// Started is published once the process has been started.
+	Started EventType = iota
+	// Exited is published once the process has exited, or failed to start.
+	Exited
+)
+
+// Event describes a change in the state of a Process.
+type Event struct {
+	// Name is the name of the Process.
+	Name string
+	// Type is the type of the event.
+	Type EventType
+	// Time is when the event occurred.
+	Time time.Time
+	// PID is the process ID; it's zero if the process couldn't be started.
+	PID int
+	// ExitCode is the exit code of an Exited process, or -1 should it have
+	// been killed by a signal or failed to start.
+	ExitCode int
+	// Err is the error returned upon the process exiting, if any.
+	Err error
+}
+
+// Process is an external process which can be run by a Supervisor.
+type Process struct {
+	spec   Spec
+	events chan Event
+}
+
+// New returns a Process for the given Spec.
+func New(spec Spec) *Process {
+	if spec.GracePeriod <= 0 {
+		spec.GracePeriod = DefaultGracePeriod
+	}
+
+	return &Process{spec: spec, events: make(chan Event, eventBuffer)}
+}
+
+// Events returns the stream of events for the Process. The stream is
+// buffered, and events are discarded - rather than blocking the Process -
+// should it not be read from.
+func (p *Process) Events() <-chan Event {
+	return p.events
+}
+
+// WorkerSpec returns a WorkerSpec which runs a single instance of the
+// Process.
+func (p *Process) WorkerSpec() supervisor.WorkerSpec {
+	return supervisor.WorkerSpec{Name: p.spec.Name, Worker: p.Supervisable()}
+}
+
+// Supervisable returns a Supervisable which runs the process until it
+// exits. A process which fails to start, or exits unsuccessfully, is
+// reported as a failure via supervisor.ReportError.
+func (p *Process) Supervisable() supervisor.Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		if err := p.run(ctx); err != nil && ctx.Err() == nil {
+			supervisor.ReportError(ctx, err)
+		}
+	}
+}
+
+func (p *Process) run(ctx context.Context) error {
+	cmd, err := p.command()
+	if err != nil {
+		p.publish(Event{Type: Exited, ExitCode: -1, Err: err})
+		return err
+	}
+
+	var tty *pty
+	if p.spec.PTY {
+		if tty, err = openPTY(cmd); err != nil {
+			p.publish(Event{Type: Exited, ExitCode: -1, Err: err})
+			return err
+		}
+	}
+
+	if err = p.start(cmd); err != nil {
+		if tty != nil {
+			tty.close()
+		}
+
+		p.publish(Event{Type: Exited, ExitCode: -1, Err: err})
+		return err
+	}
+
+	if tty != nil {
+		tty.copyTo(p.spec.Stdout)
+	}
+
+	p.publish(Event{Type: Started, PID: cmd.Process.Pid})
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+	case err = <-exited:
+	case <-ctx.Done():
+		err = p.stop(cmd, exited)
+	}
+
+	if tty != nil {
+		tty.close()
+	}
+
+	p.flush()
+	p.publish(Event{
+		Type:     Exited,
+		PID:      cmd.Process.Pid,
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Err:      err,
+	})
+
+	return err
+}
+
+// stop asks the process to exit, and kills it should it not have done so
+// within the grace period.
+func (p *Process) stop(cmd *exec.Cmd, exited chan error) error {
+	if err := terminate(cmd.Process); err != nil {
+		cmd.Process.Kill()
+		return <-exited
+	}
+
+	timer := time.NewTimer(p.spec.GracePeriod)
+	defer timer.Stop()
+
+	select {
+	case err := <-exited:
+		return err
+	case <-timer.C:
+		cmd.Process.Kill()
+		return <-exited
+	}
+}
+
+// flush passes on any incomplete lines of output, so that the output of
+// one run isn't joined to that of the next.
+func (p *Process) flush() {
+	for _, w := range []io.Writer{p.spec.Stdout, p.spec.Stderr} {
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+func (p *Process) publish(e Event) {
+	e.Name = p.spec.Name
+	e.Time = time.Now()
+
+	select {
+	case p.events <- e:
+	default:
+	}
+}
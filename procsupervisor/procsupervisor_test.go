@@ -0,0 +1,100 @@
+//go:build !windows
+// +build !windows
+
+package procsupervisor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func shell(script string, grace time.Duration) *Process {
+	return New(Spec{Name: "sh", Command: "/bin/sh", Args: []string{"-c", script}, GracePeriod: grace})
+}
+
+func nextEvent(t *testing.T, p *Process) Event {
+	t.Helper()
+
+	select {
+	case e := <-p.Events():
+		return e
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out awaiting event")
+		return Event{}
+	}
+}
+
+func Test_ProcessMustPublishExitCodes(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	p := shell("exit 3", 0)
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs:  []supervisor.WorkerSpec{p.WorkerSpec()},
+		Policy: supervisor.RestartPolicy{MaxRestarts: 1, Escalation: supervisor.StopSupervisor},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Run()
+	s.Wait()
+
+	for i := 0; i < 2; i++ {
+		if e := nextEvent(t, p); e.Type != Started || e.PID == 0 {
+			t.Error("expected the process to have started", e)
+		}
+
+		if e := nextEvent(t, p); e.Type != Exited || e.ExitCode != 3 || e.Err == nil {
+			t.Error("expected the exit code to be published", e)
+		}
+	}
+
+	if history := s.History("sh", 0); len(history) != 2 || history[0].Reason == nil {
+		t.Error("expected each exit to be reported as a failure", history)
+	}
+}
+
+func Test_ProcessMustBeTerminatedUponCancellation(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	p := shell(`trap "exit 7" TERM; while true; do sleep 0.01; done`, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go p.Supervisable()(ctx, done)
+
+	nextEvent(t, p)
+	<-time.After(time.Millisecond * 50)
+	cancel()
+	<-done
+
+	if e := nextEvent(t, p); e.Type != Exited || e.ExitCode != 7 {
+		t.Error("expected the process to exit via its SIGTERM handler", e)
+	}
+}
+
+func Test_ProcessMustBeKilledAfterGracePeriod(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	p := shell(`trap "" TERM; while true; do sleep 0.01; done`, time.Millisecond*100)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go p.Supervisable()(ctx, done)
+
+	nextEvent(t, p)
+	<-time.After(time.Millisecond * 50)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the process to be killed once its grace period elapsed")
+	}
+
+	if e := nextEvent(t, p); e.Type != Exited || e.ExitCode != -1 {
+		t.Error("expected the process to have been killed", e)
+	}
+}
@@ -0,0 +1,63 @@
+package procsupervisor
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// ErrPTYUnsupported is returned when a Spec requests a pseudo-terminal on a
+// platform where one can't be allocated.
+var ErrPTYUnsupported = errors.New("procsupervisor: pseudo-terminals are unsupported on this platform")
+
+// ptyDrainTimeout bounds how long output is read from a pseudo-terminal
+// once its process has exited; a process which leaves children holding the
+// terminal open would otherwise prevent it from ever being closed.
+const ptyDrainTimeout = time.Second
+
+// pty is a pseudo-terminal allocated for a single run of a process.
+type pty struct {
+	master *os.File
+	slave  *os.File
+	copied chan struct{}
+}
+
+// copyTo releases the parent's handle upon the terminal, and copies the
+// output of the process to w; it's called once the process has started.
+func (t *pty) copyTo(w io.Writer) {
+	if w == nil {
+		w = ioutil.Discard
+	}
+
+	t.slave.Close()
+	t.copied = make(chan struct{})
+	go func() {
+		defer close(t.copied)
+
+		// Reading fails - typically with EIO - once the process, and any
+		// children, have closed the terminal.
+		io.Copy(w, t.master)
+	}()
+}
+
+// close drains any remaining output, and then closes the terminal.
+func (t *pty) close() {
+	if t.copied == nil {
+		t.slave.Close()
+		t.master.Close()
+		return
+	}
+
+	timer := time.NewTimer(ptyDrainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-t.copied:
+	case <-timer.C:
+	}
+
+	t.master.Close()
+	<-t.copied
+}
@@ -0,0 +1,72 @@
+package procsupervisor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY allocates a pseudo-terminal, and attaches it as the controlling
+// terminal - and standard streams - of cmd.
+func openPTY(cmd *exec.Cmd) (*pty, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	slave, err := openSlave(master)
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+
+	return &pty{master: master, slave: slave}, nil
+}
+
+// openSlave unlocks, and opens, the terminal corresponding to master.
+func openSlave(master *os.File) (*os.File, error) {
+	// The descriptor is accessed via SyscallConn rather than Fd, as the
+	// latter would put it in blocking mode - preventing Close from
+	// interrupting a pending Read.
+	conn, err := master.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+	var ioctlErr error
+	err = conn.Control(func(fd uintptr) {
+		if ioctlErr = unix.IoctlSetPointerInt(int(fd), unix.TIOCSPTLCK, 0); ioctlErr != nil {
+			return
+		}
+
+		n, ioctlErr = unix.IoctlGetInt(int(fd), unix.TIOCGPTN)
+	})
+	if err == nil {
+		err = ioctlErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("procsupervisor: unable to allocate pty: %w", err)
+	}
+
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Programs which lay out their output - such as progress bars - would
+	// otherwise see a terminal of zero size.
+	unix.IoctlSetWinsize(int(slave.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: 24, Col: 80})
+	return slave, nil
+}
@@ -0,0 +1,38 @@
+package procsupervisor
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func Test_ProcessMustRunUnderPTY(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	script := `if [ -t 1 ]; then echo tty; else echo notty; fi`
+	for _, usePTY := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		p := New(Spec{
+			Name:    "sh",
+			Command: "/bin/sh",
+			Args:    []string{"-c", script},
+			Stdout:  PrefixWriter("sh", buf),
+			PTY:     usePTY,
+		})
+
+		done := make(chan struct{})
+		p.Supervisable()(context.Background(), done)
+		<-done
+
+		expected := "[sh] notty\n"
+		if usePTY {
+			expected = "[sh] tty\n"
+		}
+
+		if buf.String() != expected {
+			t.Errorf("expected %q with PTY=%t, got %q", expected, usePTY, buf.String())
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package procsupervisor
+
+import "os/exec"
+
+func openPTY(cmd *exec.Cmd) (*pty, error) {
+	return nil, ErrPTYUnsupported
+}
@@ -0,0 +1,78 @@
+package procsupervisor
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+var rlimitResources = map[string]int{
+	"as":      unix.RLIMIT_AS,
+	"core":    unix.RLIMIT_CORE,
+	"cpu":     unix.RLIMIT_CPU,
+	"data":    unix.RLIMIT_DATA,
+	"fsize":   unix.RLIMIT_FSIZE,
+	"memlock": unix.RLIMIT_MEMLOCK,
+	"nofile":  unix.RLIMIT_NOFILE,
+	"nproc":   unix.RLIMIT_NPROC,
+	"rss":     unix.RLIMIT_RSS,
+	"stack":   unix.RLIMIT_STACK,
+}
+
+// RlimitResource returns the resource with the given name - such as
+// "nofile" or "core" - for use in an Rlimit.
+func RlimitResource(name string) (int, bool) {
+	resource, ok := rlimitResources[name]
+	return resource, ok
+}
+
+// rlimitMu serialises changes to the process's own resource limits, which
+// are shared by every goroutine starting a process.
+var rlimitMu sync.Mutex
+
+// startWithRlimits starts a process via start, with the limits applied. As
+// there's no means of applying them between fork and exec, the soft limits
+// are set upon this process for the duration of start, so that the child
+// inherits them from the moment it's executed, before being restored. Hard
+// limits can't be restored once lowered, so they're applied via prlimit(2)
+// immediately after the process has started; should that fail then the
+// process is killed.
+func startWithRlimits(cmd *exec.Cmd, limits []Rlimit, start func() error) error {
+	rlimitMu.Lock()
+	defer rlimitMu.Unlock()
+
+	for _, l := range limits {
+		var previous syscall.Rlimit
+		if err := syscall.Getrlimit(l.Resource, &previous); err != nil {
+			return fmt.Errorf("procsupervisor: unable to read resource limit %d: %w", l.Resource, err)
+		}
+
+		inherited := syscall.Rlimit{Cur: l.Soft, Max: previous.Max}
+		if l.Hard > previous.Max {
+			inherited.Max = l.Hard
+		}
+
+		if err := syscall.Setrlimit(l.Resource, &inherited); err != nil {
+			return fmt.Errorf("procsupervisor: unable to set resource limit %d: %w", l.Resource, err)
+		}
+		defer syscall.Setrlimit(l.Resource, &previous)
+	}
+
+	if err := start(); err != nil {
+		return err
+	}
+
+	for _, l := range limits {
+		err := unix.Prlimit(cmd.Process.Pid, l.Resource, &unix.Rlimit{Cur: l.Soft, Max: l.Hard}, nil)
+		if err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return fmt.Errorf("procsupervisor: unable to set resource limit %d: %w", l.Resource, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package procsupervisor
+
+import (
+	"bytes"
+	"context"
+	"syscall"
+	"testing"
+)
+
+func Test_ProcessMustApplyRlimits(t *testing.T) {
+	nofile, ok := RlimitResource("nofile")
+	if !ok {
+		t.Fatal("expected nofile to be a known resource")
+	}
+
+	var before syscall.Rlimit
+	syscall.Getrlimit(nofile, &before)
+
+	buf := &bytes.Buffer{}
+	p := New(Spec{
+		Name:    "sh",
+		Command: "/bin/sh",
+		Args:    []string{"-c", "ulimit -n; sleep 0.1; ulimit -Hn"},
+		Rlimits: []Rlimit{{Resource: nofile, Soft: 64, Hard: 128}},
+		Stdout:  buf,
+	})
+
+	done := make(chan struct{})
+	p.Supervisable()(context.Background(), done)
+
+	if buf.String() != "64\n128\n" {
+		t.Error("expected the limits to be applied from the start", buf.String())
+	}
+
+	var after syscall.Rlimit
+	if syscall.Getrlimit(nofile, &after); after != before {
+		t.Error("expected the supervisor's own limits to be restored", before, after)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package procsupervisor
+
+import "os/exec"
+
+// RlimitResource returns the resource with the given name - such as
+// "nofile" or "core" - for use in an Rlimit; resource limits are only
+// supported on Linux.
+func RlimitResource(name string) (int, bool) {
+	return 0, false
+}
+
+func startWithRlimits(cmd *exec.Cmd, limits []Rlimit, start func() error) error {
+	return ErrUnsupported
+}
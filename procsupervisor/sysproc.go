@@ -0,0 +1,57 @@
+package procsupervisor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// ErrUnsupported is returned when a Spec requests a umask, credentials, or
+// resource limits on a platform which doesn't support them.
+var ErrUnsupported = errors.New("procsupervisor: unsupported on this platform")
+
+// Rlimit is a resource limit applied to a process; see setrlimit(2).
+type Rlimit struct {
+	// Resource is the resource being limited, such as unix.RLIMIT_NOFILE;
+	// see RlimitResource for looking it up by name.
+	Resource int
+	// Soft is the limit enforced by the kernel.
+	Soft uint64
+	// Hard is the ceiling to which the process may raise its soft limit.
+	Hard uint64
+}
+
+// command builds the exec.Cmd for a single run of the process.
+func (p *Process) command() (*exec.Cmd, error) {
+	cmd := exec.Command(p.spec.Command, p.spec.Args...)
+	cmd.Stdout = p.spec.Stdout
+	cmd.Stderr = p.spec.Stderr
+	cmd.Dir = p.spec.Dir
+
+	if len(p.spec.Env) > 0 {
+		cmd.Env = append(os.Environ(), p.spec.Env...)
+	}
+
+	if p.spec.User != "" || p.spec.Group != "" {
+		if err := setCredential(cmd, p.spec.User, p.spec.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	return cmd, nil
+}
+
+// start starts the process, with its umask and resource limits applied.
+func (p *Process) start(cmd *exec.Cmd) error {
+	start := cmd.Start
+	if p.spec.Umask != nil {
+		mask := int(*p.spec.Umask)
+		start = func() error { return startWithUmask(cmd, mask) }
+	}
+
+	if len(p.spec.Rlimits) > 0 {
+		return startWithRlimits(cmd, p.spec.Rlimits, start)
+	}
+
+	return start()
+}
@@ -0,0 +1,67 @@
+//go:build !windows
+// +build !windows
+
+package procsupervisor
+
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// umaskMu serialises changes to the umask, which is shared by every
+// goroutine in the process.
+var umaskMu sync.Mutex
+
+// setCredential runs cmd as the given user and group, which may be given
+// by name or ID; the group defaults to that of the user.
+func setCredential(cmd *exec.Cmd, username, group string) error {
+	cred := &syscall.Credential{Uid: uint32(syscall.Getuid()), Gid: uint32(syscall.Getgid())}
+
+	if username != "" {
+		u, err := user.Lookup(username)
+		if err != nil {
+			if u, err = user.LookupId(username); err != nil {
+				return fmt.Errorf("procsupervisor: unknown user %q", username)
+			}
+		}
+
+		uid, _ := strconv.ParseUint(u.Uid, 10, 32)
+		gid, _ := strconv.ParseUint(u.Gid, 10, 32)
+		cred.Uid, cred.Gid = uint32(uid), uint32(gid)
+	}
+
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			if g, err = user.LookupGroupId(group); err != nil {
+				return fmt.Errorf("procsupervisor: unknown group %q", group)
+			}
+		}
+
+		gid, _ := strconv.ParseUint(g.Gid, 10, 32)
+		cred.Gid = uint32(gid)
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = cred
+	return nil
+}
+
+// startWithUmask starts cmd with the given umask. As the umask is shared by
+// the whole process, it's only changed for the duration of Start; files
+// created by other goroutines in the meantime are also subject to it.
+func startWithUmask(cmd *exec.Cmd, mask int) error {
+	umaskMu.Lock()
+	defer umaskMu.Unlock()
+
+	previous := syscall.Umask(mask)
+	defer syscall.Umask(previous)
+
+	return cmd.Start()
+}
@@ -0,0 +1,50 @@
+//go:build !windows
+// +build !windows
+
+package procsupervisor
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func Test_ProcessMustApplyEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procsupervisor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	umask := os.FileMode(0027)
+	buf := &bytes.Buffer{}
+	p := New(Spec{
+		Name:    "sh",
+		Command: "/bin/sh",
+		Args:    []string{"-c", `echo "$GREETING $(pwd) $(umask)"`},
+		Env:     []string{"GREETING=hello"},
+		Dir:     dir,
+		Umask:   &umask,
+		Stdout:  buf,
+	})
+
+	done := make(chan struct{})
+	p.Supervisable()(context.Background(), done)
+
+	if expected := "hello " + dir + " 0027\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func Test_ProcessMustRejectUnknownUser(t *testing.T) {
+	p := New(Spec{Name: "sh", Command: "/bin/true", User: "no-such-user-exists"})
+
+	done := make(chan struct{})
+	p.Supervisable()(context.Background(), done)
+
+	if e := <-p.Events(); e.Type != Exited || e.Err == nil || e.PID != 0 {
+		t.Error("expected the process to fail to start", e)
+	}
+}
@@ -0,0 +1,11 @@
+package procsupervisor
+
+import "os/exec"
+
+func setCredential(cmd *exec.Cmd, username, group string) error {
+	return ErrUnsupported
+}
+
+func startWithUmask(cmd *exec.Cmd, mask int) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package procsupervisor
+
+import (
+	"os"
+	"syscall"
+)
+
+// terminate asks the process to exit via SIGTERM.
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package procsupervisor
+
+import "os"
+
+// terminate kills the process, as Windows has no equivalent of SIGTERM.
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,107 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrNameTaken is returned by WithName when another Supervisor has already
+// been registered under the given name.
+var ErrNameTaken = errors.New("supervisor: name already registered")
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Supervisor)
+)
+
+// WithName names the Supervisor and registers it, allowing it to be found
+// via Lookup by components which don't hold a reference to it. Renaming a
+// Supervisor replaces its previous registration, whilst an empty name
+// removes it from the registry altogether.
+func (s *Supervisor) WithName(name string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if existing, ok := registry[name]; ok && existing != s {
+		return fmt.Errorf("%w: %q", ErrNameTaken, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.name != "" {
+		delete(registry, s.name)
+	}
+
+	s.name = name
+	if name != "" {
+		registry[name] = s
+	}
+
+	return nil
+}
+
+// Name returns the name given to the Supervisor via WithName.
+func (s *Supervisor) Name() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.name
+}
+
+// Lookup returns the Supervisor registered under the given name.
+//
+//	if s, ok := supervisor.Lookup("ingestion"); ok {
+//		s.RestartGroup("fetchers")
+//	}
+func Lookup(name string) (*Supervisor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	s, ok := registry[name]
+	return s, ok
+}
+
+// LookupWorker returns the statistics for every instance of the named
+// worker, belonging to the Supervisor registered under the given name.
+func LookupWorker(supervisor, worker string) ([]WorkerInfo, bool) {
+	s, ok := Lookup(supervisor)
+	if !ok {
+		return nil, false
+	}
+
+	infos := s.WorkerInfo(worker)
+	return infos, len(infos) > 0
+}
+
+// Unregister removes the named Supervisor from the registry; it's
+// equivalent to calling WithName("") upon it.
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if s, ok := registry[name]; ok {
+		s.mu.Lock()
+		s.name = ""
+		s.mu.Unlock()
+
+		delete(registry, name)
+	}
+}
+
+// Registered returns the names of every registered Supervisor, in sorted
+// order.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,65 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_RegistryMustLookupSupervisorsByName(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var runs int32
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "fetcher", Worker: countingWorker(&runs), Count: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.WithName("ingestion"); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("ingestion")
+
+	other := NewSimpleSupervisor(context.Background(), generateSupervisable(&mockSupervisable{}))
+	if err := other.WithName("ingestion"); !errors.Is(err, ErrNameTaken) {
+		t.Error("expected a second registration under the same name to fail", err)
+	}
+
+	s.Run()
+	<-time.After(time.Millisecond * 20)
+
+	found, ok := Lookup("ingestion")
+	if !ok || found != s || found.Name() != "ingestion" {
+		t.Fatal("expected to find the registered supervisor")
+	}
+
+	infos, ok := LookupWorker("ingestion", "fetcher")
+	if !ok || len(infos) != 2 || !infos[0].Running {
+		t.Error("expected to find both running instances of the worker", infos)
+	}
+
+	if _, ok := LookupWorker("ingestion", "missing"); ok {
+		t.Error("expected no unknown worker to be found")
+	}
+
+	if err := s.WithName("renamed"); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("renamed")
+
+	if _, ok := Lookup("ingestion"); ok {
+		t.Error("expected the previous name to be released upon renaming")
+	}
+
+	if names := Registered(); len(names) != 1 || names[0] != "renamed" {
+		t.Error("unexpected registered names", names)
+	}
+
+	found.Stop()
+	<-time.After(time.Millisecond * 100)
+}
@@ -0,0 +1,217 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"runtime/metrics"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// groupLabel is the profiler label applied to the goroutines of a group's
+// workers, should resource statistics be enabled; it's inherited by any
+// goroutine a worker starts, and is visible in CPU and goroutine profiles.
+const groupLabel = "supervisor.group"
+
+// GroupResources contains best-effort accounting of the resources used by
+// a group of workers; see WithResourceStats. The figures are indicative,
+// allowing the group responsible for - say - memory growth to be found,
+// rather than exact.
+type GroupResources struct {
+	// Name is the name of the group; the default group is unnamed.
+	Name string
+	// Goroutines is the number of goroutines labelled with the group: those
+	// its workers are run upon, along with any they've started.
+	Goroutines int
+	// RestartCPUTime is the CPU time consumed by the process whilst
+	// restarting the group's workers. It's sampled process wide, so it
+	// includes the work of any goroutines running in the meantime.
+	RestartCPUTime time.Duration
+	// AllocatedBytes is the number of bytes allocated by the process during
+	// the runs of the group's workers, including those still in progress.
+	// It's sampled process wide around each run, so overlapping runs - in
+	// this group or any other - are each attributed the same allocations;
+	// it's an upper bound, most useful in comparison with other groups.
+	AllocatedBytes uint64
+	// AllocatedObjects is the number of objects allocated during the runs
+	// of the group's workers, sampled as AllocatedBytes is.
+	AllocatedObjects uint64
+}
+
+// WithResourceStats enables the accounting of resources by group, available
+// via GroupResources. Each worker's goroutine is labelled with its group -
+// see the profiler labels of runtime/pprof - and the process's allocations
+// and CPU time are sampled around each run and restart.
+//
+// It's disabled by default, and takes effect for the runs of workers which
+// begin after it's set.
+func (s *Supervisor) WithResourceStats(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateHooksLocked(func(h *runHooks) {
+		h.resources = enabled
+	})
+}
+
+// GroupResources returns the resource accounting of each group, should
+// resource statistics be enabled. Counting goroutines requires a goroutine
+// profile of the whole process, so it's relatively expensive to call.
+func (s *Supervisor) GroupResources() []GroupResources {
+	s.mu.Lock()
+	groups := append([]*group(nil), s.groups...)
+	s.mu.Unlock()
+
+	goroutines := labelledGoroutines()
+	now := sampleAllocs()
+
+	resources := make([]GroupResources, len(groups))
+	for i, g := range groups {
+		resources[i] = g.resources(now)
+		resources[i].Goroutines = goroutines[g.name]
+	}
+
+	return resources
+}
+
+// allocSample is the process's cumulative allocations at a point in time.
+type allocSample struct {
+	bytes   uint64
+	objects uint64
+}
+
+func sampleAllocs() allocSample {
+	samples := []metrics.Sample{
+		{Name: "/gc/heap/allocs:bytes"},
+		{Name: "/gc/heap/allocs:objects"},
+	}
+	metrics.Read(samples)
+
+	var sample allocSample
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		sample.bytes = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		sample.objects = samples[1].Value.Uint64()
+	}
+
+	return sample
+}
+
+// runStarted records the beginning of a run within the group, returning the
+// sample to give runEnded.
+func (g *group) runStarted() allocSample {
+	sample := sampleAllocs()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.usage.runs++
+	g.usage.started.bytes += sample.bytes
+	g.usage.started.objects += sample.objects
+	return sample
+}
+
+// runEnded attributes the allocations since the run began to the group.
+func (g *group) runEnded(started allocSample) {
+	sample := sampleAllocs()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.usage.runs--
+	g.usage.started.bytes -= started.bytes
+	g.usage.started.objects -= started.objects
+	g.usage.allocated.bytes += sample.bytes - started.bytes
+	g.usage.allocated.objects += sample.objects - started.objects
+}
+
+func (g *group) restarted(cpu time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.usage.restartCPU += cpu
+}
+
+// resources returns the group's accounting; the allocations of runs still
+// in progress are measured up until the given sample.
+func (g *group) resources(now allocSample) GroupResources {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	runs := uint64(g.usage.runs)
+	return GroupResources{
+		Name:             g.name,
+		RestartCPUTime:   g.usage.restartCPU,
+		AllocatedBytes:   g.usage.allocated.bytes + runs*now.bytes - g.usage.started.bytes,
+		AllocatedObjects: g.usage.allocated.objects + runs*now.objects - g.usage.started.objects,
+	}
+}
+
+// groupUsage is the resource accounting of a group. Rather than recording
+// the sample taken at the start of each run in progress, their sum is
+// recorded, from which the allocations of every run in progress can be
+// derived.
+type groupUsage struct {
+	runs       int
+	started    allocSample
+	allocated  allocSample
+	restartCPU time.Duration
+}
+
+// labelGoroutine labels the calling goroutine with the worker's group,
+// returning the context carrying the label.
+func labelGoroutine(ctx context.Context, w *worker) context.Context {
+	ctx = pprof.WithLabels(ctx, pprof.Labels(groupLabel, w.group.name))
+	pprof.SetGoroutineLabels(ctx)
+	return ctx
+}
+
+// labelledGoroutines counts the goroutines labelled with each group, from
+// a goroutine profile of the process.
+func labelledGoroutines() map[string]int {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	// Each record of the profile begins with a line such as "3 @ 0x1234",
+	// followed by one such as `# labels: {"supervisor.group":"name"}`
+	// should its goroutines be labelled.
+	counts := map[string]int{}
+	count := 0
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if i := strings.Index(line, " @ "); i > 0 && !strings.HasPrefix(line, "#") {
+			count, _ = strconv.Atoi(line[:i])
+			continue
+		}
+
+		if labels := strings.TrimPrefix(line, "# labels: "); labels != line {
+			if name, ok := parseLabel(labels, groupLabel); ok {
+				counts[name] += count
+			}
+		}
+	}
+
+	return counts
+}
+
+// parseLabel returns the value of the key from a set of labels formatted
+// as in a goroutine profile, such as {"key":"value", "other":"value"}.
+func parseLabel(labels, key string) (string, bool) {
+	prefix := strconv.Quote(key) + ":"
+	i := strings.Index(labels, prefix)
+	if i < 0 {
+		return "", false
+	}
+
+	quoted, err := strconv.QuotedPrefix(labels[i+len(prefix):])
+	if err != nil {
+		return "", false
+	}
+
+	value, err := strconv.Unquote(quoted)
+	return value, err == nil
+}
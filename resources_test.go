@@ -0,0 +1,107 @@
+package supervisor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// sink prevents the allocations of allocatingWorker from being optimised
+// away.
+var sink atomic.Value
+
+func allocatingWorker(helpers int) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		for i := 0; i < helpers; i++ {
+			go func() { <-ctx.Done() }()
+		}
+
+		for i := 0; i < 100; i++ {
+			sink.Store(make([]byte, 1024))
+		}
+		<-ctx.Done()
+	}
+}
+
+func Test_GroupResourcesMustAttributeGoroutinesAndAllocationsToGroups(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "idle", Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			<-ctx.Done()
+		}}},
+		Groups: []Group{
+			{Name: "busy", Workers: []WorkerSpec{{Name: "allocating", Worker: allocatingWorker(3), Count: 2}}},
+		},
+		ResourceStats: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	resources := s.GroupResources()
+	if len(resources) != 2 || resources[0].Name != "" || resources[1].Name != "busy" {
+		t.Fatal("expected the resources of each group", resources)
+	}
+
+	if idle := resources[0]; idle.Goroutines != 1 {
+		t.Error("expected the idle group to only run its worker's goroutine", idle.Goroutines)
+	}
+
+	busy := resources[1]
+	if busy.Goroutines != 8 {
+		t.Error("expected the goroutines started by workers to be attributed to their group", busy.Goroutines)
+	}
+
+	if busy.AllocatedBytes < 2*100*1024 || busy.AllocatedObjects == 0 {
+		t.Error("expected the allocations of runs in progress to be attributed to the group", busy.AllocatedBytes, busy.AllocatedObjects)
+	}
+
+	s.Stop()
+	s.Wait()
+	<-time.After(time.Millisecond * 50)
+
+	if after := s.GroupResources()[1]; after.Goroutines != 0 || after.AllocatedBytes < busy.AllocatedBytes {
+		t.Error("expected the allocations of completed runs to be retained", after)
+	}
+}
+
+func Test_GroupResourcesMustBeEmptyUnlessEnabled(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "allocating", Worker: allocatingWorker(1)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	if resources := s.GroupResources(); len(resources) != 1 || resources[0] != (GroupResources{}) {
+		t.Error("expected no resources to be accounted", resources)
+	}
+
+	s.Stop()
+	s.Wait()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_ParseLabelMustReadQuotedValues(t *testing.T) {
+	labels := `{"other":"x", "supervisor.group":"a \"quoted\" name"}`
+	if name, ok := parseLabel(labels, groupLabel); !ok || name != `a "quoted" name` {
+		t.Error("expected the label to be parsed", name, ok)
+	}
+
+	if _, ok := parseLabel(`{"other":"x"}`, groupLabel); ok {
+		t.Error("expected a missing label to not be found")
+	}
+}
@@ -0,0 +1,96 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Backoff describes an exponentially increasing delay between attempts.
+type Backoff struct {
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+	// Max caps the delay; zero means there is no upper limit.
+	Max time.Duration
+	// Multiplier is applied to the delay after each attempt; values below
+	// 1 are treated as 1, resulting in a constant delay.
+	Multiplier float64
+}
+
+// Duration returns the delay which should follow the given attempt, where
+// the first attempt is 0.
+func (b Backoff) Duration(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	// Without a Max the delay is capped at the longest representable
+	// Duration, rather than overflowing to a negative one.
+	limit := float64(b.Max)
+	if b.Max <= 0 {
+		limit = math.MaxInt64
+	}
+
+	d := float64(b.Initial)
+	for i := 0; i < attempt; i++ {
+		d *= multiplier
+		if d >= limit {
+			break
+		}
+	}
+
+	if d >= limit {
+		if b.Max > 0 {
+			return b.Max
+		}
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(d)
+}
+
+// RetryPolicy determines how many times, and how often, Retry will attempt
+// an operation.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts; values below 1 are
+	// treated as a single attempt.
+	Attempts int
+	// Backoff determines the delay between attempts.
+	Backoff Backoff
+}
+
+// Retry executes fn until it succeeds, the attempts permitted by the policy
+// are exhausted, or the context is cancelled. It's intended for finite
+// operations *inside* a worker - such as dialing a remote service - and is
+// distinct from the Supervisor restarting the worker itself. The backoff is
+// measured by the context's Clock; see ClockFrom.
+func Retry(ctx context.Context, fn func(context.Context) error, policy RetryPolicy) error {
+	var (
+		err   error
+		timer Timer
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for attempt := 0; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt+1 >= policy.Attempts {
+			return fmt.Errorf("supervisor: retries exhausted after %d attempts: %w", attempt+1, err)
+		}
+
+		timer = rearm(timer, ClockFrom(ctx), policy.Backoff.Duration(attempt))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C():
+		}
+	}
+}
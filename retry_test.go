@@ -0,0 +1,79 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test error")
+
+func Test_BackoffMustGrowUntilMax(t *testing.T) {
+	b := Backoff{Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond, Multiplier: 2}
+
+	expected := []time.Duration{10, 20, 40, 50, 50}
+	for attempt, d := range expected {
+		if got := b.Duration(attempt); got != d*time.Millisecond {
+			t.Error("unexpected backoff duration", attempt, got)
+		}
+	}
+}
+
+func Test_BackoffMustNotOverflowWithoutMax(t *testing.T) {
+	b := Backoff{Initial: time.Second, Multiplier: 2}
+
+	previous := time.Duration(0)
+	for attempt := 0; attempt < 1000; attempt++ {
+		got := b.Duration(attempt)
+		if got < previous {
+			t.Fatal("expected the backoff to never decrease", attempt, got)
+		}
+		previous = got
+	}
+
+	if previous != time.Duration(math.MaxInt64) {
+		t.Error("expected the backoff to be capped at the longest Duration", previous)
+	}
+}
+
+func Test_RetryMustStopOnSuccess(t *testing.T) {
+	nCalls := 0
+	err := Retry(context.Background(), func(context.Context) error {
+		nCalls++
+		if nCalls < 3 {
+			return errTest
+		}
+		return nil
+	}, RetryPolicy{Attempts: 5, Backoff: Backoff{Initial: time.Millisecond}})
+
+	if err != nil || nCalls != 3 {
+		t.Error("retry did not stop after success", err, nCalls)
+	}
+}
+
+func Test_RetryMustReturnErrorWhenExhausted(t *testing.T) {
+	nCalls := 0
+	err := Retry(context.Background(), func(context.Context) error {
+		nCalls++
+		return errTest
+	}, RetryPolicy{Attempts: 3, Backoff: Backoff{Initial: time.Millisecond}})
+
+	if !errors.Is(err, errTest) || nCalls != 3 {
+		t.Error("retry did not give up after the permitted attempts", err, nCalls)
+	}
+}
+
+func Test_RetryMustRespectContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Retry(ctx, func(context.Context) error {
+		return errTest
+	}, RetryPolicy{Attempts: 3, Backoff: Backoff{Initial: time.Second}})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Error("retry did not return the context error", err)
+	}
+}
@@ -0,0 +1,54 @@
+package supervisor
+
+import "sync"
+
+// Actor returns the Supervisor as a pair of functions satisfying
+// github.com/oklog/run's Group.Add, allowing it to participate in an
+// application lifecycle orchestrated that way:
+//
+//	var g run.Group
+//	g.Add(s.Actor())
+//	g.Add(run.SignalHandler(ctx, os.Interrupt))
+//	err := g.Run()
+//
+// execute runs the Supervisor, blocking until it's stopped and its workers
+// have exited, and returns the Cause of it stopping; interrupt shuts the
+// Supervisor down gracefully, bounded by its ShutdownTimeout. Should
+// interrupt be called before execute then execute returns immediately.
+func (s *Supervisor) Actor() (execute func() error, interrupt func(error)) {
+	var (
+		mu          sync.Mutex
+		interrupted bool
+	)
+
+	execute = func() error {
+		mu.Lock()
+		if interrupted {
+			mu.Unlock()
+			return nil
+		}
+		s.Run()
+		s.mu.Lock()
+		ctx, stopping := s.ctx, s.stopping
+		s.mu.Unlock()
+		mu.Unlock()
+
+		// The Supervisor also stops upon its parent context being cancelled.
+		select {
+		case <-stopping:
+		case <-ctx.Done():
+		}
+		s.Wait()
+		return s.Cause()
+	}
+
+	interrupt = func(error) {
+		mu.Lock()
+		interrupted = true
+		mu.Unlock()
+
+		s.gracefulShutdown()
+	}
+
+	return execute, interrupt
+}
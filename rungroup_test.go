@@ -0,0 +1,58 @@
+package supervisor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_ActorMustRunUntilInterrupted(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ms := &mockSupervisable{}
+	s := NewSimpleSupervisor(context.Background(), generateSupervisable(ms))
+	execute, interrupt := s.Actor()
+
+	result := make(chan error, 1)
+	go func() { result <- execute() }()
+
+	<-time.After(time.Millisecond * 50)
+	select {
+	case <-result:
+		t.Fatal("execute returned before being interrupted")
+	default:
+	}
+
+	interrupt(nil)
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Error("unexpected error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("execute not unblocked by interrupt")
+	}
+
+	if ms.isRunning {
+		t.Error("workers not stopped by interrupt")
+	}
+}
+
+func Test_ActorMustNotRunOnceInterrupted(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ms := &mockSupervisable{}
+	s := NewSimpleSupervisor(context.Background(), generateSupervisable(ms))
+	execute, interrupt := s.Actor()
+
+	interrupt(nil)
+	if err := execute(); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	if ms.nCalls != 0 {
+		t.Error("supervisable called after interrupt")
+	}
+}
@@ -0,0 +1,55 @@
+package supervisor
+
+import "fmt"
+
+// WithSchedule runs the Job whenever the cron expression activates; see
+// ParseSchedule for the syntax accepted. The schedule is run by a worker in
+// the Supervisor's default group, named after the Job - or "schedule-n"
+// where no JobName is given - and is started immediately should the
+// Supervisor already be running.
+//
+// Each run of the Job is supervised: panics are recovered and recorded as
+// failures, runs are bounded by any JobTimeout, and runs which become due
+// whilst a previous run is in progress are handled according to OnOverlap.
+// The status of each Job is available via Schedules.
+//
+//	s.WithSchedule("*/5 * * * *", purgeExpired, supervisor.JobName("purge"))
+func (s *Supervisor) WithSchedule(expr string, fn Job, opts ...JobOption) error {
+	schedule, err := ParseSchedule(expr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	defaultName := fmt.Sprintf("schedule-%d", len(s.schedules))
+	r := newJobRunner(fn, expr, append([]JobOption{JobName(defaultName)}, opts...))
+
+	specs := []WorkerSpec{{Name: r.opts.name, Worker: r.run(schedule.Next)}}
+	if err := s.takenLocked(specs).addSpecs(specs); err != nil {
+		return err
+	}
+	s.schedules = append(s.schedules, r)
+
+	workers := newWorkers(specs, s.historySize, s.groups[0])
+	s.addWorkersLocked(workers)
+	if s.groups[0].isRunning() {
+		s.startWorkersLocked(workers)
+	}
+
+	return nil
+}
+
+// Schedules returns the status of every Job added via WithSchedule.
+func (s *Supervisor) Schedules() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]JobInfo, len(s.schedules))
+	for i, r := range s.schedules {
+		infos[i] = r.stats()
+	}
+
+	return infos
+}
@@ -0,0 +1,170 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_ScheduleMustFindNextActivation(t *testing.T) {
+	from := time.Date(2021, time.March, 31, 23, 58, 30, 0, time.UTC)
+
+	cases := map[string]time.Time{
+		"* * * * *":          time.Date(2021, time.March, 31, 23, 59, 0, 0, time.UTC),
+		"*/5 * * * *":        time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"30 9 * * mon-fri":   time.Date(2021, time.April, 1, 9, 30, 0, 0, time.UTC),
+		"0 0 1,15 * *":       time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"0 12 * feb *":       time.Date(2022, time.February, 1, 12, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":         time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 13 * 5":         time.Date(2021, time.April, 2, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 7":          time.Date(2021, time.April, 4, 0, 0, 0, 0, time.UTC),
+		"@hourly":            time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"0-10/5 22-23 * * *": time.Date(2021, time.April, 1, 22, 0, 0, 0, time.UTC),
+	}
+
+	for expr, expected := range cases {
+		s, err := ParseSchedule(expr)
+		if err != nil {
+			t.Error(expr, err)
+			continue
+		}
+
+		if next := s.Next(from); !next.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s", expr, expected, next)
+		}
+	}
+
+	never, _ := ParseSchedule("0 0 30 2 *")
+	if !never.Next(from).IsZero() {
+		t.Error("expected an impossible schedule to never activate")
+	}
+}
+
+func Test_ScheduleMustRejectInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * * * foo", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := ParseSchedule(expr); !errors.Is(err, ErrInvalidSchedule) {
+			t.Error("expected expression to be rejected", expr, err)
+		}
+	}
+}
+
+func Test_JobMustApplyOverlapPolicy(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	for overlap, expected := range map[Overlap]int32{SkipOverlap: 1, QueueOverlap: 2, AllowOverlap: 3} {
+		var runs int32
+		r := newJobRunner(func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			<-time.After(time.Millisecond * 50)
+			return nil
+		}, "", []JobOption{OnOverlap(overlap)})
+
+		for i := 0; i < 3; i++ {
+			r.trigger(context.Background())
+		}
+		r.wait()
+
+		if atomic.LoadInt32(&runs) != expected {
+			t.Errorf("overlap %d: expected %d runs, got %d", overlap, expected, runs)
+		}
+
+		if info := r.stats(); info.Running != 0 || info.Runs != int(expected) {
+			t.Errorf("overlap %d: unexpected statistics %+v", overlap, info)
+		}
+	}
+}
+
+func Test_JobMustRecoverPanicsAndTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	r := newJobRunner(func(ctx context.Context) error {
+		panic("testing")
+	}, "", nil)
+	r.trigger(context.Background())
+	r.wait()
+
+	if info := r.stats(); info.Failures != 1 || info.LastError == nil {
+		t.Error("expected the panic to be recorded as a failure", info)
+	}
+
+	r = newJobRunner(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, "", []JobOption{JobTimeout(time.Millisecond * 20)})
+	r.trigger(context.Background())
+	r.wait()
+
+	if info := r.stats(); !errors.Is(info.LastError, context.DeadlineExceeded) {
+		t.Error("expected the run to be cancelled by its timeout", info)
+	}
+}
+
+func Test_SupervisorMustRunSchedules(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := func(ctx context.Context) error { return nil }
+	if err := s.WithSchedule("*/5 * * * *", job, JobName("purge")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.WithSchedule("*/5 * * * *", job, JobName("purge")); !errors.Is(err, ErrDuplicateName) {
+		t.Error("expected a second job with the same name to be rejected", err)
+	}
+
+	s.Run()
+	<-time.After(time.Millisecond * 20)
+
+	schedules := s.Schedules()
+	if len(schedules) != 1 || schedules[0].Name != "purge" || schedules[0].Next.Minute()%5 != 0 {
+		t.Error("expected the schedule's next run to be available", schedules)
+	}
+
+	if workers := s.WorkerInfo("purge"); len(workers) != 1 || !workers[0].Running || !s.Ready() {
+		t.Error("expected the schedule to be run by a ready worker", workers)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_SupervisorMustRejectConcurrentSchedulesOfTheSameName(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	var added int32
+	job := func(ctx context.Context) error { return nil }
+	results := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		go func() {
+			if s.WithSchedule("*/5 * * * *", job, JobName("purge")) == nil {
+				atomic.AddInt32(&added, 1)
+			}
+			results <- struct{}{}
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		<-results
+	}
+
+	if n := atomic.LoadInt32(&added); n != 1 || len(s.Schedules()) != 1 || len(s.WorkerInfo("purge")) != 1 {
+		t.Error("expected exactly one schedule of the name to be added", n, s.Schedules())
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
@@ -0,0 +1,58 @@
+package supervisor
+
+import (
+	"context"
+	"sort"
+)
+
+// Shutdown stops the Supervisor's workers in order of their ShutdownClass,
+// waiting for every worker in a class to stop before moving on to the next.
+// Should the context be cancelled before then, the remaining workers are
+// all stopped immediately and the context's error is returned.
+//
+// Should leak diagnostics be enabled then, once the workers have been
+// stopped, any worker goroutines still running are reported; see
+// WithLeakDiagnostics.
+func (s *Supervisor) Shutdown(ctx context.Context) error {
+	err := s.shutdown(ctx)
+	s.Stop()
+	s.diagnoseLeaks()
+
+	return err
+}
+
+func (s *Supervisor) shutdown(ctx context.Context) error {
+	s.markStopping()
+
+	for _, class := range s.shutdownClasses() {
+		if err := stopWorkers(ctx, class); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// shutdownClasses groups the Supervisor's workers by ShutdownClass, in the
+// order they should be stopped.
+func (s *Supervisor) shutdownClasses() [][]*worker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byClass := make(map[int][]*worker)
+	keys := []int{}
+	for _, w := range s.workers {
+		if _, ok := byClass[w.class]; !ok {
+			keys = append(keys, w.class)
+		}
+		byClass[w.class] = append(byClass[w.class], w)
+	}
+
+	sort.Ints(keys)
+	classes := make([][]*worker, len(keys))
+	for i, k := range keys {
+		classes[i] = byClass[k]
+	}
+
+	return classes
+}
@@ -0,0 +1,122 @@
+package supervisor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// SignalError is the Cause of a Supervisor which was stopped upon receipt
+// of an OS signal.
+type SignalError struct {
+	// Signal is the signal which was received.
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("supervisor: received signal %s", e.Signal)
+}
+
+// SignalAction is performed by a Supervisor upon receipt of a signal; see
+// Options.Signals.
+type SignalAction func(*Supervisor, os.Signal)
+
+// ShutdownAction gracefully shuts down the Supervisor via Shutdown - bounded
+// by its ShutdownTimeout - and records the signal as the Supervisor's Cause.
+func ShutdownAction() SignalAction {
+	return func(s *Supervisor, sig os.Signal) {
+		log(fmt.Sprintf("received signal %s, shutting down", sig))
+		s.setCause(&SignalError{Signal: sig})
+		s.gracefulShutdown()
+	}
+}
+
+// ReloadAction invokes the reload callback, and then performs a rolling
+// restart of the named workers so that they pick up any changes; should
+// reload return an error then no workers are restarted.
+func ReloadAction(reload func() error, workers ...string) SignalAction {
+	return func(s *Supervisor, sig os.Signal) {
+		log(fmt.Sprintf("received signal %s, reloading", sig))
+		if err := reload(); err != nil {
+			log(fmt.Sprintf("reload failed: %s", err))
+			return
+		}
+
+		s.RestartWorkers(workers...)
+	}
+}
+
+// RestartGroupAction performs a rolling restart of the named group.
+func RestartGroupAction(name string) SignalAction {
+	return func(s *Supervisor, sig os.Signal) {
+		log(fmt.Sprintf("received signal %s, restarting group %s", sig, name))
+		s.RestartGroup(name)
+	}
+}
+
+// DumpAction writes a snapshot of the Supervisor's workers - along with
+// those of any nested Supervisors - to w; see DumpTree.
+func DumpAction(w io.Writer) SignalAction {
+	return func(s *Supervisor, sig os.Signal) {
+		s.DumpTree(w)
+	}
+}
+
+// NotifySignals gracefully shuts down the Supervisor - via Shutdown - upon
+// receipt of any of the given signals; the signal is then available via the
+// Supervisor's Cause. The returned function stops listening for signals, and
+// should be called if the Supervisor is stopped by other means.
+//
+//	defer supervisor.NotifySignals(s, syscall.SIGTERM, syscall.SIGINT)()
+//	s.Run()
+//	s.Wait()
+func NotifySignals(s *Supervisor, sigs ...os.Signal) func() {
+	actions := make(map[os.Signal]SignalAction, len(sigs))
+	for _, sig := range sigs {
+		actions[sig] = ShutdownAction()
+	}
+
+	return handleSignals(s, actions)
+}
+
+// NotifyReload performs a ReloadAction upon receipt of SIGHUP. The returned
+// function stops listening for the signal.
+func NotifyReload(s *Supervisor, reload func() error, workers ...string) func() {
+	return handleSignals(s, map[os.Signal]SignalAction{
+		syscall.SIGHUP: ReloadAction(reload, workers...),
+	})
+}
+
+// handleSignals performs the corresponding action for each signal received,
+// until the returned function is called.
+func handleSignals(s *Supervisor, actions map[os.Signal]SignalAction) func() {
+	sigs := make([]os.Signal, 0, len(actions))
+	for sig := range actions {
+		sigs = append(sigs, sig)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, sigs...)
+
+	quit := make(chan struct{})
+	go func() {
+		defer signal.Stop(sigCh)
+
+		for {
+			select {
+			case sig := <-sigCh:
+				actions[sig](s, sig)
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() { close(quit) })
+	}
+}
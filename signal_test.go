@@ -0,0 +1,150 @@
+//go:build !windows
+// +build !windows
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_NotifySignalsMustShutdownSupervisor(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ms := &mockSupervisable{}
+	s := NewSimpleSupervisor(context.Background(), generateSupervisable(ms))
+
+	stop := NotifySignals(s, syscall.SIGUSR1)
+	defer stop()
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	p, _ := os.FindProcess(os.Getpid())
+	p.Signal(syscall.SIGUSR1)
+
+	s.Wait()
+
+	var sigErr *SignalError
+	if !errors.As(s.Cause(), &sigErr) || sigErr.Signal != syscall.SIGUSR1 {
+		t.Error("received signal not exposed as the stop cause", s.Cause())
+	}
+
+	if !ms.ctxStopped {
+		t.Error("worker was not stopped upon receipt of the signal")
+	}
+}
+
+func Test_NotifyReloadMustRestartDesignatedWorkers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var reloaded, restarted, untouched int32
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{Name: "reloadable", Worker: countingWorker(&restarted), Count: 2},
+			{Name: "static", Worker: countingWorker(&untouched)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := NotifyReload(s, func() error {
+		atomic.AddInt32(&reloaded, 1)
+		return nil
+	}, "reloadable")
+	defer stop()
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	p, _ := os.FindProcess(os.Getpid())
+	p.Signal(syscall.SIGHUP)
+	<-time.After(time.Millisecond * 50)
+
+	s.Stop()
+	s.Wait()
+
+	if n := atomic.LoadInt32(&reloaded); n != 1 {
+		t.Error("reload callback not invoked", n)
+	}
+
+	if n, m := atomic.LoadInt32(&restarted), atomic.LoadInt32(&untouched); n != 4 || m != 1 {
+		t.Error("unexpected workers restarted", n, m)
+	}
+
+	for _, info := range s.ListWorkers() {
+		if info.Restarts != 0 {
+			t.Error("requested restart recorded as a failure", info)
+		}
+	}
+}
+
+// lockedBuffer is a bytes.Buffer which may be written to by the signal
+// handler whilst being read by the test.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func Test_SupervisorMustPerformConfiguredSignalActions(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var cache, other int32
+	dump := &lockedBuffer{}
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "other", Worker: countingWorker(&other)}},
+		Groups: []Group{{
+			Name:    "cache",
+			Workers: []WorkerSpec{{Name: "cache", Worker: countingWorker(&cache)}},
+		}},
+		Signals: map[os.Signal]SignalAction{
+			syscall.SIGUSR1: RestartGroupAction("cache"),
+			syscall.SIGUSR2: DumpAction(dump),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	p, _ := os.FindProcess(os.Getpid())
+	p.Signal(syscall.SIGUSR1)
+	<-time.After(time.Millisecond * 50)
+	p.Signal(syscall.SIGUSR2)
+	<-time.After(time.Millisecond * 50)
+
+	s.Stop()
+	s.Wait()
+	<-time.After(time.Millisecond * 10)
+
+	if n, m := atomic.LoadInt32(&cache), atomic.LoadInt32(&other); n != 2 || m != 1 {
+		t.Error("signal did not restart the configured group", n, m)
+	}
+
+	if !strings.Contains(dump.String(), "cache[0]") {
+		t.Error("signal did not dump a snapshot of the workers", dump.String())
+	}
+}
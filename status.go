@@ -0,0 +1,133 @@
+package supervisor
+
+import "time"
+
+// WorkerState is the state of a worker instance, as reported by Status.
+type WorkerState int
+
+const (
+	// StateStopped instances aren't running, and won't be restarted; this
+	// includes instances which have yet to be started.
+	StateStopped WorkerState = iota
+	// StateStarting instances are due to begin a run, but are waiting to do
+	// so - such as whilst the Supervisor is paused, or its concurrency limit
+	// is reached.
+	StateStarting
+	// StateRunning instances are running, but haven't reported themselves
+	// as ready; see Ready.
+	StateRunning
+	// StateReady instances are running, and have reported themselves as
+	// ready.
+	StateReady
+	// StateBackingOff instances have exited, and are waiting for the delay
+	// before their restart to elapse.
+	StateBackingOff
+	// StateQuarantined instances belong to a group which exceeded its
+	// restart budget, and was stopped as a result.
+	StateQuarantined
+)
+
+var stateNames = map[WorkerState]string{
+	StateStopped:     "stopped",
+	StateStarting:    "starting",
+	StateRunning:     "running",
+	StateReady:       "ready",
+	StateBackingOff:  "backing_off",
+	StateQuarantined: "quarantined",
+}
+
+func (s WorkerState) String() string {
+	return stateNames[s]
+}
+
+// MarshalText satisfies encoding.TextMarshaler.
+func (s WorkerState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// WorkerStatus describes the current state of a single worker instance,
+// with enough detail to build a status page from.
+type WorkerStatus struct {
+	// Name is the name of the WorkerSpec the instance belongs to.
+	Name string
+	// Instance is the index of the instance, starting at 0.
+	Instance int
+	// Group is the name of the group the instance belongs to; the default
+	// group is unnamed.
+	Group string
+	// State is the state of the instance.
+	State WorkerState
+	// Uptime is how long the current run of the instance has lasted.
+	Uptime time.Duration
+	// Attempt is the number of the instance's current, or most recent, run;
+	// the first run is attempt 1, and it's zero if the instance has never
+	// been run.
+	Attempt int
+	// Restarts is the number of times the instance has been restarted after
+	// exiting unexpectedly.
+	Restarts int
+	// LastExit is the most recent exit which led to the instance being
+	// restarted, should there be one.
+	LastExit *Exit
+	// NextRestart is when the instance will be restarted, whilst it's
+	// backing off.
+	NextRestart time.Time
+}
+
+// Status returns the status of every worker instance managed by the
+// Supervisor. As with ListWorkers, it's gathered without the Supervisor's
+// lock held.
+func (s *Supervisor) Status() []WorkerStatus {
+	workers := s.snapshotWorkers()
+
+	statuses := make([]WorkerStatus, len(workers))
+	for i, w := range workers {
+		statuses[i] = w.status()
+	}
+
+	return statuses
+}
+
+func (w *worker) status() WorkerStatus {
+	w.mu.Lock()
+	status := WorkerStatus{
+		Name:        w.name,
+		Instance:    w.instance,
+		Group:       w.group.name,
+		State:       w.state,
+		Attempt:     w.runs,
+		Restarts:    w.restarts,
+		LastExit:    w.history.last(),
+		NextRestart: w.nextRestart,
+	}
+
+	switch {
+	case w.running:
+		status.Uptime = w.clock.Now().Sub(w.startedAt)
+		status.State = StateRunning
+		if w.ready {
+			status.State = StateReady
+		}
+	case w.state == StateBackingOff && !w.clock.Now().Before(w.nextRestart):
+		// The delay has elapsed, so the instance is about to be started.
+		status.State = StateStarting
+		status.NextRestart = time.Time{}
+	}
+	w.mu.Unlock()
+
+	if status.State == StateStopped && w.group.isQuarantined() {
+		status.State = StateQuarantined
+	}
+
+	return status
+}
+
+// backingOff records that the instance will be restarted once the delay
+// has elapsed.
+func (w *worker) backingOff(until time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.state = StateBackingOff
+	w.nextRestart = until
+}
@@ -0,0 +1,116 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_StatusMustDescribeEachInstance(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{Name: "ready", Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+				Ready(ctx)
+				<-ctx.Done()
+			}},
+			{Name: "running", Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+				<-ctx.Done()
+			}},
+			{Name: "failing", Worker: failingWorker},
+		},
+		Policy: RestartPolicy{Backoff: Backoff{Initial: time.Hour}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := s.Status(); len(status) != 3 || status[0].State != StateStopped || status[0].Attempt != 0 {
+		t.Error("expected instances which haven't been started to be stopped", status)
+	}
+
+	s.Run()
+	<-time.After(time.Millisecond * 50)
+
+	status := s.Status()
+	if ready := status[0]; ready.State != StateReady || ready.Attempt != 1 || ready.Uptime <= 0 {
+		t.Error("expected the instance to be ready", ready)
+	}
+
+	if running := status[1]; running.State != StateRunning || running.LastExit != nil {
+		t.Error("expected the instance to be running", running)
+	}
+
+	failing := status[2]
+	if failing.State != StateBackingOff || failing.Attempt != 1 || failing.Restarts != 1 {
+		t.Error("expected the instance to be backing off", failing)
+	}
+
+	if failing.LastExit == nil || failing.LastExit.Reason != "failed" {
+		t.Error("expected the exit to be reported", failing.LastExit)
+	}
+
+	if until := time.Until(failing.NextRestart); until < time.Minute*59 || until > time.Hour {
+		t.Error("expected the time of the next restart", failing.NextRestart)
+	}
+
+	s.Stop()
+	s.Wait()
+	<-time.After(time.Millisecond * 50)
+
+	for _, status := range s.Status() {
+		if status.State != StateStopped || !status.NextRestart.IsZero() {
+			t.Error("expected every instance to be stopped", status)
+		}
+	}
+}
+
+func Test_StatusMustReportQuarantinedGroups(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "healthy", Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			<-ctx.Done()
+		}}},
+		Groups: []Group{{
+			Name: "flaky",
+			Workers: []WorkerSpec{
+				{Name: "failing", Worker: failingWorker},
+				{Name: "sibling", Worker: func(ctx context.Context, done chan struct{}) {
+					defer Recover(ctx, done)
+					<-ctx.Done()
+				}},
+			},
+			Policy: RestartPolicy{MaxRestarts: 2, Period: time.Minute},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+	<-time.After(time.Millisecond * 50)
+
+	states := map[string]WorkerState{}
+	for _, status := range s.Status() {
+		states[status.Name] = status.State
+	}
+
+	if states["healthy"] != StateRunning || states["failing"] != StateQuarantined || states["sibling"] != StateQuarantined {
+		t.Error("expected the group which exceeded its budget to be quarantined", states)
+	}
+
+	if text, _ := json.Marshal(StateQuarantined); string(text) != `"quarantined"` {
+		t.Error("expected the state to be marshalled by name", string(text))
+	}
+
+	s.Stop()
+	s.Wait()
+	<-time.After(time.Millisecond * 50)
+}
@@ -6,7 +6,10 @@ package supervisor
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,17 +23,54 @@ import (
 // 3. The Supervisable **must** ensure that `recover()` is called.
 type Supervisable func(context.Context, chan struct{})
 
+// Func adapts a function with the context-only signature to a Supervisable,
+// satisfying the requirements above on its behalf. The function should run
+// until its context is cancelled; should it return an error beforehand then
+// that's reported as the reason for its exit, via ReportError.
+func Func(fn func(context.Context) error) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			ReportError(ctx, err)
+		}
+	}
+}
+
 // Supervisor is the basic Supervision Tree supervisor node. It's capable
 // of monitoring a given goroutine and restarting it upon failure, as well
 // as terminating or restarting it upon request.
 type Supervisor struct {
-	isSimple       bool
-	workers        []Supervisable
-	ctx            context.Context
-	stop           context.CancelFunc
-	wg             *sync.WaitGroup
-	workerCount    int
-	runningWorkers int
+	name            string
+	isSimple        bool
+	groups          []*group
+	workers         []*worker
+	parent          context.Context
+	ctx             context.Context
+	stop            context.CancelFunc
+	wg              *sync.WaitGroup
+	metrics         Metrics
+	mu              sync.Mutex
+	running         sync.WaitGroup
+	runningWorkers  int32
+	cause           error
+	stopping        chan struct{}
+	signals         map[os.Signal]SignalAction
+	shutdownTimeout time.Duration
+	historySize     int
+	config          *Config
+	schedules       []*jobRunner
+	delayed         int
+	goroutines      map[uint64]*worker
+	leaks           []Leak
+	// hooks holds the *runHooks consulted by each run of a worker.
+	hooks atomic.Value
+	// subscribers holds the []*subscription of the streams returned by
+	// Events.
+	subscribers atomic.Value
+	// byName indexes the workers by name, so that looking up, adding and
+	// removing workers doesn't require scanning every one of them.
+	byName map[string][]*worker
 }
 
 // NewSimpleSupervisor returns a supervisor which can only run a single
@@ -38,12 +78,18 @@ type Supervisor struct {
 // enough.
 func NewSimpleSupervisor(ctx context.Context, worker Supervisable) *Supervisor {
 	supervisorCtx, cancel := context.WithCancel(ctx)
-	return &Supervisor{
+	g := newGroup("", RestartPolicy{})
+	s := &Supervisor{
 		isSimple: true,
-		workers:  []Supervisable{worker},
+		groups:   []*group{g},
+		parent:   ctx,
 		ctx:      supervisorCtx,
 		stop:     cancel,
+		stopping: make(chan struct{}),
 	}
+
+	s.addWorkersLocked(newWorkers(specsFromWorkers([]Supervisable{worker}, 1), DefaultHistorySize, g))
+	return s
 }
 
 // Options holds basic configuration information for the Supervisor.
@@ -54,62 +100,450 @@ type Options struct {
 	// Workers is a slice of different Supervisable workers, these will
 	// all be executed with WorkerCount instances
 	Workers []Supervisable
+	// Specs is a slice of named workers, each with their own instance
+	// count; these are executed in addition to any Workers.
+	Specs []WorkerSpec
+	// Policy is the RestartPolicy applied to Workers and Specs.
+	Policy RestartPolicy
+	// Groups partitions additional workers in to named groups, each with
+	// their own RestartPolicy.
+	Groups []Group
 	// Context allows a parent context.Context object to be used, useful
 	// where there are external timeouts or cancellations that may occur
 	// further up the call chain.
 	Context context.Context
 	// Waiter allows the caller to block until the Supervisor has completed.
 	Waiter *sync.WaitGroup
+	// Metrics is notified whenever a worker is restarted.
+	Metrics Metrics
+	// HistorySize is the number of exits to retain for each worker
+	// instance; it defaults to DefaultHistorySize.
+	HistorySize int
+	// Signals maps OS signals to the action the Supervisor should perform
+	// upon receiving them; the Supervisor listens for these signals whilst
+	// it's running.
+	Signals map[os.Signal]SignalAction
+	// ShutdownTimeout bounds any graceful Shutdown initiated by the
+	// Supervisor itself, such as upon receiving a signal; zero means there
+	// is no limit.
+	ShutdownTimeout time.Duration
+	// ConcurrencyLimit bounds the number of worker runs executing at once;
+	// zero means there is no limit. See Supervisor.WithConcurrencyLimit.
+	ConcurrencyLimit int
+	// ContextDecorator is applied to the context of each worker run; see
+	// Supervisor.WithContextDecorator.
+	ContextDecorator ContextDecorator
+	// Clock is the source of time for the Supervisor and its workers; see
+	// ClockFrom. It defaults to the Clock of the Context, if any, and
+	// otherwise to RealClock.
+	Clock Clock
+	// LeakGracePeriod enables leak diagnostics upon Shutdown; see
+	// Supervisor.WithLeakDiagnostics.
+	LeakGracePeriod time.Duration
+	// ResourceStats enables the accounting of resources by group; see
+	// Supervisor.WithResourceStats.
+	ResourceStats bool
+	// Pressure enables the throttling of restarts whilst the process is
+	// under pressure; see Supervisor.WithPressureThrottling.
+	Pressure *PressurePolicy
 }
 
 // NewSupervisorWithOptions configures a new Supervisor using any options
-// specified by the Options struct.
-func NewSupervisorWithOptions(opts *Options) *Supervisor {
+// specified by the Options struct. The Options are validated first, and an
+// error - such as ErrNilWorker or ErrDuplicateName - is returned should they
+// be invalid.
+func NewSupervisorWithOptions(opts *Options) (*Supervisor, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
 	ctx := opts.Context
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	ctx = withClock(ctx, opts.Clock)
 	supervisorCtx, cancel := context.WithCancel(ctx)
 
-	return &Supervisor{
-		workers:     opts.Workers,
-		workerCount: opts.WorkerCount,
-		ctx:         supervisorCtx,
-		stop:        cancel,
+	specs := append(specsFromWorkers(opts.Workers, opts.WorkerCount), opts.Specs...)
+	defaultGroup := newGroup("", opts.Policy)
+
+	groups := []*group{defaultGroup}
+	workers := newWorkers(specs, opts.HistorySize, defaultGroup)
+	for _, grp := range opts.Groups {
+		g := newGroup(grp.Name, grp.Policy)
+		groups = append(groups, g)
+		workers = append(workers, newWorkers(grp.Workers, opts.HistorySize, g)...)
+	}
+
+	s := &Supervisor{
+		groups:          groups,
+		parent:          ctx,
+		ctx:             supervisorCtx,
+		stop:            cancel,
+		stopping:        make(chan struct{}),
+		wg:              opts.Waiter,
+		metrics:         opts.Metrics,
+		signals:         opts.Signals,
+		shutdownTimeout: opts.ShutdownTimeout,
+		historySize:     opts.HistorySize,
 	}
+
+	s.addWorkersLocked(workers)
+	s.WithConcurrencyLimit(opts.ConcurrencyLimit)
+	s.WithContextDecorator(opts.ContextDecorator)
+	s.WithLeakDiagnostics(opts.LeakGracePeriod)
+	s.WithResourceStats(opts.ResourceStats)
+	s.WithPressureThrottling(opts.Pressure)
+	return s, nil
 }
 
 // Run is the entrypoint for the supervisor; calling run will configure
 // all the supplied Supervisables at the specified number of instances.
+//
+// If the Supervisor has previously been stopped then it's given a fresh
+// context, derived from the one it was originally created with.
 func (s *Supervisor) Run() {
-	for _, worker := range s.workers {
-		go s.runLoop(worker)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		s.ctx, s.stop = context.WithCancel(s.parent)
+		s.cause = nil
+		s.stopping = make(chan struct{})
+	}
+
+	if len(s.signals) > 0 {
+		stopSignals := handleSignals(s, s.signals)
+		go func(ctx context.Context) {
+			<-ctx.Done()
+			stopSignals()
+		}(s.ctx)
+	}
+
+	for _, g := range s.groups {
+		g.start(s.ctx)
 	}
+
+	s.startWorkersLocked(s.workers)
 }
 
-func (s *Supervisor) runLoop(worker Supervisable) {
+// startWorkerLocked launches the run loop for a worker instance, within the
+// context of its group.
+func (s *Supervisor) startWorkerLocked(w *worker) {
 	if s.wg != nil {
 		s.wg.Add(1)
-		defer s.wg.Done()
 	}
 
 	// BUG(): This is a quick hack, and should be handled via the WaitGroup
 	// Just need to work out how to handle `.WithWaitGroup(sync.WaitGroup)`
 	// calls that happen in conjunction with an internal pre-existing one.
-	s.runningWorkers++
+	atomic.AddInt32(&s.runningWorkers, 1)
+	s.running.Add(1)
+	ctx, exited := w.start(w.group.context())
+	go s.runLoop(ctx, exited, s.wg, w)
+}
+
+func (s *Supervisor) runLoop(ctx context.Context, exited chan struct{}, wg *sync.WaitGroup, w *worker) {
+	defer func() {
+		if wg != nil {
+			wg.Done()
+		}
+
+		atomic.AddInt32(&s.runningWorkers, -1)
+		s.running.Done()
+		s.publish(Event{Type: WorkerStopped, Name: w.name, Instance: w.instance})
+
+		close(exited)
+	}()
+
+	// backoff is reused across restarts, rather than allocating a Timer
+	// for each restart delay.
+	var backoff Timer
 	defer func() {
-		s.runningWorkers--
+		if backoff != nil {
+			backoff.Stop()
+		}
 	}()
 
+	clock := ClockFrom(ctx)
+	labelled := false
 	for {
+		hooks := s.runHooks()
+		if !hooks.awaitResume(ctx) {
+			w.stopped()
+			break
+		}
+
+		release, ok := hooks.acquireRun(ctx)
+		if !ok {
+			w.stopped()
+			break
+		}
+
+		var allocs allocSample
+		if hooks.resources {
+			if !labelled {
+				ctx, labelled = labelGoroutine(ctx, w), true
+			}
+			allocs = w.group.runStarted()
+		}
+
+		// The worker is run upon the run loop's own goroutine, rather than
+		// one spawned for each run; a worker which hands its work to another
+		// goroutine is still waited upon via its done channel.
 		isDone := make(chan struct{})
-		go worker(s.ctx, isDone)
+		run := w.started(ctx, clock)
+		s.publish(Event{Type: WorkerStarted, Name: w.name, Instance: w.instance})
+		s.tracked(w, hooks.leakGrace > 0, hooks.decorated(w, run), isDone)
 
 		<-isDone
-		if s.ctx.Err() != nil {
+		release()
+		if hooks.resources {
+			w.group.runEnded(allocs)
+		}
+		if w.restartRequested() && ctx.Err() == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			w.stopped()
 			break
 		}
+
+		var cpu time.Duration
+		if hooks.resources {
+			cpu = processCPUTime()
+		}
+
+		exit := run.report.get()
+		if w.temporary {
+			w.finished(exit)
+			if exit.Reason != nil {
+				s.publish(Event{Type: WorkerFailed, Name: w.name, Instance: w.instance, Exit: exit})
+			}
+			w.stopped()
+			break
+		}
+
+		if w.significant && exit.Reason == nil {
+			log(fmt.Sprintf("significant worker %s exited, stopping supervisor", w.name))
+			w.stopped()
+			s.Stop()
+			break
+		}
+
+		info := w.failed(exit)
+		if s.metrics != nil {
+			s.metrics.WorkerRestarted(info)
+		}
+		s.publish(Event{Type: WorkerFailed, Name: w.name, Instance: w.instance, Exit: exit})
+
+		delay, ok := w.group.restart(exit.Time)
+		if !ok {
+			w.stopped()
+			w.group.escalate(s)
+			break
+		}
+
+		for _, sibling := range w.group.siblings(w) {
+			sibling.restart()
+		}
+
+		if hooks.resources {
+			w.group.restarted(processCPUTime() - cpu)
+		}
+
+		if hooks.throttle != nil {
+			delay += hooks.throttle.delay(clock)
+		}
+
+		if delay > 0 {
+			w.backingOff(clock.Now().Add(delay))
+			backoff = rearm(backoff, ClockFrom(ctx), delay)
+			select {
+			case <-ctx.Done():
+				w.stopped()
+				return
+			case <-backoff.C():
+			}
+		}
+	}
+}
+
+// runHooks are the settings consulted by every run of a worker. They're
+// replaced as a whole whenever one of them changes, and read without the
+// Supervisor's lock, so that restarting workers don't contend upon it.
+type runHooks struct {
+	resume    chan struct{}
+	limit     chan struct{}
+	decorate  ContextDecorator
+	leakGrace time.Duration
+	resources bool
+	throttle  *pressureThrottle
+}
+
+var noHooks = &runHooks{}
+
+func (s *Supervisor) runHooks() *runHooks {
+	if hooks, ok := s.hooks.Load().(*runHooks); ok {
+		return hooks
 	}
+
+	return noHooks
+}
+
+// updateHooksLocked replaces the Supervisor's runHooks with a copy which has
+// been modified by fn; the Supervisor's lock must be held.
+func (s *Supervisor) updateHooksLocked(fn func(*runHooks)) {
+	hooks := *s.runHooks()
+	fn(&hooks)
+	s.hooks.Store(&hooks)
+}
+
+// ListWorkers returns the statistics for every worker instance managed by
+// the Supervisor. The statistics are gathered without the Supervisor's lock
+// held, so reading them never blocks workers from being restarted.
+func (s *Supervisor) ListWorkers() []WorkerInfo {
+	workers := s.snapshotWorkers()
+
+	infos := make([]WorkerInfo, len(workers))
+	for i, w := range workers {
+		infos[i] = w.info()
+	}
+
+	return infos
+}
+
+// snapshotWorkers returns a copy of the Supervisor's worker instances.
+func (s *Supervisor) snapshotWorkers() []*worker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*worker(nil), s.workers...)
+}
+
+// WorkerInfo returns the statistics for every instance of the named worker.
+func (s *Supervisor) WorkerInfo(name string) []WorkerInfo {
+	infos := []WorkerInfo{}
+	for _, w := range s.findWorkers(name) {
+		infos = append(infos, w.info())
+	}
+
+	return infos
+}
+
+// RestartWorkers performs a rolling restart of every instance of the named
+// workers; each instance is restarted in turn, with the next only being
+// restarted once the previous has started again.
+func (s *Supervisor) RestartWorkers(names ...string) {
+	for _, w := range s.findWorkers(names...) {
+		<-w.restart()
+	}
+}
+
+// RestartGroup performs a rolling restart of every worker in the named
+// group.
+func (s *Supervisor) RestartGroup(name string) {
+	for _, w := range s.groupWorkers(name) {
+		<-w.restart()
+	}
+}
+
+func (s *Supervisor) groupWorkers(name string) []*worker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := []*worker{}
+	for _, w := range s.workers {
+		if w.group.name == name {
+			found = append(found, w)
+		}
+	}
+
+	return found
+}
+
+func (s *Supervisor) findWorkers(names ...string) []*worker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := []*worker{}
+	for i, name := range names {
+		if duplicated(names[:i], name) {
+			continue
+		}
+
+		found = append(found, s.byName[name]...)
+	}
+
+	return found
+}
+
+func duplicated(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addWorkersLocked appends the worker instances to the Supervisor, indexing
+// them by name.
+func (s *Supervisor) addWorkersLocked(workers []*worker) {
+	if s.byName == nil {
+		s.byName = make(map[string][]*worker)
+	}
+
+	s.workers = append(s.workers, workers...)
+	for _, w := range workers {
+		s.byName[w.name] = append(s.byName[w.name], w)
+	}
+}
+
+// removeWorkersLocked removes the worker instances from the Supervisor, and
+// its index.
+func (s *Supervisor) removeWorkersLocked(removed map[*worker]bool) {
+	s.workers = removeWorkers(s.workers, removed)
+
+	names := map[string]bool{}
+	for w := range removed {
+		names[w.name] = true
+	}
+
+	for name := range names {
+		if kept := removeWorkers(s.byName[name], removed); len(kept) > 0 {
+			s.byName[name] = kept
+		} else {
+			delete(s.byName, name)
+		}
+	}
+}
+
+// takenLocked returns the names of the given specs which are already taken
+// by the Supervisor's workers.
+func (s *Supervisor) takenLocked(specs []WorkerSpec) nameSet {
+	names := nameSet{}
+	for _, spec := range specs {
+		if _, ok := s.byName[spec.Name]; ok {
+			names[spec.Name] = true
+		}
+	}
+
+	return names
+}
+
+// History returns the most recent exits of the given worker instance,
+// oldest first. It returns nil if there's no such instance.
+func (s *Supervisor) History(name string, instance int) []Exit {
+	for _, w := range s.findWorkers(name) {
+		if w.instance == instance {
+			return w.exits()
+		}
+	}
+
+	return nil
 }
 
 // Restart terminates the current worker goroutines, and then executes
@@ -117,26 +551,90 @@ func (s *Supervisor) runLoop(worker Supervisable) {
 // `Run` consecutively.
 func (s *Supervisor) Restart() {
 	s.Stop()
-	defer s.Run()
-
-	for {
-		// @todo - come on, man. This isn't the way.
-		<-time.After(time.Millisecond * 250)
-		if s.HasStopped() {
-			return
-		}
-	}
+	s.Wait()
+	s.Run()
 }
 
 // Stop terminates any current goroutines by simply invoking the context
 // cancellation function.
 func (s *Supervisor) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.markStoppingLocked()
 	s.stop()
 }
 
+// Clock returns the Clock used by the Supervisor and its workers; see
+// ClockFrom.
+func (s *Supervisor) Clock() Clock {
+	return ClockFrom(s.parent)
+}
+
 // HasStopped returns a boolean stating wheter the Supervisor is running.
 func (s *Supervisor) HasStopped() bool {
-	return (s.runningWorkers == 0)
+	return s.CurrentWorkerCount() == 0
+}
+
+// CurrentWorkerCount returns the number of worker instances whose run loops
+// are executing, including those waiting to be restarted. It's read
+// atomically, so never contends with workers starting or exiting.
+func (s *Supervisor) CurrentWorkerCount() int {
+	return int(atomic.LoadInt32(&s.runningWorkers))
+}
+
+// Wait blocks until all of the Supervisor's workers have stopped.
+func (s *Supervisor) Wait() {
+	s.running.Wait()
+}
+
+// WaitContext blocks until all of the Supervisor's workers have stopped, as
+// with Wait, or until the context is cancelled - in which case the context's
+// error is returned, allowing the caller to escalate should a worker fail to
+// stop.
+func (s *Supervisor) WaitContext(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitTimeout blocks until all of the Supervisor's workers have stopped, or
+// until the timeout passes, in which case context.DeadlineExceeded is
+// returned; see WaitContext.
+func (s *Supervisor) WaitTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return s.WaitContext(ctx)
+}
+
+// Cause returns the reason the Supervisor was stopped, if one was given;
+// for example a *SignalError when stopped via NotifySignals.
+func (s *Supervisor) Cause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cause
+}
+
+// setCause records the reason for the Supervisor being stopped; only the
+// first reason given is retained.
+func (s *Supervisor) setCause(cause error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cause == nil {
+		s.cause = cause
+	}
 }
 
 // WithWaitGroup allows a WaitGroup to be specified and incremented
@@ -146,3 +644,9 @@ func (s *Supervisor) HasStopped() bool {
 func (s *Supervisor) WithWaitGroup(wg *sync.WaitGroup) {
 	s.wg = wg
 }
+
+// WithMetrics specifies a Metrics implementation to be notified whenever
+// a worker is restarted.
+func (s *Supervisor) WithMetrics(m Metrics) {
+	s.metrics = m
+}
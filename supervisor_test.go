@@ -2,7 +2,9 @@ package supervisor
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -47,6 +49,16 @@ func generateSupervisable(ms *mockSupervisable) Supervisable {
 	}
 }
 
+// countingWorker runs until stopped, counting its runs; unlike a shared
+// mockSupervisable, it's safe for use by multiple instances at once.
+func countingWorker(runs *int32) Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+		atomic.AddInt32(runs, 1)
+		<-ctx.Done()
+	}
+}
+
 //
 // These tests monitor the basic functionality, but there's also a little
 // bit of magic behind the scenes in that we're also testing for leaking
@@ -142,6 +154,131 @@ func Test_SupervisorMustNotifyCallerWithWaitGroup(t *testing.T) {
 	}
 }
 
+func Test_SupervisorMustBoundWaitWithContext(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	release := make(chan struct{})
+	s := NewSimpleSupervisor(context.Background(), func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+		<-release
+	})
+	s.Run()
+
+	<-time.After(time.Millisecond * 20)
+	s.Stop()
+
+	if err := s.WaitTimeout(time.Millisecond * 50); err != context.DeadlineExceeded {
+		t.Error("expected the wait to time out whilst the worker ignores cancellation", err)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := s.WaitContext(ctx); err != nil {
+		t.Error("expected the wait to complete once the worker stopped", err)
+	}
+}
+
+func Test_SupervisorMustRestartWorkersWhilstStatisticsAreRead(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var (
+		once      sync.Once
+		observing = make(chan struct{})
+		release   = make(chan struct{})
+	)
+
+	s, err := NewSupervisorWithOptions(&Options{Specs: []WorkerSpec{
+		{Name: "queue", Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+
+			ObserveQueue(ctx, func() (int, int) {
+				once.Do(func() { close(observing) })
+				<-release
+				return 0, 1
+			})
+			<-ctx.Done()
+		}},
+		{Name: "restartable", Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			<-ctx.Done()
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+	defer s.Wait()
+	defer s.Stop()
+
+	<-time.After(time.Millisecond * 20)
+	if n := s.CurrentWorkerCount(); n != 2 {
+		t.Error("expected both workers to be counted", n)
+	}
+
+	listed := make(chan []WorkerInfo)
+	go func() { listed <- s.ListWorkers() }()
+	<-observing
+
+	restarted := make(chan struct{})
+	go func() {
+		s.RestartWorkers("restartable")
+		close(restarted)
+	}()
+
+	select {
+	case <-restarted:
+	case <-time.After(time.Second):
+		t.Error("expected the restart not to be blocked by reading statistics")
+	}
+
+	close(release)
+	if infos := <-listed; len(infos) != 2 || infos[0].QueueCapacity != 1 {
+		t.Error("expected the statistics to be read", infos)
+	}
+	<-restarted
+}
+
+func Test_SupervisorMustStartAndStopLargeWorkerSetsInBatches(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var started int64
+	worker := func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		atomic.AddInt64(&started, 1)
+		<-ctx.Done()
+	}
+
+	count := bulkBatchSize*2 + 1
+	s, err := NewSupervisorWithOptions(&Options{Specs: []WorkerSpec{
+		{Name: "bulk", Count: count, Worker: worker},
+		{Name: "removed", Count: count, Worker: worker},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	if n := s.CurrentWorkerCount(); n != count*2 {
+		t.Error("expected every instance to be started", n)
+	}
+
+	if !s.RemoveWorker("removed") || len(s.ListWorkers()) != count {
+		t.Error("expected every instance of the removed worker to be stopped", len(s.ListWorkers()))
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Error(err)
+	}
+	s.Wait()
+
+	if n := atomic.LoadInt64(&started); n != int64(count*2) {
+		t.Error("expected every instance to have run", n)
+	}
+}
+
 func Test_SupervisorShouldRestartWhenRequested(t *testing.T) {
 	defer goleak.VerifyNone(t)
 
@@ -159,3 +296,475 @@ func Test_SupervisorShouldRestartWhenRequested(t *testing.T) {
 		t.Error("supervisable not restarted", ms.nCalls)
 	}
 }
+
+type mockMetrics struct {
+	mu       sync.Mutex
+	restarts int
+}
+
+func (m *mockMetrics) WorkerRestarted(WorkerInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.restarts++
+}
+
+func Test_SupervisorMustTrackWorkerStatistics(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	flaky := func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(50 * time.Millisecond):
+			panic("testing")
+		}
+	}
+	metrics := &mockMetrics{}
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{Name: "flaky", Worker: flaky, Count: 2},
+		},
+		Metrics: metrics,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 130)
+	workers := s.ListWorkers()
+	s.Stop()
+	<-time.After(time.Millisecond * 100)
+
+	if len(workers) != 2 {
+		t.Fatal("expected an entry per worker instance", len(workers))
+	}
+
+	for i, info := range workers {
+		if info.Name != "flaky" || info.Instance != i {
+			t.Error("unexpected worker identity", info.Name, info.Instance)
+		}
+
+		if info.Restarts < 1 || info.LastFailure.IsZero() || info.MTBF == 0 {
+			t.Error("restart statistics not recorded", info)
+		}
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if metrics.restarts < 2 {
+		t.Error("metrics not notified of restarts", metrics.restarts)
+	}
+}
+
+func Test_SupervisorMustRecordExitHistory(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	nCalls := 0
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{
+			Name: "panicky",
+			Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+
+				nCalls++
+				if nCalls%2 == 0 {
+					ReportError(ctx, errTest)
+					return
+				}
+				panic("testing")
+			},
+		}},
+		HistorySize: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+
+	history := s.History("panicky", 0)
+	if len(history) != 3 {
+		t.Fatal("history not bounded by its size", len(history))
+	}
+
+	for i := 1; i < len(history); i++ {
+		if history[i].Time.Before(history[i-1].Time) {
+			t.Error("history not ordered oldest first")
+		}
+
+		if history[i].Panicked == history[i-1].Panicked {
+			t.Error("exit reasons not recorded for each run", history)
+		}
+	}
+
+	for _, exit := range history {
+		if exit.Panicked && (exit.Reason != "testing" || exit.StackDigest == "") {
+			t.Error("panic not recorded correctly", exit)
+		}
+
+		if !exit.Panicked && exit.Reason != errTest {
+			t.Error("reported error not recorded", exit)
+		}
+	}
+}
+
+func Test_FuncMustReportErrorsAndRecoverPanics(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	nCalls := 0
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{
+			Name: "func",
+			Worker: Func(func(ctx context.Context) error {
+				nCalls++
+				if nCalls%2 == 0 {
+					return errTest
+				}
+				panic("testing")
+			}),
+		}},
+		HistorySize: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+
+	history := s.History("func", 0)
+	if len(history) != 2 {
+		t.Fatal("function not restarted", len(history))
+	}
+
+	for _, exit := range history {
+		if exit.Panicked && exit.Reason != "testing" {
+			t.Error("panic not recovered", exit)
+		}
+
+		if !exit.Panicked && exit.Reason != errTest {
+			t.Error("returned error not reported", exit)
+		}
+	}
+}
+
+func Test_SupervisorMustStopWhenSignificantWorkerCompletes(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	helper := &mockSupervisable{}
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{
+				Name: "main",
+				Worker: func(ctx context.Context, done chan struct{}) {
+					defer Recover(ctx, done)
+					<-time.After(50 * time.Millisecond)
+				},
+				Significant: true,
+			},
+			{Name: "helper", Worker: generateSupervisable(helper)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 150)
+
+	if !s.HasStopped() {
+		t.Error("supervisor still running after significant worker completed")
+	}
+
+	if !helper.ctxStopped {
+		t.Error("remaining workers were not stopped")
+	}
+}
+
+func Test_SupervisorMustIsolateGroupRestartBudgets(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	flaky := &mockSupervisable{shouldPanic: true}
+	stable := &mockSupervisable{}
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "stable", Worker: generateSupervisable(stable)}},
+		Groups: []Group{{
+			Name:    "background",
+			Workers: []WorkerSpec{{Name: "flaky", Worker: generateSupervisable(flaky)}},
+			Policy: RestartPolicy{
+				MaxRestarts: 2,
+				Period:      time.Second,
+				Backoff:     Backoff{Initial: 10 * time.Millisecond},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 300)
+
+	if flaky.nCalls != 3 {
+		t.Error("group restarted beyond its budget", flaky.nCalls)
+	}
+
+	if !stable.isRunning {
+		t.Error("exhausted group affected workers outside of it")
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 100)
+}
+
+func Test_SupervisorMustShutdownInClassOrder(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	mu := sync.Mutex{}
+	order := []string{}
+	ordered := func(name string, delay time.Duration) Supervisable {
+		return func(ctx context.Context, done chan struct{}) {
+			defer close(done)
+
+			<-ctx.Done()
+			<-time.After(delay)
+
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{Name: "flusher", Worker: ordered("flusher", 0), ShutdownClass: 2},
+			{Name: "ingress", Worker: ordered("ingress", 50*time.Millisecond), ShutdownClass: 0},
+			{Name: "processor", Worker: ordered("processor", 25*time.Millisecond), ShutdownClass: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Error("unexpected shutdown error", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 3 || order[0] != "ingress" || order[1] != "processor" || order[2] != "flusher" {
+		t.Error("workers not stopped in class order", order)
+	}
+
+	if !s.HasStopped() {
+		t.Error("supervisor indicates it's still running")
+	}
+}
+
+func Test_SupervisorMustHoldWorkersWhilstPaused(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var running, calls int32
+	s := NewSimpleSupervisor(context.Background(), func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+		defer atomic.StoreInt32(&running, 0)
+
+		atomic.StoreInt32(&running, 1)
+		atomic.AddInt32(&calls, 1)
+		<-ctx.Done()
+	})
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	s.Pause()
+	<-time.After(time.Millisecond * 50)
+
+	if atomic.LoadInt32(&running) != 0 || !s.IsPaused() || s.HasStopped() {
+		t.Error("worker still running whilst paused")
+	}
+
+	s.Resume()
+	<-time.After(time.Millisecond * 50)
+
+	if n := atomic.LoadInt32(&calls); atomic.LoadInt32(&running) != 1 || n != 2 {
+		t.Error("worker not restarted upon resume", n)
+	}
+
+	s.Stop()
+	s.Wait()
+}
+
+func Test_SupervisorMustRestartGroupPerStrategy(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	// The failure is held back until every sibling is running, as a sibling
+	// which is yet to start has no run to restart.
+	release := make(chan struct{})
+	var first, failing, last int32
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{Name: "first", Worker: countingWorker(&first)},
+			{Name: "failing", Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+				if atomic.AddInt32(&failing, 1) == 1 {
+					<-release
+					panic("testing")
+				}
+				<-ctx.Done()
+			}},
+			{Name: "last", Worker: countingWorker(&last)},
+		},
+		Policy: RestartPolicy{Strategy: RestForOne},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	deadline := time.After(time.Second)
+	for running := 0; running < 3; {
+		select {
+		case <-deadline:
+			t.Fatal("expected every worker to start")
+		case <-time.After(time.Millisecond):
+		}
+
+		running = 0
+		for _, info := range s.ListWorkers() {
+			if info.Running {
+				running++
+			}
+		}
+	}
+	close(release)
+
+	<-time.After(time.Millisecond * 50)
+	s.Stop()
+	s.Wait()
+
+	if a, b, c := atomic.LoadInt32(&first), atomic.LoadInt32(&failing), atomic.LoadInt32(&last); a != 1 || b != 2 || c != 2 {
+		t.Error("unexpected restarts for rest_for_one", a, b, c)
+	}
+}
+
+func Test_SupervisorMustAddAndRemoveWorkers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := NewSupervisorWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	worker := func(ctx context.Context, done chan struct{}) {
+		defer close(done)
+		<-ctx.Done()
+	}
+
+	if err := s.AddWorker(WorkerSpec{Name: "added", Worker: worker, Count: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.AddWorker(WorkerSpec{Name: "added", Worker: worker}); !errors.Is(err, ErrDuplicateName) {
+		t.Error("expected a worker with a duplicate name to be rejected", err)
+	}
+
+	<-time.After(time.Millisecond * 20)
+	if infos := s.WorkerInfo("added"); len(infos) != 2 || !infos[0].Running || !infos[1].Running {
+		t.Error("expected the added worker to be started", infos)
+	}
+
+	if !s.RemoveWorker("added") || s.RemoveWorker("added") {
+		t.Error("expected the worker to be removed exactly once")
+	}
+
+	if infos := s.WorkerInfo("added"); len(infos) != 0 {
+		t.Error("expected the removed worker to be gone", infos)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+// benchmarkRestarts measures the overhead of supervising workers which fail
+// immediately upon starting, across the given number of instances; each op
+// is a single run of an instance, followed by its restart.
+func benchmarkRestarts(b *testing.B, instances int) {
+	var (
+		remaining = int64(b.N)
+		once      sync.Once
+		finished  = make(chan struct{})
+	)
+
+	s, err := NewSupervisorWithOptions(&Options{Specs: []WorkerSpec{{
+		Name:  "crashy",
+		Count: instances,
+		Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+
+			if atomic.AddInt64(&remaining, -1) < 0 {
+				once.Do(func() { close(finished) })
+				<-ctx.Done()
+				return
+			}
+
+			ReportError(ctx, errTest)
+		},
+	}}})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	s.Run()
+	<-finished
+
+	b.StopTimer()
+	s.Stop()
+	s.Wait()
+}
+
+func BenchmarkRestartSingleWorker(b *testing.B) {
+	benchmarkRestarts(b, 1)
+}
+
+func BenchmarkRestartManyWorkers(b *testing.B) {
+	benchmarkRestarts(b, 10000)
+}
+
+func BenchmarkStartAndShutdownLargeWorkerSet(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s, err := NewSupervisorWithOptions(&Options{Specs: []WorkerSpec{{
+			Name:  "bulk",
+			Count: 50000,
+			Worker: func(ctx context.Context, done chan struct{}) {
+				defer Recover(ctx, done)
+				<-ctx.Done()
+			},
+		}}})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		s.Run()
+		if err := s.Shutdown(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+		s.Wait()
+	}
+}
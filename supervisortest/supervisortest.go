@@ -0,0 +1,241 @@
+// Package supervisortest provides utilities for testing the supervision
+// behaviour of Supervisables - such as how often they're restarted - by
+// waiting upon the Supervisor's state, rather than sleeping for a duration
+// long enough to hopefully observe it.
+//
+//	func TestWorkerRestartsAfterFailure(t *testing.T) {
+//		h := supervisortest.New(t, supervisor.Options{
+//			Specs: []supervisor.WorkerSpec{{Name: "worker", Worker: worker}},
+//		})
+//
+//		h.WaitRestarts("worker", 2)
+//	}
+package supervisortest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// DefaultTimeout bounds how long the Harness waits for a condition.
+const DefaultTimeout = 5 * time.Second
+
+// pollInterval is how often conditions are checked.
+const pollInterval = time.Millisecond
+
+// Eventually waits for the condition to hold, failing the test should it not
+// do so within the timeout.
+func Eventually(t testing.TB, timeout time.Duration, cond func() bool) {
+	t.Helper()
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("supervisortest: condition not met within %s", timeout)
+		}
+
+		<-poll.C
+	}
+}
+
+// RunUntil runs the Supervisor until the condition holds, and then shuts it
+// down gracefully; the test fails should the condition not hold within the
+// timeout.
+func RunUntil(t testing.TB, s *supervisor.Supervisor, timeout time.Duration, cond func(*supervisor.Supervisor) bool) {
+	t.Helper()
+
+	s.Run()
+	defer shutdown(s, timeout)
+
+	Eventually(t, timeout, func() bool { return cond(s) })
+}
+
+// Restarts returns the number of times the instances of the named worker
+// have been restarted, in total.
+func Restarts(s *supervisor.Supervisor, name string) int {
+	n := 0
+	for _, info := range s.WorkerInfo(name) {
+		n += info.Restarts
+	}
+
+	return n
+}
+
+// WaitRestarts waits for the instances of the named worker to have been
+// restarted at least n times in total.
+func WaitRestarts(t testing.TB, s *supervisor.Supervisor, name string, n int, timeout time.Duration) {
+	t.Helper()
+
+	Eventually(t, timeout, func() bool { return Restarts(s, name) >= n })
+}
+
+// AssertRestarts fails the test should the instances of the named worker
+// not have been restarted exactly n times in total.
+func AssertRestarts(t testing.TB, s *supervisor.Supervisor, name string, n int) {
+	t.Helper()
+
+	if restarts := Restarts(s, name); restarts != n {
+		t.Errorf("supervisortest: expected %q to have restarted %d times, but it restarted %d", name, n, restarts)
+	}
+}
+
+// WaitReady waits for every worker of the Supervisor to report itself ready.
+func WaitReady(t testing.TB, s *supervisor.Supervisor, timeout time.Duration) {
+	t.Helper()
+
+	Eventually(t, timeout, s.Ready)
+}
+
+// Recorder is a supervisor.Metrics recording each restart, allowing tests
+// to wait upon them as events.
+type Recorder struct {
+	next supervisor.Metrics
+
+	mu       sync.Mutex
+	restarts []supervisor.WorkerInfo
+	changed  chan struct{}
+}
+
+// NewRecorder returns a Recorder, which also notifies the given Metrics of
+// each restart should it not be nil.
+func NewRecorder(next supervisor.Metrics) *Recorder {
+	return &Recorder{next: next, changed: make(chan struct{})}
+}
+
+// WorkerRestarted records the restart.
+func (r *Recorder) WorkerRestarted(info supervisor.WorkerInfo) {
+	r.mu.Lock()
+	r.restarts = append(r.restarts, info)
+	close(r.changed)
+	r.changed = make(chan struct{})
+	r.mu.Unlock()
+
+	if r.next != nil {
+		r.next.WorkerRestarted(info)
+	}
+}
+
+// Restarts returns every restart recorded, in the order they occurred.
+func (r *Recorder) Restarts() []supervisor.WorkerInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]supervisor.WorkerInfo(nil), r.restarts...)
+}
+
+// Await waits for the nth restart to be recorded - counting from 1 -
+// returning it; the test fails should it not occur within the timeout.
+func (r *Recorder) Await(t testing.TB, n int, timeout time.Duration) supervisor.WorkerInfo {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		r.mu.Lock()
+		restarts, changed := r.restarts, r.changed
+		r.mu.Unlock()
+
+		if len(restarts) >= n {
+			return restarts[n-1]
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			t.Fatalf("supervisortest: restart %d not recorded within %s", n, timeout)
+		}
+	}
+}
+
+// Harness runs a Supervisor for the duration of a test, under a FakeClock
+// and with a Recorder, shutting it down once the test completes. As time
+// only passes upon the Clock being advanced, restart delays and schedules
+// are under the test's control.
+type Harness struct {
+	T          testing.TB
+	Supervisor *supervisor.Supervisor
+	Clock      *supervisor.FakeClock
+	Recorder   *Recorder
+}
+
+// New returns a Harness running a Supervisor built from the options; its
+// Clock is a FakeClock - unless the options give another, in which case the
+// Harness's Clock is nil - and any Metrics they give are notified via the
+// Recorder. The test fails should the options be invalid.
+func New(t testing.TB, opts supervisor.Options) *Harness {
+	t.Helper()
+
+	h := &Harness{T: t, Recorder: NewRecorder(opts.Metrics)}
+	if opts.Clock == nil {
+		h.Clock = supervisor.NewFakeClock(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+		opts.Clock = h.Clock
+	}
+	opts.Metrics = h.Recorder
+
+	s, err := supervisor.NewSupervisorWithOptions(&opts)
+	if err != nil {
+		t.Fatalf("supervisortest: invalid options: %v", err)
+	}
+
+	h.Supervisor = s
+	s.Run()
+	t.Cleanup(func() { shutdown(s, DefaultTimeout) })
+
+	return h
+}
+
+// Advance waits for at least n timers to be pending upon the Clock, such as
+// a worker waiting to be restarted, and then advances it by the duration.
+func (h *Harness) Advance(n int, d time.Duration) {
+	h.T.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	if err := h.Clock.BlockUntil(ctx, n); err != nil {
+		h.T.Fatalf("supervisortest: %d timers not pending within %s", n, DefaultTimeout)
+	}
+
+	h.Clock.Advance(d)
+}
+
+// WaitRestarts waits for the named worker to have restarted n times; see
+// WaitRestarts.
+func (h *Harness) WaitRestarts(name string, n int) {
+	h.T.Helper()
+
+	WaitRestarts(h.T, h.Supervisor, name, n, DefaultTimeout)
+}
+
+// AssertRestarts asserts the named worker has restarted n times; see
+// AssertRestarts.
+func (h *Harness) AssertRestarts(name string, n int) {
+	h.T.Helper()
+
+	AssertRestarts(h.T, h.Supervisor, name, n)
+}
+
+// WaitReady waits for every worker to report itself ready.
+func (h *Harness) WaitReady() {
+	h.T.Helper()
+
+	WaitReady(h.T, h.Supervisor, DefaultTimeout)
+}
+
+// shutdown shuts the Supervisor down gracefully, and waits for its workers
+// to exit.
+func shutdown(s *supervisor.Supervisor, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s.Shutdown(ctx)
+	s.Wait()
+}
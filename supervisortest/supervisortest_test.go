@@ -0,0 +1,57 @@
+package supervisortest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+func failing(ctx context.Context, done chan struct{}) {
+	defer supervisor.Recover(ctx, done)
+
+	supervisor.ReportError(ctx, errors.New("failed"))
+}
+
+func Test_HarnessMustControlRestarts(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("harness", func(t *testing.T) {
+		h := New(t, supervisor.Options{
+			Specs: []supervisor.WorkerSpec{{Name: "failing", Worker: failing}},
+			Policy: supervisor.RestartPolicy{
+				Backoff: supervisor.Backoff{Initial: time.Minute},
+			},
+		})
+
+		// Each restart waits a minute upon the fake clock.
+		h.Recorder.Await(t, 1, DefaultTimeout)
+		h.AssertRestarts("failing", 1)
+
+		h.Advance(1, time.Minute)
+		h.WaitRestarts("failing", 2)
+
+		if info := h.Recorder.Await(t, 2, DefaultTimeout); info.Name != "failing" {
+			t.Error("unexpected restart recorded", info)
+		}
+	})
+}
+
+func Test_RunUntilMustStopOnceConditionHolds(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := supervisor.NewSimpleSupervisor(context.Background(), func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		supervisor.Ready(ctx)
+		<-ctx.Done()
+	})
+
+	RunUntil(t, s, DefaultTimeout, (*supervisor.Supervisor).Ready)
+	if !s.HasStopped() {
+		t.Error("expected the supervisor to be stopped")
+	}
+}
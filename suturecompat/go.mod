@@ -0,0 +1,15 @@
+module go.fergus.london/go-supervise/suturecompat
+
+go 1.18
+
+require (
+	github.com/thejerf/suture/v4 v4.0.6
+	go.fergus.london/go-supervise v0.1.0
+	go.uber.org/goleak v1.3.0
+)
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
+// The root module is replaced whilst developing within this repository;
+// the replacement is ignored by modules which depend upon this one.
+replace go.fergus.london/go-supervise => ../
@@ -0,0 +1,80 @@
+// Package suturecompat adapts between this module's Supervisable and the
+// Service interface of github.com/thejerf/suture, so that services written
+// for suture can be supervised by a Supervisor - and vice versa - easing a
+// migration between the two.
+//
+//	s.AddWorker(suturecompat.Spec("indexer", &Indexer{}))
+//
+//	tree := suture.NewSimple("root")
+//	tree.Add(suturecompat.Service("billing", billingWorker))
+package suturecompat
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thejerf/suture/v4"
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrServiceReturned is reported when a suture.Service returns without an
+// error, as suture would restart it.
+var ErrServiceReturned = errors.New("suturecompat: service returned")
+
+// Supervisable adapts a suture.Service to a Supervisable, following suture's
+// conventions for the errors it returns:
+//
+//   - suture.ErrDoNotRestart leaves the service stopped until the worker's
+//     context is cancelled, so it isn't restarted;
+//   - suture.ErrTerminateSupervisorTree exits normally, which stops the
+//     Supervisor should the worker be Significant; see Spec;
+//   - any other error, or none, is reported as the reason for the exit, upon
+//     which the service is restarted.
+func Supervisable(svc suture.Service) supervisor.Supervisable {
+	return func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		supervisor.Ready(ctx)
+		err := svc.Serve(ctx)
+
+		switch {
+		case ctx.Err() != nil:
+		case errors.Is(err, suture.ErrDoNotRestart):
+			<-ctx.Done()
+		case errors.Is(err, suture.ErrTerminateSupervisorTree):
+		case err == nil:
+			supervisor.ReportError(ctx, ErrServiceReturned)
+		default:
+			supervisor.ReportError(ctx, err)
+		}
+	}
+}
+
+// Spec returns a Significant WorkerSpec for the suture.Service, so that it
+// may terminate the Supervisor by returning suture.ErrTerminateSupervisorTree
+// as it would a suture.Supervisor.
+func Spec(name string, svc suture.Service) supervisor.WorkerSpec {
+	return supervisor.WorkerSpec{Name: name, Worker: Supervisable(svc), Significant: true}
+}
+
+// Service adapts a Supervisable to a suture.Service, named for suture's
+// logging. Serve runs the Supervisable until it exits, returning the error
+// it reported - or one wrapping supervisor.ErrWorkerPanicked; see
+// supervisor.ToFunc. A Supervisor can be run as a suture.Service via
+// Supervisor.AsSupervisable.
+func Service(name string, s supervisor.Supervisable) suture.Service {
+	return &service{name: name, serve: supervisor.ToFunc(s)}
+}
+
+type service struct {
+	name  string
+	serve func(context.Context) error
+}
+
+func (s *service) Serve(ctx context.Context) error {
+	return s.serve(ctx)
+}
+
+func (s *service) String() string {
+	return s.name
+}
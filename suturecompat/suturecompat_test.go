@@ -0,0 +1,86 @@
+package suturecompat
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thejerf/suture/v4"
+	supervisor "go.fergus.london/go-supervise"
+	"go.uber.org/goleak"
+)
+
+type serviceFunc func(ctx context.Context) error
+
+func (f serviceFunc) Serve(ctx context.Context) error { return f(ctx) }
+
+func Test_SupervisableMustFollowSutureConventions(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	nCalls := int32(0)
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{
+			Name: "service",
+			Worker: Supervisable(serviceFunc(func(ctx context.Context) error {
+				if atomic.AddInt32(&nCalls, 1) < 3 {
+					return errors.New("failed")
+				}
+				return suture.ErrDoNotRestart
+			})),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 100)
+	if n := atomic.LoadInt32(&nCalls); n != 3 {
+		t.Error("expected the service to be restarted until it returned ErrDoNotRestart", n)
+	}
+
+	s.Stop()
+	<-time.After(time.Millisecond * 50)
+}
+
+func Test_SpecMustTerminateTheSupervisor(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{Spec("service", serviceFunc(func(ctx context.Context) error {
+			return suture.ErrTerminateSupervisorTree
+		}))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	<-time.After(time.Millisecond * 50)
+	if !s.HasStopped() {
+		t.Error("expected the Supervisor to have stopped")
+	}
+}
+
+func Test_ServiceMustRunUnderSuture(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	runs := make(chan struct{}, 10)
+	tree := suture.NewSimple("root")
+	tree.Add(Service("worker", func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		runs <- struct{}{}
+		panic("testing")
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := tree.ServeBackground(ctx)
+
+	<-runs
+	<-runs
+	cancel()
+	<-errs
+}
@@ -0,0 +1,130 @@
+// Package systemd integrates a Supervisor with systemd's service
+// notification protocol, allowing supervised services to be run as
+// `Type=notify` units and make use of `WatchdogSec`.
+package systemd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// ErrNoSocket is returned when the process was not started by systemd with
+// a notification socket.
+var ErrNoSocket = errors.New("systemd: NOTIFY_SOCKET is not set")
+
+// readinessInterval is how often the Supervisor is polled for readiness.
+const readinessInterval = 100 * time.Millisecond
+
+// Notify sends the given state - such as "READY=1" - to systemd via the
+// socket specified by $NOTIFY_SOCKET.
+func Notify(state string) error {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return ErrNoSocket
+	}
+
+	if strings.HasPrefix(path, "@") {
+		path = "\x00" + path[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// WatchdogInterval returns the interval configured via `WatchdogSec`, or
+// zero if the watchdog isn't enabled for this process.
+func WatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond
+}
+
+// Watch notifies systemd of the Supervisor's state: "READY=1" is sent once
+// every worker has reported itself ready via supervisor.Ready, and
+// "STOPPING=1" once the Supervisor begins to stop. If the watchdog is
+// enabled then "WATCHDOG=1" is sent at half the watchdog interval, provided
+// that every worker which sends heartbeats has done so within the interval;
+// a stalled worker therefore results in systemd restarting the service.
+//
+// Watch blocks until the Supervisor begins to stop or the context is
+// cancelled, and is typically run in its own goroutine.
+func Watch(ctx context.Context, s *supervisor.Supervisor) error {
+	if os.Getenv("NOTIFY_SOCKET") == "" {
+		return ErrNoSocket
+	}
+
+	clock := s.Clock()
+	readiness := clock.NewTimer(readinessInterval)
+	defer readiness.Stop()
+
+	var (
+		watchdog supervisor.Timer
+		tick     <-chan time.Time
+	)
+	interval := WatchdogInterval()
+	if interval > 0 {
+		watchdog = clock.NewTimer(interval / 2)
+		defer watchdog.Stop()
+		tick = watchdog.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.Stopping():
+			return Notify("STOPPING=1")
+		case <-readiness.C():
+			if !s.Ready() {
+				readiness.Reset(readinessInterval)
+				continue
+			}
+
+			if err := Notify("READY=1"); err != nil {
+				return err
+			}
+		case <-tick:
+			watchdog.Reset(interval / 2)
+			if !healthy(s, clock, interval) {
+				continue
+			}
+
+			if err := Notify("WATCHDOG=1"); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// healthy returns whether every worker which sends heartbeats has done so
+// within the given interval, as measured upon the Supervisor's Clock.
+func healthy(s *supervisor.Supervisor, clock supervisor.Clock, interval time.Duration) bool {
+	now := clock.Now()
+	for _, info := range s.ListWorkers() {
+		if !info.LastHeartbeat.IsZero() && now.Sub(info.LastHeartbeat) > interval {
+			return false
+		}
+	}
+
+	return true
+}
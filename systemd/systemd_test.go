@@ -0,0 +1,150 @@
+package systemd
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+func listen(t *testing.T) *net.UnixConn {
+	dir, err := ioutil.TempDir("", "systemd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	os.Setenv("NOTIFY_SOCKET", path)
+	os.Setenv("WATCHDOG_USEC", "100000")
+	t.Cleanup(func() {
+		os.Unsetenv("NOTIFY_SOCKET")
+		os.Unsetenv("WATCHDOG_USEC")
+	})
+
+	return conn
+}
+
+func receive(conn *net.UnixConn) []string {
+	states := []string{}
+	buf := make([]byte, 64)
+	for {
+		conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return states
+		}
+		states = append(states, string(buf[:n]))
+	}
+}
+
+func Test_WatchMustNotifyReadinessWatchdogAndStopping(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	conn := listen(t)
+
+	s := supervisor.NewSimpleSupervisor(context.Background(), func(ctx context.Context, done chan struct{}) {
+		defer supervisor.Recover(ctx, done)
+
+		supervisor.Ready(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(20 * time.Millisecond):
+				supervisor.Heartbeat(ctx)
+			}
+		}
+	})
+	s.Run()
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- Watch(context.Background(), s)
+	}()
+
+	<-time.After(time.Millisecond * 250)
+	s.Stop()
+
+	if err := <-errCh; err != nil {
+		t.Error("unexpected error from Watch", err)
+	}
+	s.Wait()
+
+	counts := map[string]int{}
+	for _, state := range receive(conn) {
+		counts[state]++
+	}
+
+	if counts["READY=1"] != 1 || counts["STOPPING=1"] != 1 || counts["WATCHDOG=1"] < 2 {
+		t.Error("unexpected notifications sent", counts)
+	}
+}
+
+func Test_WatchMustReturnErrorWithoutSocket(t *testing.T) {
+	s := supervisor.NewSimpleSupervisor(context.Background(), nil)
+	if err := Watch(context.Background(), s); err != ErrNoSocket {
+		t.Error("expected ErrNoSocket", err)
+	}
+}
+
+func Test_WatchdogMustFollowTheSupervisorsClock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	conn := listen(t)
+
+	clock := supervisor.NewFakeClock(time.Now())
+	s, err := supervisor.NewSupervisorWithOptions(&supervisor.Options{
+		Specs: []supervisor.WorkerSpec{{Name: "stalled", Worker: func(ctx context.Context, done chan struct{}) {
+			defer supervisor.Recover(ctx, done)
+
+			supervisor.Ready(ctx)
+			supervisor.Heartbeat(ctx)
+			<-ctx.Done()
+		}}},
+		Clock: clock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Run()
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- Watch(context.Background(), s)
+	}()
+
+	// The worker's heartbeat is recent upon the first tick of the watchdog,
+	// but stale by the second.
+	<-time.After(time.Millisecond * 20)
+	clock.Advance(readinessInterval)
+	<-time.After(time.Millisecond * 20)
+	clock.Advance(time.Second)
+	<-time.After(time.Millisecond * 20)
+	s.Stop()
+
+	if err := <-errCh; err != nil {
+		t.Error("unexpected error from Watch", err)
+	}
+	s.Wait()
+
+	counts := map[string]int{}
+	for _, state := range receive(conn) {
+		counts[state]++
+	}
+
+	if counts["READY=1"] != 1 || counts["STOPPING=1"] != 1 || counts["WATCHDOG=1"] != 1 {
+		t.Error("unexpected notifications sent", counts)
+	}
+}
@@ -0,0 +1,113 @@
+package supervisor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// DumpTree writes an indented tree of the Supervisor's groups and worker
+// instances to w, along with the state of each instance; nested Supervisors
+// are written beneath the instance which runs them. For example:
+//
+//	supervisor "api" running
+//	  group (default) one_for_one
+//	    http[0] ready uptime=1m2s attempt=1 restarts=0
+//	    db[0] backing_off attempt=3 restarts=2 next_restart=in 4s last_exit="dial failed"
+//	  group "jobs" quarantined
+//	    ...
+//
+// It's gathered without the Supervisor's lock held whilst writing, so it's
+// suitable for printing upon SIGQUIT or from a crash handler; see
+// DumpAction.
+func (s *Supervisor) DumpTree(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	s.dumpTree(out, "")
+	return out.Flush()
+}
+
+// String returns the tree written by DumpTree.
+func (s *Supervisor) String() string {
+	var b strings.Builder
+	s.DumpTree(&b)
+	return b.String()
+}
+
+func (s *Supervisor) dumpTree(w io.Writer, indent string) {
+	s.mu.Lock()
+	name, groups := s.name, append([]*group(nil), s.groups...)
+	s.mu.Unlock()
+
+	label := "supervisor"
+	if name != "" {
+		label = fmt.Sprintf("supervisor %q", name)
+	}
+	fmt.Fprintf(w, "%s%s %s\n", indent, label, s.treeState(groups[0]))
+
+	workers := s.snapshotWorkers()
+	now := ClockFrom(s.parent).Now()
+	for _, g := range groups {
+		g.mu.Lock()
+		state := g.policy.Strategy.String()
+		if g.quarantined {
+			state = "quarantined"
+		}
+		g.mu.Unlock()
+
+		label := "(default)"
+		if g.name != "" {
+			label = fmt.Sprintf("%q", g.name)
+		}
+		fmt.Fprintf(w, "%s  group %s %s\n", indent, label, state)
+
+		for _, wk := range workers {
+			if wk.group != g {
+				continue
+			}
+
+			fmt.Fprintf(w, "%s    %s\n", indent, formatStatus(wk.status(), now))
+			if wk.child != nil {
+				wk.child.dumpTree(w, indent+"      ")
+			}
+		}
+	}
+}
+
+// treeState describes the Supervisor's lifecycle in a tree.
+func (s *Supervisor) treeState(defaultGroup *group) string {
+	select {
+	case <-s.Stopping():
+		if s.HasStopped() {
+			return "stopped"
+		}
+		return "stopping"
+	default:
+	}
+
+	if defaultGroup.context() == nil {
+		return "not_started"
+	}
+
+	return "running"
+}
+
+func formatStatus(status WorkerStatus, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s[%d] %s", status.Name, status.Instance, status.State)
+	if status.Uptime > 0 {
+		fmt.Fprintf(&b, " uptime=%s", status.Uptime.Round(time.Millisecond))
+	}
+
+	fmt.Fprintf(&b, " attempt=%d restarts=%d", status.Attempt, status.Restarts)
+	if !status.NextRestart.IsZero() {
+		fmt.Fprintf(&b, " next_restart=in %s", status.NextRestart.Sub(now).Round(time.Millisecond))
+	}
+
+	if status.LastExit != nil && status.LastExit.Reason != nil {
+		fmt.Fprintf(&b, " last_exit=%q", fmt.Sprint(status.LastExit.Reason))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func Test_DumpTreeMustDescribeNestedSupervisors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	waiting := func(ctx context.Context, done chan struct{}) {
+		defer Recover(ctx, done)
+		<-ctx.Done()
+	}
+
+	child, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{{Name: "leaf", Worker: func(ctx context.Context, done chan struct{}) {
+			defer Recover(ctx, done)
+			Ready(ctx)
+			<-ctx.Done()
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSupervisorWithOptions(&Options{
+		Specs: []WorkerSpec{
+			{Name: "api", Worker: waiting},
+			{Name: "child", Child: child},
+		},
+		Groups: []Group{{
+			Name:    "jobs",
+			Workers: []WorkerSpec{{Name: "failing", Worker: failingWorker}},
+			Policy:  RestartPolicy{Backoff: Backoff{Initial: time.Hour}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.WithName("tree"); err != nil {
+		t.Fatal(err)
+	}
+	defer s.WithName("")
+
+	if !strings.HasPrefix(s.String(), `supervisor "tree" not_started`) {
+		t.Error("expected the Supervisor to not have been started", s.String())
+	}
+
+	s.Run()
+	<-time.After(time.Millisecond * 50)
+
+	dump := &bytes.Buffer{}
+	if err := s.DumpTree(dump); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(dump.String()), "\n")
+	expected := []string{
+		`supervisor "tree" running`,
+		`  group (default) one_for_one`,
+		`    api[0] running uptime=`,
+		`    child[0] running uptime=`,
+		`      supervisor running`,
+		`        group (default) one_for_one`,
+		`          leaf[0] ready uptime=`,
+		`  group "jobs" one_for_one`,
+		`    failing[0] backing_off attempt=1 restarts=1 next_restart=in `,
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatal("expected a line for each supervisor, group and instance", dump.String())
+	}
+
+	for i, prefix := range expected {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("expected line %d to begin with %q, got %q", i, prefix, lines[i])
+		}
+	}
+
+	if !strings.HasSuffix(lines[len(lines)-1], `last_exit="failed"`) {
+		t.Error("expected the last exit to be described", lines[len(lines)-1])
+	}
+
+	s.Stop()
+	s.Wait()
+	<-time.After(time.Millisecond * 50)
+
+	if !strings.HasPrefix(s.String(), `supervisor "tree" stopped`) {
+		t.Error("expected the Supervisor to have stopped", s.String())
+	}
+}
@@ -0,0 +1,213 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNilWorker is returned when a worker has no Supervisable to execute.
+	ErrNilWorker = errors.New("supervisor: nil worker")
+	// ErrInvalidCount is returned when a worker's instance count is
+	// negative.
+	ErrInvalidCount = errors.New("supervisor: invalid worker count")
+	// ErrUnnamedWorker is returned when a WorkerSpec has no Name.
+	ErrUnnamedWorker = errors.New("supervisor: unnamed worker")
+	// ErrDuplicateName is returned when two workers, or two groups, share
+	// the same name.
+	ErrDuplicateName = errors.New("supervisor: duplicate name")
+	// ErrInvalidChild is returned when a worker with a nested Supervisor
+	// also specifies a Supervisable, or more than a single instance.
+	ErrInvalidChild = errors.New("supervisor: invalid nested supervisor")
+	// ErrInvalidPolicy is returned when a RestartPolicy is contradictory or
+	// out of range.
+	ErrInvalidPolicy = errors.New("supervisor: invalid restart policy")
+)
+
+// validate checks Options for mistakes which would otherwise only become
+// apparent once the Supervisor is running. A WorkerCount, or Count, of zero
+// is permitted and denotes a single instance.
+func (opts *Options) validate() error {
+	if opts.WorkerCount < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidCount, opts.WorkerCount)
+	}
+
+	for _, w := range opts.Workers {
+		if w == nil {
+			return ErrNilWorker
+		}
+	}
+
+	if err := validatePolicy("", opts.Policy); err != nil {
+		return err
+	}
+
+	names := newNameSet(specsFromWorkers(opts.Workers, opts.WorkerCount))
+	if err := names.addSpecs(opts.Specs); err != nil {
+		return err
+	}
+
+	groups := map[string]bool{"": true}
+	for _, g := range opts.Groups {
+		if groups[g.Name] {
+			return fmt.Errorf("%w: group %q", ErrDuplicateName, g.Name)
+		}
+		groups[g.Name] = true
+
+		if err := validatePolicy(g.Name, g.Policy); err != nil {
+			return err
+		}
+
+		if err := names.addSpecs(g.Workers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nameSet tracks the names of workers across every group of a Supervisor,
+// as workers are addressed by name alone.
+type nameSet map[string]bool
+
+func newNameSet(specs []WorkerSpec) nameSet {
+	names := nameSet{}
+	for _, spec := range specs {
+		names[spec.Name] = true
+	}
+
+	return names
+}
+
+func (names nameSet) add(name string) error {
+	if name == "" {
+		return ErrUnnamedWorker
+	}
+
+	if names[name] {
+		return fmt.Errorf("%w: worker %q", ErrDuplicateName, name)
+	}
+
+	names[name] = true
+	return nil
+}
+
+func (names nameSet) addSpecs(specs []WorkerSpec) error {
+	for _, spec := range specs {
+		if err := names.add(spec.Name); err != nil {
+			return err
+		}
+
+		if err := validateSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateSpec(spec WorkerSpec) error {
+	if spec.Count < 0 {
+		return fmt.Errorf("%w: %q has a count of %d", ErrInvalidCount, spec.Name, spec.Count)
+	}
+
+	if spec.Child == nil {
+		if spec.Worker == nil {
+			return fmt.Errorf("%w: %q", ErrNilWorker, spec.Name)
+		}
+
+		return nil
+	}
+
+	if spec.Worker != nil {
+		return fmt.Errorf("%w: %q has both a Worker and a Child", ErrInvalidChild, spec.Name)
+	}
+
+	if spec.Count > 1 {
+		return fmt.Errorf("%w: %q has a count of %d", ErrInvalidChild, spec.Name, spec.Count)
+	}
+
+	return nil
+}
+
+// validatePolicy rejects negative values, along with a Period which has no
+// MaxRestarts to apply to and a Backoff capped below its initial delay.
+func validatePolicy(group string, p RestartPolicy) error {
+	switch {
+	case p.MaxRestarts < 0:
+		return fmt.Errorf("%w: group %q has negative MaxRestarts", ErrInvalidPolicy, group)
+	case p.Period < 0 || p.Backoff.Initial < 0 || p.Backoff.Max < 0 || p.Backoff.Multiplier < 0:
+		return fmt.Errorf("%w: group %q has a negative duration", ErrInvalidPolicy, group)
+	case p.Period > 0 && p.MaxRestarts == 0:
+		return fmt.Errorf("%w: group %q has a Period but no MaxRestarts", ErrInvalidPolicy, group)
+	case p.Backoff.Max > 0 && p.Backoff.Max < p.Backoff.Initial:
+		return fmt.Errorf("%w: group %q has a Backoff.Max below Backoff.Initial", ErrInvalidPolicy, group)
+	case p.Strategy < OneForOne || p.Strategy > RestForOne:
+		return fmt.Errorf("%w: group %q has an unknown Strategy", ErrInvalidPolicy, group)
+	case p.Escalation < StopGroup || p.Escalation > StopSupervisor:
+		return fmt.Errorf("%w: group %q has an unknown Escalation", ErrInvalidPolicy, group)
+	}
+
+	return nil
+}
+
+// validate checks a Config in the same manner as Options.validate, but
+// without constructing any of its workers.
+func (cfg *Config) validate() error {
+	if err := validatePolicy("", cfg.Policy.RestartPolicy()); err != nil {
+		return err
+	}
+
+	names := nameSet{}
+	if err := names.addConfigs(cfg.Workers); err != nil {
+		return err
+	}
+
+	groups := map[string]bool{"": true}
+	for _, g := range cfg.Groups {
+		if groups[g.Name] {
+			return fmt.Errorf("%w: group %q", ErrDuplicateName, g.Name)
+		}
+		groups[g.Name] = true
+
+		if err := validatePolicy(g.Name, g.Policy.RestartPolicy()); err != nil {
+			return err
+		}
+
+		if err := names.addConfigs(g.Workers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (names nameSet) addConfigs(workers []WorkerConfig) error {
+	for _, wc := range workers {
+		if err := names.add(wc.Name); err != nil {
+			return err
+		}
+
+		if wc.Count < 0 {
+			return fmt.Errorf("%w: %q has a count of %d", ErrInvalidCount, wc.Name, wc.Count)
+		}
+
+		if wc.Supervisor == nil {
+			continue
+		}
+
+		if wc.Factory != "" {
+			return fmt.Errorf("%w: %q has both a factory and a supervisor", ErrInvalidChild, wc.Name)
+		}
+
+		if wc.Count > 1 {
+			return fmt.Errorf("%w: %q has a count of %d", ErrInvalidChild, wc.Name, wc.Count)
+		}
+
+		if err := wc.Supervisor.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
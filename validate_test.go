@@ -0,0 +1,108 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_OptionsMustBeValidated(t *testing.T) {
+	worker := func(ctx context.Context, done chan struct{}) { close(done) }
+	child := NewSimpleSupervisor(context.Background(), worker)
+
+	cases := map[string]struct {
+		opts *Options
+		err  error
+	}{
+		"nil worker": {
+			opts: &Options{Workers: []Supervisable{nil}},
+			err:  ErrNilWorker,
+		},
+		"nil spec worker": {
+			opts: &Options{Specs: []WorkerSpec{{Name: "a"}}},
+			err:  ErrNilWorker,
+		},
+		"negative worker count": {
+			opts: &Options{Workers: []Supervisable{worker}, WorkerCount: -1},
+			err:  ErrInvalidCount,
+		},
+		"negative spec count": {
+			opts: &Options{Specs: []WorkerSpec{{Name: "a", Worker: worker, Count: -2}}},
+			err:  ErrInvalidCount,
+		},
+		"unnamed spec": {
+			opts: &Options{Specs: []WorkerSpec{{Worker: worker}}},
+			err:  ErrUnnamedWorker,
+		},
+		"duplicate worker across groups": {
+			opts: &Options{
+				Specs:  []WorkerSpec{{Name: "a", Worker: worker}},
+				Groups: []Group{{Name: "g", Workers: []WorkerSpec{{Name: "a", Worker: worker}}}},
+			},
+			err: ErrDuplicateName,
+		},
+		"duplicate generated name": {
+			opts: &Options{
+				Workers: []Supervisable{worker},
+				Specs:   []WorkerSpec{{Name: "worker-0", Worker: worker}},
+			},
+			err: ErrDuplicateName,
+		},
+		"duplicate group": {
+			opts: &Options{Groups: []Group{{Name: "g"}, {Name: "g"}}},
+			err:  ErrDuplicateName,
+		},
+		"child with worker": {
+			opts: &Options{Specs: []WorkerSpec{{Name: "a", Worker: worker, Child: child}}},
+			err:  ErrInvalidChild,
+		},
+		"child with multiple instances": {
+			opts: &Options{Specs: []WorkerSpec{{Name: "a", Child: child, Count: 2}}},
+			err:  ErrInvalidChild,
+		},
+		"period without max restarts": {
+			opts: &Options{Policy: RestartPolicy{Period: time.Minute}},
+			err:  ErrInvalidPolicy,
+		},
+		"backoff max below initial": {
+			opts: &Options{Groups: []Group{{Name: "g", Policy: RestartPolicy{
+				Backoff: Backoff{Initial: time.Second, Max: time.Millisecond},
+			}}}},
+			err: ErrInvalidPolicy,
+		},
+		"unknown strategy": {
+			opts: &Options{Policy: RestartPolicy{Strategy: Strategy(7)}},
+			err:  ErrInvalidPolicy,
+		},
+		"valid": {
+			opts: &Options{
+				Workers: []Supervisable{worker},
+				Specs:   []WorkerSpec{{Name: "a", Worker: worker, Count: 2}, {Name: "b", Child: child}},
+				Policy:  RestartPolicy{MaxRestarts: 3, Period: time.Minute},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		s, err := NewSupervisorWithOptions(tc.opts)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected error %v, got %v", name, tc.err, err)
+		}
+
+		if (s == nil) == (tc.err == nil) {
+			t.Errorf("%s: expected a Supervisor only when valid", name)
+		}
+	}
+}
+
+func Test_ConfigMustBeValidatedBeforeBuilding(t *testing.T) {
+	cfg := &Config{Workers: []WorkerConfig{
+		{Name: "a", Factory: "missing"},
+		{Name: "a", Factory: "missing"},
+	}}
+
+	if _, err := NewSupervisorFromConfig(context.Background(), cfg); !errors.Is(err, ErrDuplicateName) {
+		t.Fatal("expected duplicate names to be rejected before factories are resolved", err)
+	}
+}
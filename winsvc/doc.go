@@ -0,0 +1,8 @@
+// Package winsvc runs a Supervisor as a Windows service, translating
+// requests from the Service Control Manager into calls upon the Supervisor:
+// stop and shutdown requests result in a graceful Shutdown, whilst pause
+// and continue requests map to Pause and Resume respectively.
+//
+// This is the Windows counterpart to the signal handling provided by the
+// supervisor package, and is only available when building for Windows.
+package winsvc
@@ -0,0 +1,82 @@
+//go:build windows
+// +build windows
+
+package winsvc
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+
+	supervisor "go.fergus.london/go-supervise"
+)
+
+// accepted are the control requests the service responds to.
+const accepted = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptPauseAndContinue
+
+// IsService returns whether the process is running as a Windows service.
+func IsService() (bool, error) {
+	return svc.IsWindowsService()
+}
+
+// Run executes the Supervisor as the named Windows service, blocking until
+// the service has stopped. The timeout bounds the graceful Shutdown which
+// is performed upon a stop or shutdown request; zero means no limit.
+func Run(name string, s *supervisor.Supervisor, timeout time.Duration) error {
+	return svc.Run(name, &handler{s: s, timeout: timeout})
+}
+
+// handler implements svc.Handler for a Supervisor.
+type handler struct {
+	s       *supervisor.Supervisor
+	timeout time.Duration
+}
+
+func (h *handler) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	h.s.Run()
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for {
+		select {
+		case <-h.s.Stopping():
+			changes <- svc.Status{State: svc.StopPending}
+			h.s.Wait()
+			return false, 0
+
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				changes <- c.CurrentStatus
+
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				h.shutdown()
+				return false, 0
+
+			case svc.Pause:
+				changes <- svc.Status{State: svc.PausePending}
+				h.s.Pause()
+				changes <- svc.Status{State: svc.Paused, Accepts: accepted}
+
+			case svc.Continue:
+				changes <- svc.Status{State: svc.ContinuePending}
+				h.s.Resume()
+				changes <- svc.Status{State: svc.Running, Accepts: accepted}
+			}
+		}
+	}
+}
+
+func (h *handler) shutdown() {
+	ctx := context.Background()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	h.s.Shutdown(ctx)
+	h.s.Wait()
+}
@@ -0,0 +1,367 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WorkerSpec describes a named Supervisable, and how many instances of it
+// the Supervisor should run.
+type WorkerSpec struct {
+	// Name identifies the worker in statistics and logging output.
+	Name string
+	// Worker is the Supervisable to execute.
+	Worker Supervisable
+	// Count is the number of instances to run; values below 1 are treated
+	// as a single instance.
+	Count int
+	// Significant workers determine the lifetime of the Supervisor; should
+	// an instance exit normally - that is, without panicking or reporting an
+	// error - then the Supervisor stops all of its workers.
+	Significant bool
+	// ShutdownClass orders the shutdown of workers by Supervisor.Shutdown;
+	// lower classes are stopped first, and every worker in a class must have
+	// stopped before the next class begins.
+	ShutdownClass int
+	// Child, if given, is a nested Supervisor to run in place of Worker;
+	// see Supervisor.AsSupervisable.
+	Child *Supervisor
+
+	// temporary workers are run once, and never restarted; their exits
+	// don't count towards the group's restart budget.
+	temporary bool
+}
+
+// WorkerInfo contains the statistics for a single instance of a worker.
+type WorkerInfo struct {
+	// Name is the name of the WorkerSpec the instance belongs to.
+	Name string
+	// Instance is the index of the instance, starting at 0.
+	Instance int
+	// Running denotes whether the instance is currently executing.
+	Running bool
+	// Restarts is the number of times the instance has been restarted.
+	Restarts int
+	// LastFailure is when the instance last exited unexpectedly.
+	LastFailure time.Time
+	// Uptime is how long the current run of the instance has lasted.
+	Uptime time.Duration
+	// MTBF is the mean time between failures; i.e. the average duration of
+	// a run which ended in the instance being restarted.
+	MTBF time.Duration
+	// Ready denotes whether the current run has reported itself as ready.
+	Ready bool
+	// LastHeartbeat is when the instance last called Heartbeat.
+	LastHeartbeat time.Time
+	// QueueDepth is the number of items queued for the instance, as
+	// reported via ObserveQueue.
+	QueueDepth int
+	// QueueCapacity is the capacity of the instance's queue, as reported
+	// via ObserveQueue.
+	QueueCapacity int
+}
+
+// Metrics is notified of changes to a worker's statistics; it's the
+// extension point for exporting the data from ListWorkers to a monitoring
+// system.
+type Metrics interface {
+	// WorkerRestarted is called after an instance exits unexpectedly, and
+	// before it's restarted.
+	WorkerRestarted(WorkerInfo)
+}
+
+// worker is a single running instance of a WorkerSpec, and holds the
+// statistics for that instance.
+type worker struct {
+	name        string
+	instance    int
+	fn          Supervisable
+	significant bool
+	temporary   bool
+	class       int
+	group       *group
+	child       *Supervisor
+
+	mu            sync.Mutex
+	cancel        context.CancelFunc
+	exited        chan struct{}
+	cancelRun     context.CancelFunc
+	restarted     chan struct{}
+	running       bool
+	ready         bool
+	startedAt     time.Time
+	clock         Clock
+	lastHeartbeat time.Time
+	queue         func() (int, int)
+	restarts      int
+	lastFailure   time.Time
+	failedTime    time.Duration
+	history       *exitHistory
+	state         WorkerState
+	runs          int
+	nextRestart   time.Time
+}
+
+func newWorkers(specs []WorkerSpec, historySize int, g *group) []*worker {
+	workers := []*worker{}
+	for _, spec := range specs {
+		count := spec.Count
+		if count < 1 {
+			count = 1
+		}
+
+		workers = append(workers, newInstances(spec, 0, count, historySize, g)...)
+	}
+
+	return workers
+}
+
+// newInstances creates the instances of a WorkerSpec from index "from" up
+// to, but not including, index "to".
+func newInstances(spec WorkerSpec, from, to, historySize int, g *group) []*worker {
+	fn := spec.Worker
+	if spec.Child != nil {
+		fn = spec.Child.AsSupervisable()
+	}
+
+	workers := []*worker{}
+	for i := from; i < to; i++ {
+		workers = append(workers, &worker{
+			name:        spec.Name,
+			instance:    i,
+			fn:          fn,
+			significant: spec.Significant,
+			temporary:   spec.temporary,
+			class:       spec.ShutdownClass,
+			group:       g,
+			child:       spec.Child,
+			history:     newExitHistory(historySize),
+		})
+	}
+
+	g.addWorkers(workers)
+	return workers
+}
+
+// removeWorkers returns a copy of the workers without those removed, in a
+// single pass.
+func removeWorkers(workers []*worker, removed map[*worker]bool) []*worker {
+	kept := make([]*worker, 0, len(workers))
+	for _, w := range workers {
+		if !removed[w] {
+			kept = append(kept, w)
+		}
+	}
+
+	return kept
+}
+
+// specsFromWorkers converts anonymous Supervisables to WorkerSpecs, naming
+// them by their position.
+func specsFromWorkers(workers []Supervisable, count int) []WorkerSpec {
+	specs := make([]WorkerSpec, len(workers))
+	for i, w := range workers {
+		specs[i] = WorkerSpec{
+			Name:   fmt.Sprintf("worker-%d", i),
+			Worker: w,
+			Count:  count,
+		}
+	}
+
+	return specs
+}
+
+// start derives the context for a new run loop of the instance, along with
+// the channel to close once that loop has exited.
+func (w *worker) start(parent context.Context) (context.Context, chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(parent)
+	w.cancel = cancel
+	w.exited = make(chan struct{})
+	w.state = StateStarting
+	return ctx, w.exited
+}
+
+// stop cancels the run loop of the instance, returning a channel which is
+// closed once it has exited.
+func (w *worker) stop() chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel == nil {
+		exited := make(chan struct{})
+		close(exited)
+		return exited
+	}
+
+	w.cancel()
+	return w.exited
+}
+
+// restart cancels the current run of the instance, causing it to be started
+// again; the returned channel is closed once the new run has begun.
+func (w *worker) restart() chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan struct{})
+	if !w.running {
+		close(ch)
+		return ch
+	}
+
+	if w.restarted == nil {
+		w.restarted = ch
+		w.cancelRun()
+	}
+
+	return w.restarted
+}
+
+// started records the beginning of a new run, returning the context the run
+// should use.
+func (w *worker) started(ctx context.Context, clock Clock) *runContext {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.running = true
+	w.ready = false
+	w.queue = nil
+	w.runs++
+	w.state = StateRunning
+	w.nextRestart = time.Time{}
+	w.clock = clock
+	w.startedAt = w.clock.Now()
+	w.notifyRestartedLocked()
+
+	run := &runContext{w: w, report: exitReport{clock: clock}}
+	run.Context, w.cancelRun = context.WithCancel(ctx)
+	return run
+}
+
+// runContext is the context of a single run of a worker instance, carrying
+// the instance itself and the report of the run's exit. It stands in for a
+// pair of context values, which would otherwise both be allocated upon each
+// restart.
+type runContext struct {
+	context.Context
+	w      *worker
+	report exitReport
+}
+
+func (c *runContext) Value(key interface{}) interface{} {
+	switch key.(type) {
+	case workerKey:
+		return c.w
+	case exitReportKey:
+		return &c.report
+	}
+
+	return c.Context.Value(key)
+}
+
+// restartRequested returns whether the run which has just completed was
+// cancelled by a call to restart.
+func (w *worker) restartRequested() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.cancelRun()
+	if w.restarted == nil {
+		return false
+	}
+
+	w.running = false
+	w.state = StateStarting
+	return true
+}
+
+func (w *worker) stopped() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.running = false
+	w.state = StateStopped
+	w.nextRestart = time.Time{}
+	w.notifyRestartedLocked()
+}
+
+func (w *worker) notifyRestartedLocked() {
+	if w.restarted != nil {
+		close(w.restarted)
+		w.restarted = nil
+	}
+}
+
+func (w *worker) failed(exit Exit) WorkerInfo {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.running = false
+	w.state = StateStarting
+	w.restarts++
+	w.lastFailure = exit.Time
+	w.failedTime += exit.Time.Sub(w.startedAt)
+	w.history.add(exit)
+
+	return w.infoLocked()
+}
+
+// finished records the exit of a temporary instance, which isn't restarted.
+func (w *worker) finished(exit Exit) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if exit.Reason != nil {
+		w.lastFailure = exit.Time
+	}
+	w.history.add(exit)
+}
+
+func (w *worker) exits() []Exit {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.history.list()
+}
+
+// info returns the instance's statistics. Its queue is observed without the
+// instance's lock held, so a slow ObserveQueue function can't block the
+// instance from being restarted.
+func (w *worker) info() WorkerInfo {
+	w.mu.Lock()
+	info, queue := w.infoLocked(), w.queue
+	w.mu.Unlock()
+
+	if info.Running && queue != nil {
+		info.QueueDepth, info.QueueCapacity = queue()
+	}
+
+	return info
+}
+
+func (w *worker) infoLocked() WorkerInfo {
+	info := WorkerInfo{
+		Name:          w.name,
+		Instance:      w.instance,
+		Running:       w.running,
+		Restarts:      w.restarts,
+		LastFailure:   w.lastFailure,
+		Ready:         w.running && w.ready,
+		LastHeartbeat: w.lastHeartbeat,
+	}
+
+	if w.running {
+		info.Uptime = w.clock.Now().Sub(w.startedAt)
+	}
+
+	if w.restarts > 0 {
+		info.MTBF = w.failedTime / time.Duration(w.restarts)
+	}
+
+	return info
+}